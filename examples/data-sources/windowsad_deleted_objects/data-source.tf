@@ -0,0 +1,7 @@
+data "windowsad_deleted_objects" "users" {
+  object_class = "user"
+}
+
+output "recently_deleted_users" {
+  value = [for o in data.windowsad_deleted_objects.users.objects : "${o.name} (${o.deleted_at})"]
+}
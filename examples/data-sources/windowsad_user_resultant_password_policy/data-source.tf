@@ -0,0 +1,10 @@
+data "windowsad_user_resultant_password_policy" "svc" {
+  user_id = "svc-backup"
+}
+
+check "service_account_password_policy" {
+  assert {
+    condition     = data.windowsad_user_resultant_password_policy.svc.password_settings_object_name == "service-accounts"
+    error_message = "svc-backup is not covered by the service-accounts password settings object."
+  }
+}
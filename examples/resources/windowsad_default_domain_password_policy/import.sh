@@ -0,0 +1,2 @@
+# The ID for this resource is the DNS name of the domain
+$ terraform import windowsad_default_domain_password_policy.contoso contoso.com
@@ -0,0 +1,11 @@
+# Only the attributes that are set are managed, the others keep their current value.
+resource "windowsad_default_domain_password_policy" "contoso" {
+  domain                     = "contoso.com"
+  complexity_enabled         = true
+  min_password_length        = 14
+  password_history_count     = 24
+  max_password_age           = "365.00:00:00"
+  lockout_threshold          = 10
+  lockout_duration           = "00:15:00"
+  lockout_observation_window = "00:15:00"
+}
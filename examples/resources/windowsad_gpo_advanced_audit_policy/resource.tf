@@ -0,0 +1,28 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_advanced_audit_policy" "audit" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  subcategory {
+    name    = "Credential Validation"
+    success = true
+    failure = true
+  }
+
+  subcategory {
+    name    = "Logon"
+    success = true
+    failure = true
+  }
+
+  subcategory {
+    name    = "Process Creation"
+    success = true
+  }
+}
@@ -0,0 +1 @@
+$ terraform import windowsad_gpo_preference_drives.drives 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_preferencedrives
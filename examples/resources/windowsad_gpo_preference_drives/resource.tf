@@ -0,0 +1,33 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_preference_drives" "drives" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  drive {
+    letter     = "S"
+    path       = "\\\\fileserver\\share"
+    label      = "Share"
+    persistent = true
+    action     = "replace"
+  }
+
+  drive {
+    letter = "H"
+    path   = "\\\\fileserver\\home\\%USERNAME%"
+    label  = "Home"
+
+    filter {
+      type = "FilterGroup"
+      attributes = {
+        name        = "CONTOSO\\Staff"
+        userContext = "1"
+      }
+    }
+  }
+}
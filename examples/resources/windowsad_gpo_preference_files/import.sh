@@ -0,0 +1,2 @@
+$ terraform import windowsad_gpo_preference_files.files 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_preferencefiles
+$ terraform import windowsad_gpo_preference_files.user 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_userpreferencefiles
@@ -0,0 +1,22 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_preference_files" "files" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  file {
+    source    = "\\\\fileserver\\config\\agent.json"
+    target    = "C:\\ProgramData\\Contoso\\agent.json"
+    read_only = true
+  }
+
+  file {
+    target = "C:\\ProgramData\\Contoso\\legacy.ini"
+    action = "delete"
+  }
+}
@@ -0,0 +1,39 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_preference_groups" "groups" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  group {
+    name              = "Administrators (built-in)"
+    sid               = "S-1-5-32-544"
+    delete_all_users  = true
+    delete_all_groups = true
+
+    member {
+      name = "CONTOSO\\Workstation Admins"
+    }
+  }
+
+  group {
+    name = "Remote Desktop Users (built-in)"
+    sid  = "S-1-5-32-555"
+
+    member {
+      name = "CONTOSO\\Kiosk Operators"
+    }
+
+    filter {
+      type = "FilterGroup"
+      attributes = {
+        name        = "CONTOSO\\Kiosks"
+        userContext = "0"
+      }
+    }
+  }
+}
@@ -0,0 +1,2 @@
+$ terraform import windowsad_gpo_preference_registry.registry 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_preferenceregistry
+$ terraform import windowsad_gpo_preference_registry.user 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_userpreferenceregistry
@@ -0,0 +1,37 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_preference_registry" "registry" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  value {
+    hive       = "HKEY_LOCAL_MACHINE"
+    key        = "SOFTWARE\\Contoso\\Agent"
+    value_name = "Server"
+    value      = "agent.contoso.com"
+  }
+
+  value {
+    hive       = "HKEY_LOCAL_MACHINE"
+    key        = "SOFTWARE\\Contoso\\Agent"
+    value_name = "Port"
+    type       = "REG_DWORD"
+    value      = "8443"
+
+    filter {
+      type = "FilterOs"
+      attributes = {
+        class   = "NT"
+        version = "WIN10"
+        type    = "NE"
+        edition = "NE"
+        sp      = "NE"
+      }
+    }
+  }
+}
@@ -0,0 +1,40 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_preference_scheduled_tasks" "tasks" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  task {
+    name        = "Cleanup"
+    description = "Removes the temporary files every night"
+    action      = "replace"
+
+    trigger {
+      type           = "daily"
+      start_boundary = "2024-01-01T03:00:00"
+    }
+
+    exec {
+      command   = "powershell.exe"
+      arguments = "-NoProfile -Command \"Remove-Item -Recurse -Force C:\\Windows\\Temp\\*\""
+    }
+  }
+
+  task {
+    name = "Inventory"
+
+    trigger {
+      type  = "boot"
+      delay = "PT5M"
+    }
+
+    exec {
+      command = "\\\\fileserver\\tools\\inventory.exe"
+    }
+  }
+}
@@ -0,0 +1,2 @@
+$ terraform import windowsad_gpo_registry_policy.reg 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_registrypolicy
+$ terraform import windowsad_gpo_registry_policy.user 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02_userregistrypolicy
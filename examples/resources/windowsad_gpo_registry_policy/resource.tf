@@ -0,0 +1,49 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_registry_policy" "reg" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+    value_name = "NoAutoUpdate"
+    type       = "REG_DWORD"
+    value      = "0"
+  }
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows NT\\DNSClient"
+    value_name = "SearchList"
+    type       = "REG_SZ"
+    value      = "corp.example.com,example.com"
+  }
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\System"
+    value_name = "ExampleList"
+    type       = "REG_MULTI_SZ"
+    values     = ["first", "second"]
+  }
+
+  delete {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+    value_name = "AUOptions"
+  }
+}
+
+resource "windowsad_gpo_registry_policy" "user" {
+  gpo_container = windowsad_gpo.gpo.id
+  configuration = "user"
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\Control Panel\\Desktop"
+    value_name = "ScreenSaveTimeOut"
+    type       = "REG_SZ"
+    value      = "600"
+  }
+}
@@ -0,0 +1,27 @@
+variable domain { default = "yourdomain.com" }
+variable gpo_name { default = "TestGPO" }
+
+resource "windowsad_gpo" "gpo" {
+  name   = var.gpo_name
+  domain = var.domain
+}
+
+resource "windowsad_gpo_script" "startup" {
+  gpo_container = windowsad_gpo.gpo.id
+  type          = "startup"
+  name          = "setup.ps1"
+  parameters    = "-Verbose"
+
+  content = <<-EOT
+    Write-Verbose "Configuring the computer"
+    Set-Service -Name W32Time -StartupType Automatic
+  EOT
+}
+
+resource "windowsad_gpo_script" "logon" {
+  gpo_container = windowsad_gpo.gpo.id
+  type          = "logon"
+  name          = "drives.cmd"
+  content       = "net use S: \\\\fileserver\\share\r\n"
+  order         = 0
+}
@@ -18,6 +18,23 @@ resource "windowsad_gpo_security" "gpo_sec" {
     acl          = "D:AR(A;;CCDCLCSWRPWPDTLOCRSDRCWDWO;;;LA)"
   }
 
+  security_options {
+    enable_guest_account   = "0"
+    lm_compatibility_level = "5"
+    no_lm_hash             = "1"
+    legal_notice_caption   = "Authorized use only"
+  }
+
+  privilege_rights {
+    privilege  = "SeDenyNetworkLogonRight"
+    principals = ["BUILTIN\\Guests", "NT AUTHORITY\\Local account"]
+  }
+
+  privilege_rights {
+    privilege  = "SeServiceLogonRight"
+    principals = ["S-1-5-20"]
+  }
+
   system_services {
     service_name = "CertSvc"
     startup_mode = "2"
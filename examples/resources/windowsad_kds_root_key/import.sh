@@ -0,0 +1,2 @@
+# The ID for this resource is the KeyId reported by Get-KdsRootKey
+$ terraform import windowsad_kds_root_key.lab 8ed3d1e9-60cb-4a3c-8c32-5bc8dd4e1a11
@@ -0,0 +1,4 @@
+# Backdating the effective time makes the key usable right away. Only do this in lab environments.
+resource "windowsad_kds_root_key" "lab" {
+  effective_time = "2020-01-01T00:00:00Z"
+}
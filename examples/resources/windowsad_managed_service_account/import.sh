@@ -0,0 +1,2 @@
+# The ID for this resource is the GUID of the service account
+$ terraform import windowsad_managed_service_account.sql 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02
@@ -0,0 +1,13 @@
+resource "windowsad_group" "sql_hosts" {
+  name             = "SQL Servers"
+  sam_account_name = "SQLServers"
+  container        = "OU=Groups,DC=yourdomain,DC=com"
+}
+
+resource "windowsad_managed_service_account" "sql" {
+  name                                            = "gmsa-sql"
+  dns_host_name                                   = "sql.yourdomain.com"
+  principals_allowed_to_retrieve_managed_password = [windowsad_group.sql_hosts.dn]
+  service_principal_names                         = ["MSSQLSvc/sql.yourdomain.com:1433"]
+  kerberos_encryption_type                        = ["AES128", "AES256"]
+}
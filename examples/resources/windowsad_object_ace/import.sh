@@ -0,0 +1,3 @@
+# The ID for this resource is the target DN and the ACE in SDDL form, separated by a pipe.
+# The principal must be given as a SID, SID aliases like DA are rejected, and the object types as GUIDs.
+$ terraform import windowsad_object_ace.helpdesk_reset_password 'OU=Staff,DC=contoso,DC=com|(OA;CIIO;CR;00299570-246d-11d0-a768-00aa006e0529;bf967aba-0de6-11d0-a285-00aa003049e2;S-1-5-21-1004336348-1177238915-682003330-1105)'
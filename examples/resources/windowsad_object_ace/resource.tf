@@ -0,0 +1,9 @@
+# Allow the helpdesk to reset the passwords of the users in the Staff OU
+resource "windowsad_object_ace" "helpdesk_reset_password" {
+  target_dn             = "OU=Staff,DC=contoso,DC=com"
+  principal             = "CONTOSO\\helpdesk"
+  rights                = ["ExtendedRight"]
+  object_type           = "Reset Password"
+  inherited_object_type = "user"
+  inheritance           = "Descendents"
+}
@@ -0,0 +1,4 @@
+# The ID for this resource is the DN of the target object. The import starts with an empty set of
+# ACEs, so the next plan adds every ACE of the configuration. Applying it leaves the ACEs that
+# already exist on the object as they are.
+$ terraform import windowsad_object_acl.workstations 'OU=Workstations,DC=contoso,DC=com'
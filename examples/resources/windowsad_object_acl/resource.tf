@@ -0,0 +1,18 @@
+# Delegate the administration of the Workstations OU to the desktop team
+resource "windowsad_object_acl" "workstations" {
+  target_dn = "OU=Workstations,DC=contoso,DC=com"
+
+  ace {
+    principal   = "CN=Desktop Team,OU=Groups,DC=contoso,DC=com"
+    rights      = ["CreateChild", "DeleteChild"]
+    object_type = "computer"
+    inheritance = "All"
+  }
+
+  ace {
+    principal             = "CN=Desktop Team,OU=Groups,DC=contoso,DC=com"
+    rights                = ["GenericAll"]
+    inherited_object_type = "computer"
+    inheritance           = "Descendents"
+  }
+}
@@ -0,0 +1,2 @@
+# The ID for this resource is the DN of the object
+$ terraform import windowsad_object_owner.pc01 'CN=PC01,OU=Workstations,DC=contoso,DC=com'
@@ -0,0 +1,10 @@
+resource "windowsad_computer" "pc01" {
+  name      = "PC01"
+  container = "OU=Workstations,DC=contoso,DC=com"
+}
+
+# Computers joined by a technician are owned by the technician, make Domain Admins the owner instead
+resource "windowsad_object_owner" "pc01" {
+  target_dn = windowsad_computer.pc01.dn
+  owner     = "CONTOSO\\Domain Admins"
+}
@@ -0,0 +1,10 @@
+resource "windowsad_offline_domain_join" "odj" {
+  name        = "web01"
+  container   = "OU=Servers,DC=yourdomain,DC=com"
+  description = "Pre-staged web server"
+}
+
+output "odj_blob" {
+  value     = windowsad_offline_domain_join.odj.odj_blob
+  sensitive = true
+}
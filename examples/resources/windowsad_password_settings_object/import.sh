@@ -0,0 +1,2 @@
+# The ID for this resource is the object GUID of the password settings object
+$ terraform import windowsad_password_settings_object.admins 2a6f0b3c-8b9e-4cde-9c0a-0f3c1f6c1234
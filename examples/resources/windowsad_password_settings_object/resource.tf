@@ -0,0 +1,18 @@
+resource "windowsad_group" "admins" {
+  name             = "tier0-admins"
+  sam_account_name = "tier0-admins"
+  container        = "OU=Groups,DC=contoso,DC=com"
+}
+
+resource "windowsad_password_settings_object" "admins" {
+  name                       = "tier0-admins"
+  description                = "Stricter password policy for tier 0 administrators"
+  precedence                 = 10
+  min_password_length        = 16
+  password_history_count     = 24
+  max_password_age           = "60.00:00:00"
+  lockout_threshold          = 5
+  lockout_duration           = "00:30:00"
+  lockout_observation_window = "00:30:00"
+  applies_to                 = [windowsad_group.admins.dn]
+}
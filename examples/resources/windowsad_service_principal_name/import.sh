@@ -0,0 +1,2 @@
+# The ID for this resource is the GUID of the account followed by the SPN, separated by a slash
+$ terraform import windowsad_service_principal_name.http 9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02/HTTP/web.yourdomain.com
@@ -0,0 +1,11 @@
+resource "windowsad_user" "svc" {
+  principal_name   = "svc-web@yourdomain.com"
+  sam_account_name = "svc-web"
+  display_name     = "Web service account"
+  initial_password = "SuperSecure1234!!"
+}
+
+resource "windowsad_service_principal_name" "http" {
+  account = windowsad_user.svc.id
+  spn     = "HTTP/web.yourdomain.com"
+}
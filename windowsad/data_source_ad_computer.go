@@ -41,6 +41,26 @@ func dataSourceADComputer() *schema.Resource {
 				Computed:    true,
 				Description: "The SID of the computer object.",
 			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the computer account is enabled.",
+			},
+			"dns_host_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The fully qualified domain name (FQDN) of the computer.",
+			},
+			"operating_system": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system reported by the computer.",
+			},
+			"operating_system_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system version reported by the computer.",
+			},
 		},
 	}
 }
@@ -61,7 +81,7 @@ func dataSourceADComputerRead(d *schema.ResourceData, meta interface{}) error {
 		identity = dn
 	}
 
-	computer, err := winrmhelper.NewComputerFromHost(meta.(*config.ProviderConf), identity)
+	computer, err := winrmhelper.NewComputerFromHost(meta.(*config.ProviderConf), identity, nil)
 	if err != nil {
 		return err
 	}
@@ -71,6 +91,10 @@ func dataSourceADComputerRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set("dn", computer.DN)
 	_ = d.Set("guid", computer.GUID)
 	_ = d.Set("sid", computer.SID.Value)
+	_ = d.Set("enabled", computer.Enabled)
+	_ = d.Set("dns_host_name", computer.DNSHostName)
+	_ = d.Set("operating_system", computer.OperatingSystem)
+	_ = d.Set("operating_system_version", computer.OperatingSystemVersion)
 
 	return nil
 }
@@ -0,0 +1,114 @@
+package windowsad
+
+import (
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceADDeletedObjects() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get the deleted objects that can still be restored from the Active Directory Recycle Bin, most recently deleted first.",
+		Read:        dataSourceADDeletedObjectsRead,
+		Schema: map[string]*schema.Schema{
+			"object_class": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return deleted objects of this class, e.g. `user`, `group`, `computer` or `organizationalUnit`. Computers are not returned for `user`.",
+			},
+			"sam_account_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return deleted objects with this SAM account name.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return deleted objects that had this name before they were deleted.",
+			},
+			"objects": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The deleted objects.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"guid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The GUID of the object, it is kept when the object is restored.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the object before it was deleted.",
+						},
+						"dn": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The distinguished name of the object in the Deleted Objects container.",
+						},
+						"object_class": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The class of the object.",
+						},
+						"sam_account_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The SAM account name of the object, empty for objects that are not security principals.",
+						},
+						"sid": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The SID of the object, empty for objects that are not security principals.",
+						},
+						"last_known_parent": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The distinguished name of the container the object was deleted from.",
+						},
+						"deleted_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "When the object was deleted, in RFC 3339 format.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceADDeletedObjectsRead(d *schema.ResourceData, meta interface{}) error {
+	filter := winrmhelper.DeletedObjectFilter{
+		ObjectClass:    d.Get("object_class").(string),
+		SAMAccountName: d.Get("sam_account_name").(string),
+		Name:           d.Get("name").(string),
+	}
+	objects, err := winrmhelper.GetDeletedObjects(meta.(*config.ProviderConf), filter)
+	if err != nil {
+		return fmt.Errorf("while retrieving deleted objects: %s", err)
+	}
+
+	result := make([]map[string]interface{}, 0, len(objects))
+	for _, o := range objects {
+		result = append(result, map[string]interface{}{
+			"guid":              o.GUID,
+			"name":              o.Name,
+			"dn":                o.DistinguishedName,
+			"object_class":      o.ObjectClass,
+			"sam_account_name":  o.SAMAccountName,
+			"sid":               o.SID,
+			"last_known_parent": o.LastKnownParent,
+			"deleted_at":        o.DeletedAt,
+		})
+	}
+
+	_ = d.Set("objects", result)
+	d.SetId(fmt.Sprintf("deleted-objects|%s|%s|%s", filter.ObjectClass, filter.SAMAccountName, filter.Name))
+	return nil
+}
@@ -0,0 +1,97 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataSourceADDeletedObjects_restoreUser(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_domain_name",
+	}
+
+	container := os.Getenv("TF_VAR_ad_user_container")
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	sam := testAccRandomSAM()
+	password := testAccRandomPassword()
+	principalName := testAccRandomPrincipalName(domain)
+	resourceName := "windowsad_user.a"
+	dataSourceName := "data.windowsad_deleted_objects.d"
+	var sid string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceADDeletedObjectsUserConfig(sam, password, principalName, container, "before"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccStoreAttribute(resourceName, "sid", &sid),
+				),
+			},
+			{
+				// the user is destroyed and ends up in the recycle bin
+				Config: testAccDataSourceADDeletedObjectsConfig(sam),
+			},
+			{
+				// data sources are read while planning, so the deleted user only shows up in the next plan
+				Config: testAccDataSourceADDeletedObjectsConfig(sam),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "objects.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "objects.0.object_class", "user"),
+					resource.TestCheckResourceAttrPtr(dataSourceName, "objects.0.sid", &sid),
+				),
+			},
+			{
+				// creating the user again restores it with the same SID and applies the configuration
+				Config: testAccDataSourceADDeletedObjectsUserConfig(sam, password, principalName, container, "after"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(resourceName, "sid", &sid),
+					resource.TestCheckResourceAttr(resourceName, "container", container),
+					resource.TestCheckResourceAttr(resourceName, "description", "after"),
+				),
+			},
+		},
+	})
+}
+
+// testAccStoreAttribute stores the value of an attribute so it can be compared in later steps
+func testAccStoreAttribute(name, key string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s resource not found", name)
+		}
+		*value = rs.Primary.Attributes[key]
+		return nil
+	}
+}
+
+func testAccDataSourceADDeletedObjectsUserConfig(sam, password, principalName, container, description string) string {
+	return fmt.Sprintf(`
+resource "windowsad_user" "a" {
+  sam_account_name         = %[1]q
+  display_name             = %[1]q
+  initial_password         = %[2]q
+  principal_name           = %[3]q
+  container                = %[4]q
+  description              = %[5]q
+  restore_from_recycle_bin = true
+}
+`, sam, password, principalName, container, description)
+}
+
+func testAccDataSourceADDeletedObjectsConfig(sam string) string {
+	return fmt.Sprintf(`
+data "windowsad_deleted_objects" "d" {
+  object_class     = "user"
+  sam_account_name = %q
+}
+`, sam)
+}
@@ -0,0 +1,152 @@
+package windowsad
+
+import (
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceADUserResultantPasswordPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "Get the password policy that applies to an Active Directory user, either a fine-grained password policy or the default domain password policy.",
+		Read:        dataSourceADUserResultantPasswordPolicyRead,
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The user's identifier. It can be the user's GUID, SID, Distinguished Name, or SAM Account Name.",
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Where the policy comes from, `password_settings_object` for a fine-grained password policy or `default_domain_policy` for the default domain password policy.",
+			},
+			"password_settings_object_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The GUID of the fine-grained password policy. Empty if the default domain password policy applies.",
+			},
+			"password_settings_object_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the fine-grained password policy. Empty if the default domain password policy applies.",
+			},
+			"precedence": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The precedence of the fine-grained password policy. `0` if the default domain password policy applies.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the fine-grained password policy, or of the domain if the default domain password policy applies.",
+			},
+			"complexity_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether passwords must meet complexity requirements.",
+			},
+			"min_password_length": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The minimum password length.",
+			},
+			"password_history_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of previous passwords that can't be reused.",
+			},
+			"min_password_age": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The minimum password age, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"max_password_age": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The maximum password age, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"lockout_threshold": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of failed logon attempts that cause the account to be locked out.",
+			},
+			"lockout_duration": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "How long an account stays locked out, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"lockout_observation_window": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time after which the failed logon attempts counter is reset, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"reversible_encryption_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether passwords are stored using reversible encryption.",
+			},
+		},
+	}
+}
+
+func dataSourceADUserResultantPasswordPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	userID := d.Get("user_id").(string)
+	u, err := winrmhelper.GetUserFromHost(conf, userID, nil)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return fmt.Errorf("No user found with user_id %q", userID)
+	}
+
+	pso, err := winrmhelper.GetUserResultantPasswordSettingsObject(conf, u.GUID)
+	if err != nil {
+		return err
+	}
+
+	if pso != nil {
+		_ = d.Set("source", "password_settings_object")
+		_ = d.Set("password_settings_object_id", pso.GUID)
+		_ = d.Set("password_settings_object_name", pso.Name)
+		_ = d.Set("precedence", pso.Precedence)
+		_ = d.Set("dn", pso.DN)
+		_ = d.Set("complexity_enabled", pso.ComplexityEnabled)
+		_ = d.Set("min_password_length", pso.MinPasswordLength)
+		_ = d.Set("password_history_count", pso.PasswordHistoryCount)
+		_ = d.Set("min_password_age", pso.MinPasswordAge)
+		_ = d.Set("max_password_age", pso.MaxPasswordAge)
+		_ = d.Set("lockout_threshold", pso.LockoutThreshold)
+		_ = d.Set("lockout_duration", pso.LockoutDuration)
+		_ = d.Set("lockout_observation_window", pso.LockoutObservationWindow)
+		_ = d.Set("reversible_encryption_enabled", pso.ReversibleEncryptionEnabled)
+	} else {
+		domainDN := winrmhelper.GetDomainDNFromDN(u.DistinguishedName)
+		policy, err := winrmhelper.GetDefaultDomainPasswordPolicyFromHost(conf, domainDN)
+		if err != nil {
+			return err
+		}
+		_ = d.Set("source", "default_domain_policy")
+		_ = d.Set("password_settings_object_id", "")
+		_ = d.Set("password_settings_object_name", "")
+		_ = d.Set("precedence", 0)
+		_ = d.Set("dn", policy.DN)
+		_ = d.Set("complexity_enabled", policy.ComplexityEnabled)
+		_ = d.Set("min_password_length", policy.MinPasswordLength)
+		_ = d.Set("password_history_count", policy.PasswordHistoryCount)
+		_ = d.Set("min_password_age", policy.MinPasswordAge)
+		_ = d.Set("max_password_age", policy.MaxPasswordAge)
+		_ = d.Set("lockout_threshold", policy.LockoutThreshold)
+		_ = d.Set("lockout_duration", policy.LockoutDuration)
+		_ = d.Set("lockout_observation_window", policy.LockoutObservationWindow)
+		_ = d.Set("reversible_encryption_enabled", policy.ReversibleEncryptionEnabled)
+	}
+	d.SetId(u.GUID)
+
+	return nil
+}
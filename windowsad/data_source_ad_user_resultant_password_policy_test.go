@@ -0,0 +1,76 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceADUserResultantPasswordPolicy_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_domain_name",
+	}
+
+	container := os.Getenv("TF_VAR_ad_user_container")
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	sam := testAccRandomSAM()
+	psoName := testAccShortRandomName("pso")
+	password := testAccRandomPassword()
+	principalName := testAccRandomPrincipalName(domain)
+	dataSourceName := "data.windowsad_user_resultant_password_policy.d"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceADUserResultantPasswordPolicyConfig(sam, password, principalName, container, psoName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "source", "default_domain_policy"),
+					resource.TestCheckResourceAttr(dataSourceName, "password_settings_object_name", ""),
+				),
+			},
+			{
+				Config: testAccDataSourceADUserResultantPasswordPolicyConfig(sam, password, principalName, container, psoName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "source", "password_settings_object"),
+					resource.TestCheckResourceAttr(dataSourceName, "password_settings_object_name", psoName),
+					resource.TestCheckResourceAttr(dataSourceName, "min_password_length", "15"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceADUserResultantPasswordPolicyConfig(sam, password, principalName, container, psoName string, applyPSO bool) string {
+	appliesTo := "[]"
+	if applyPSO {
+		appliesTo = "[windowsad_user.a.dn]"
+	}
+	return fmt.Sprintf(`
+resource "windowsad_user" "a" {
+  sam_account_name = %[1]q
+  display_name     = %[1]q
+  initial_password = %[2]q
+  principal_name   = %[3]q
+  container        = %[4]q
+}
+
+resource "windowsad_password_settings_object" "p" {
+  name                = %[5]q
+  precedence          = 10
+  min_password_length = 15
+  applies_to          = %[6]s
+}
+
+data "windowsad_user_resultant_password_policy" "d" {
+  user_id = windowsad_user.a.id
+
+  depends_on = [windowsad_password_settings_object.p]
+}
+`, sam, password, principalName, container, psoName, appliesTo)
+}
@@ -2,6 +2,7 @@ package adschema
 
 import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // GPOSecuritySchemaKeys is a list of all keys defined in the resource's schema
@@ -96,6 +97,19 @@ func GpoSecuritySchema() map[string]*schema.Schema {
 			Elem:        &schema.Resource{Schema: filesystemSchema()},
 			Description: "Settings related to File System permissions. (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpsb/abeebe06-49aa-44d4-ae5b-d6aff458e8e7)",
 		},
+		"security_options": {
+			Type:        schema.TypeList,
+			MaxItems:    1,
+			Optional:    true,
+			Elem:        &schema.Resource{Schema: securityOptionsSchema()},
+			Description: "Named Security Options, written to the System Access and Registry Values sections. (https://docs.microsoft.com/en-us/windows/security/threat-protection/security-policy-settings/security-options)",
+		},
+		"privilege_rights": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Resource{Schema: privilegeRightsSchema()},
+			Description: "User rights assignments. (https://docs.microsoft.com/en-us/windows/security/threat-protection/security-policy-settings/user-rights-assignment)",
+		},
 	}
 }
 
@@ -208,9 +222,10 @@ func restrictedGroupsSchema() map[string]*schema.Schema {
 func registryValuesSchema() map[string]*schema.Schema {
 	sch := map[string]*schema.Schema{
 		"key_name": {
-			Type:        schema.TypeString,
-			Required:    true,
-			Description: "Fully qualified name of the key (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-rrp/97587de7-3524-4291-8527-39517110c0eb)",
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: ValidateRegistryValueKeyName,
+			Description:  "Fully qualified name of the key (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-rrp/97587de7-3524-4291-8527-39517110c0eb). The values of the Security Options catalog must be set with `security_options` instead.",
 		},
 		"value_type": {
 			Type:        schema.TypeString,
@@ -289,6 +304,73 @@ func filesystemSchema() map[string]*schema.Schema {
 	return sch
 }
 
+// PrivilegeRightNames lists the user rights that can be assigned through the Privilege Rights section.
+var PrivilegeRightNames = []string{
+	"SeAssignPrimaryTokenPrivilege",
+	"SeAuditPrivilege",
+	"SeBackupPrivilege",
+	"SeBatchLogonRight",
+	"SeChangeNotifyPrivilege",
+	"SeCreateGlobalPrivilege",
+	"SeCreatePagefilePrivilege",
+	"SeCreatePermanentPrivilege",
+	"SeCreateSymbolicLinkPrivilege",
+	"SeCreateTokenPrivilege",
+	"SeDebugPrivilege",
+	"SeDelegateSessionUserImpersonatePrivilege",
+	"SeDenyBatchLogonRight",
+	"SeDenyInteractiveLogonRight",
+	"SeDenyNetworkLogonRight",
+	"SeDenyRemoteInteractiveLogonRight",
+	"SeDenyServiceLogonRight",
+	"SeEnableDelegationPrivilege",
+	"SeImpersonatePrivilege",
+	"SeIncreaseBasePriorityPrivilege",
+	"SeIncreaseQuotaPrivilege",
+	"SeIncreaseWorkingSetPrivilege",
+	"SeInteractiveLogonRight",
+	"SeLoadDriverPrivilege",
+	"SeLockMemoryPrivilege",
+	"SeMachineAccountPrivilege",
+	"SeManageVolumePrivilege",
+	"SeNetworkLogonRight",
+	"SeProfileSingleProcessPrivilege",
+	"SeRelabelPrivilege",
+	"SeRemoteInteractiveLogonRight",
+	"SeRemoteShutdownPrivilege",
+	"SeRestorePrivilege",
+	"SeSecurityPrivilege",
+	"SeServiceLogonRight",
+	"SeShutdownPrivilege",
+	"SeSyncAgentPrivilege",
+	"SeSystemEnvironmentPrivilege",
+	"SeSystemProfilePrivilege",
+	"SeSystemtimePrivilege",
+	"SeTakeOwnershipPrivilege",
+	"SeTcbPrivilege",
+	"SeTimeZonePrivilege",
+	"SeTrustedCredManAccessPrivilege",
+	"SeUndockPrivilege",
+}
+
+func privilegeRightsSchema() map[string]*schema.Schema {
+	sch := map[string]*schema.Schema{
+		"privilege": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(PrivilegeRightNames, false),
+			Description:  "Name of the user right, e.g. `SeDenyNetworkLogonRight` or `SeServiceLogonRight`.",
+		},
+		"principals": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Users and groups the right is assigned to, as account names like `BUILTIN\\Administrators` or SIDs. Names are written to the GPO as SIDs. An empty set assigns the right to no one.",
+		},
+	}
+	return sch
+}
+
 func init() {
 	for k := range GpoSecuritySchema() {
 		GPOSecuritySchemaKeys = append(GPOSecuritySchemaKeys, k)
@@ -0,0 +1,371 @@
+package adschema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Registry value types used by the Registry Values section of the security settings INF file
+const (
+	RegistryValueString      = 1
+	RegistryValueDWord       = 4
+	RegistryValueMultiString = 7
+)
+
+// SecurityOptionsSystemAccess is the value of SecurityOption.ValueType for the options stored as
+// keys of the System Access section instead of registry values
+const SecurityOptionsSystemAccess = 0
+
+// SecurityOption describes a named Security Option and the INF key it maps to. Options with a
+// registry value type are written to the Registry Values section, the other ones to the System
+// Access section.
+type SecurityOption struct {
+	Name        string
+	Key         string
+	ValueType   int
+	Description string
+	Validate    schema.SchemaValidateFunc
+}
+
+const (
+	lsaKey         = `MACHINE\System\CurrentControlSet\Control\Lsa\`
+	lanmanServer   = `MACHINE\System\CurrentControlSet\Services\LanManServer\Parameters\`
+	lanmanWks      = `MACHINE\System\CurrentControlSet\Services\LanmanWorkstation\Parameters\`
+	netlogonKey    = `MACHINE\System\CurrentControlSet\Services\Netlogon\Parameters\`
+	systemPolicies = `MACHINE\Software\Microsoft\Windows\CurrentVersion\Policies\System\`
+)
+
+var validateBoolean = validation.StringInSlice([]string{"0", "1"}, false)
+
+// validateIntegerString validates strings holding integers between min and max
+func validateIntegerString(min, max int) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) ([]string, []error) {
+		v, err := strconv.Atoi(i.(string))
+		if err != nil {
+			return nil, []error{fmt.Errorf("expected %s to be an integer, got %q", k, i.(string))}
+		}
+		if v < min || v > max {
+			return nil, []error{fmt.Errorf("expected %s to be in the range (%d - %d), got %d", k, min, max, v)}
+		}
+		return nil, nil
+	}
+}
+
+// SecurityOptions is the catalog of the Security Options supported by the security_options block
+// (https://docs.microsoft.com/en-us/windows/security/threat-protection/security-policy-settings/security-options)
+var SecurityOptions = []SecurityOption{
+	{
+		Name:        "lsa_anonymous_name_lookup",
+		Key:         "LSAAnonymousNameLookup",
+		ValueType:   SecurityOptionsSystemAccess,
+		Description: "Network access: Allow anonymous SID/Name translation. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "enable_admin_account",
+		Key:         "EnableAdminAccount",
+		ValueType:   SecurityOptionsSystemAccess,
+		Description: "Accounts: Administrator account status. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "enable_guest_account",
+		Key:         "EnableGuestAccount",
+		ValueType:   SecurityOptionsSystemAccess,
+		Description: "Accounts: Guest account status. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "new_administrator_name",
+		Key:         "NewAdministratorName",
+		ValueType:   SecurityOptionsSystemAccess,
+		Description: "Accounts: Rename administrator account.",
+	},
+	{
+		Name:        "new_guest_name",
+		Key:         "NewGuestName",
+		ValueType:   SecurityOptionsSystemAccess,
+		Description: "Accounts: Rename guest account.",
+	},
+	{
+		Name:        "limit_blank_password_use",
+		Key:         lsaKey + "LimitBlankPasswordUse",
+		ValueType:   RegistryValueDWord,
+		Description: "Accounts: Limit local account use of blank passwords to console logon only. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "force_audit_policy_subcategory_settings",
+		Key:         lsaKey + "SCENoApplyLegacyAuditPolicy",
+		ValueType:   RegistryValueDWord,
+		Description: "Audit: Force audit policy subcategory settings to override audit policy category settings. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "lm_compatibility_level",
+		Key:         lsaKey + "LmCompatibilityLevel",
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: LAN Manager authentication level. 0: send LM & NTLM responses, 1: send LM & NTLM, use NTLMv2 session security if negotiated, 2: send NTLM response only, 3: send NTLMv2 response only, 4: send NTLMv2 response only, refuse LM, 5: send NTLMv2 response only, refuse LM & NTLM.",
+		Validate:    validateIntegerString(0, 5),
+	},
+	{
+		Name:        "no_lm_hash",
+		Key:         lsaKey + "NoLMHash",
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: Do not store LAN Manager hash value on next password change. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "restrict_anonymous",
+		Key:         lsaKey + "RestrictAnonymous",
+		ValueType:   RegistryValueDWord,
+		Description: "Network access: Do not allow anonymous enumeration of SAM accounts and shares. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "restrict_anonymous_sam",
+		Key:         lsaKey + "RestrictAnonymousSAM",
+		ValueType:   RegistryValueDWord,
+		Description: "Network access: Do not allow anonymous enumeration of SAM accounts. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "everyone_includes_anonymous",
+		Key:         lsaKey + "EveryoneIncludesAnonymous",
+		ValueType:   RegistryValueDWord,
+		Description: "Network access: Let Everyone permissions apply to anonymous users. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "ntlm_min_client_sec",
+		Key:         lsaKey + `MSV1_0\NTLMMinClientSec`,
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: Minimum session security for NTLM SSP based clients, as a bit mask. 537395200 requires NTLMv2 session security and 128-bit encryption.",
+		Validate:    validateIntegerString(0, 1<<31-1),
+	},
+	{
+		Name:        "ntlm_min_server_sec",
+		Key:         lsaKey + `MSV1_0\NTLMMinServerSec`,
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: Minimum session security for NTLM SSP based servers, as a bit mask. 537395200 requires NTLMv2 session security and 128-bit encryption.",
+		Validate:    validateIntegerString(0, 1<<31-1),
+	},
+	{
+		Name:        "server_require_security_signature",
+		Key:         lanmanServer + "RequireSecuritySignature",
+		ValueType:   RegistryValueDWord,
+		Description: "Microsoft network server: Digitally sign communications (always). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "server_enable_security_signature",
+		Key:         lanmanServer + "EnableSecuritySignature",
+		ValueType:   RegistryValueDWord,
+		Description: "Microsoft network server: Digitally sign communications (if client agrees). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "restrict_null_sess_access",
+		Key:         lanmanServer + "RestrictNullSessAccess",
+		ValueType:   RegistryValueDWord,
+		Description: "Network access: Restrict anonymous access to Named Pipes and Shares. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "client_require_security_signature",
+		Key:         lanmanWks + "RequireSecuritySignature",
+		ValueType:   RegistryValueDWord,
+		Description: "Microsoft network client: Digitally sign communications (always). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "client_enable_security_signature",
+		Key:         lanmanWks + "EnableSecuritySignature",
+		ValueType:   RegistryValueDWord,
+		Description: "Microsoft network client: Digitally sign communications (if server agrees). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "client_enable_plain_text_password",
+		Key:         lanmanWks + "EnablePlainTextPassword",
+		ValueType:   RegistryValueDWord,
+		Description: "Microsoft network client: Send unencrypted password to third-party SMB servers. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "ldap_client_integrity",
+		Key:         `MACHINE\System\CurrentControlSet\Services\LDAP\LDAPClientIntegrity`,
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: LDAP client signing requirements. 0: none, 1: negotiate signing, 2: require signing.",
+		Validate:    validateIntegerString(0, 2),
+	},
+	{
+		Name:        "ldap_server_integrity",
+		Key:         `MACHINE\System\CurrentControlSet\Services\NTDS\Parameters\LDAPServerIntegrity`,
+		ValueType:   RegistryValueDWord,
+		Description: "Domain controller: LDAP server signing requirements. 1: none, 2: require signing.",
+		Validate:    validateIntegerString(1, 2),
+	},
+	{
+		Name:        "require_sign_or_seal",
+		Key:         netlogonKey + "RequireSignOrSeal",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Digitally encrypt or sign secure channel data (always). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "seal_secure_channel",
+		Key:         netlogonKey + "SealSecureChannel",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Digitally encrypt secure channel data (when possible). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "sign_secure_channel",
+		Key:         netlogonKey + "SignSecureChannel",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Digitally sign secure channel data (when possible). 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "require_strong_key",
+		Key:         netlogonKey + "RequireStrongKey",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Require strong (Windows 2000 or later) session key. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "disable_machine_account_password_change",
+		Key:         netlogonKey + "DisablePasswordChange",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Disable machine account password changes. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "maximum_machine_account_password_age",
+		Key:         netlogonKey + "MaximumPasswordAge",
+		ValueType:   RegistryValueDWord,
+		Description: "Domain member: Maximum machine account password age, in days (0-999).",
+		Validate:    validateIntegerString(0, 999),
+	},
+	{
+		Name:        "dont_display_last_user_name",
+		Key:         systemPolicies + "DontDisplayLastUserName",
+		ValueType:   RegistryValueDWord,
+		Description: "Interactive logon: Do not display last user name. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "disable_cad",
+		Key:         systemPolicies + "DisableCAD",
+		ValueType:   RegistryValueDWord,
+		Description: "Interactive logon: Do not require CTRL+ALT+DEL. 0: CTRL+ALT+DEL is required, 1: it is not.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "inactivity_timeout_secs",
+		Key:         systemPolicies + "InactivityTimeoutSecs",
+		ValueType:   RegistryValueDWord,
+		Description: "Interactive logon: Machine inactivity limit, in seconds (0-599940).",
+		Validate:    validateIntegerString(0, 599940),
+	},
+	{
+		Name:        "legal_notice_caption",
+		Key:         systemPolicies + "LegalNoticeCaption",
+		ValueType:   RegistryValueString,
+		Description: "Interactive logon: Message title for users attempting to log on.",
+	},
+	{
+		Name:        "legal_notice_text",
+		Key:         systemPolicies + "LegalNoticeText",
+		ValueType:   RegistryValueMultiString,
+		Description: "Interactive logon: Message text for users attempting to log on. Commas separate the lines of the message.",
+	},
+	{
+		Name:        "shutdown_without_logon",
+		Key:         systemPolicies + "ShutdownWithoutLogon",
+		ValueType:   RegistryValueDWord,
+		Description: "Shutdown: Allow system to be shut down without having to log on. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "enable_lua",
+		Key:         systemPolicies + "EnableLUA",
+		ValueType:   RegistryValueDWord,
+		Description: "User Account Control: Run all administrators in Admin Approval Mode. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "filter_administrator_token",
+		Key:         systemPolicies + "FilterAdministratorToken",
+		ValueType:   RegistryValueDWord,
+		Description: "User Account Control: Admin Approval Mode for the Built-in Administrator account. 0: disabled, 1: enabled.",
+		Validate:    validateBoolean,
+	},
+	{
+		Name:        "consent_prompt_behavior_admin",
+		Key:         systemPolicies + "ConsentPromptBehaviorAdmin",
+		ValueType:   RegistryValueDWord,
+		Description: "User Account Control: Behavior of the elevation prompt for administrators in Admin Approval Mode (0-5). 2 prompts for consent on the secure desktop.",
+		Validate:    validateIntegerString(0, 5),
+	},
+	{
+		Name:        "consent_prompt_behavior_user",
+		Key:         systemPolicies + "ConsentPromptBehaviorUser",
+		ValueType:   RegistryValueDWord,
+		Description: "User Account Control: Behavior of the elevation prompt for standard users. 0: automatically deny, 1: prompt for credentials on the secure desktop, 3: prompt for credentials.",
+		Validate:    validation.StringInSlice([]string{"0", "1", "3"}, false),
+	},
+	{
+		Name:        "kerberos_supported_encryption_types",
+		Key:         systemPolicies + `Kerberos\Parameters\SupportedEncryptionTypes`,
+		ValueType:   RegistryValueDWord,
+		Description: "Network security: Configure encryption types allowed for Kerberos, as a bit mask. 24 allows AES128 and AES256 only.",
+		Validate:    validateIntegerString(0, 1<<31-1),
+	},
+	{
+		Name:        "cached_logons_count",
+		Key:         `MACHINE\Software\Microsoft\Windows NT\CurrentVersion\Winlogon\CachedLogonsCount`,
+		ValueType:   RegistryValueString,
+		Description: "Interactive logon: Number of previous logons to cache (0-50).",
+		Validate:    validateIntegerString(0, 50),
+	},
+}
+
+// LookupSecurityOption returns the Security Option of the catalog with the given INF key. Keys are
+// compared case insensitively.
+func LookupSecurityOption(key string) (SecurityOption, bool) {
+	for _, opt := range SecurityOptions {
+		if strings.EqualFold(opt.Key, key) {
+			return opt, true
+		}
+	}
+	return SecurityOption{}, false
+}
+
+// ValidateRegistryValueKeyName rejects the registry values of the Security Options catalog, which are
+// managed by the security_options block. Reading them back in both places would make the two blocks
+// fight over the same value.
+func ValidateRegistryValueKeyName(v interface{}, k string) ([]string, []error) {
+	if opt, ok := LookupSecurityOption(v.(string)); ok {
+		return nil, []error{fmt.Errorf("%s: %q is a security option, set it with the %q attribute of the security_options block instead", k, v.(string), opt.Name)}
+	}
+	return nil, nil
+}
+
+func securityOptionsSchema() map[string]*schema.Schema {
+	sch := map[string]*schema.Schema{}
+	for _, opt := range SecurityOptions {
+		sch[opt.Name] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: opt.Validate,
+			Description:  opt.Description,
+		}
+	}
+	return sch
+}
@@ -0,0 +1,113 @@
+// Package auditcsv encodes and decodes audit.csv files, the file holding the advanced audit policy
+// configuration of Group Policy Objects.
+// (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpac/)
+package auditcsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Header is the first line of every audit.csv file
+var Header = []string{"Machine Name", "Policy Target", "Subcategory", "Subcategory GUID", "Inclusion Setting", "Exclusion Setting", "Setting Value"}
+
+// Setting values of the audit.csv file
+const (
+	NoAuditing        = 0
+	Success           = 1
+	Failure           = 2
+	SuccessAndFailure = 3
+)
+
+var inclusionSettings = map[int]string{
+	NoAuditing:        "No Auditing",
+	Success:           "Success",
+	Failure:           "Failure",
+	SuccessAndFailure: "Success and Failure",
+}
+
+// Setting is the audit setting of a subcategory
+type Setting struct {
+	Subcategory string
+	Success     bool
+	Failure     bool
+}
+
+// Value returns the value of the Setting Value column for this setting
+func (s Setting) Value() int {
+	v := NoAuditing
+	if s.Success {
+		v |= Success
+	}
+	if s.Failure {
+		v |= Failure
+	}
+	return v
+}
+
+// Encode returns the contents of an audit.csv file holding the given settings. Settings are
+// written in the order of the catalog, the way the Group Policy editor writes them.
+func Encode(settings []Setting) ([]byte, error) {
+	bySubcategory := map[string]Setting{}
+	for _, s := range settings {
+		if _, ok := LookupSubcategory(s.Subcategory); !ok {
+			return nil, fmt.Errorf("unknown audit subcategory %q", s.Subcategory)
+		}
+		if _, ok := bySubcategory[s.Subcategory]; ok {
+			return nil, fmt.Errorf("audit subcategory %q is set more than once", s.Subcategory)
+		}
+		bySubcategory[s.Subcategory] = s
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	w.UseCRLF = true
+	_ = w.Write(Header)
+	for _, sub := range Subcategories {
+		s, ok := bySubcategory[sub.Name]
+		if !ok {
+			continue
+		}
+		value := s.Value()
+		_ = w.Write([]string{"", "System", fmt.Sprintf("Audit %s", sub.Name), sub.GUID, inclusionSettings[value], "", strconv.Itoa(value)})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Parse decodes the contents of an audit.csv file. Rows that aren't subcategory settings, such as
+// the global audit options, are ignored. Settings are returned sorted by subcategory name.
+func Parse(data []byte) ([]Setting, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing audit.csv: %s", err)
+	}
+
+	settings := []Setting{}
+	for idx, record := range records {
+		if idx == 0 || len(record) < len(Header) {
+			continue
+		}
+		sub, ok := LookupSubcategoryGUID(strings.TrimSpace(record[3]))
+		if !ok {
+			continue
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(record[6]))
+		if err != nil || value < NoAuditing || value > SuccessAndFailure {
+			return nil, fmt.Errorf("invalid setting value %q for audit subcategory %q", record[6], sub.Name)
+		}
+		settings = append(settings, Setting{
+			Subcategory: sub.Name,
+			Success:     value&Success != 0,
+			Failure:     value&Failure != 0,
+		})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Subcategory < settings[j].Subcategory })
+	return settings, nil
+}
@@ -0,0 +1,98 @@
+package auditcsv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	got, err := Encode([]Setting{
+		{Subcategory: "Process Creation", Success: true},
+		{Subcategory: "Credential Validation", Success: true, Failure: true},
+		{Subcategory: "Logon", Failure: true},
+		{Subcategory: "Logoff"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"Machine Name,Policy Target,Subcategory,Subcategory GUID,Inclusion Setting,Exclusion Setting,Setting Value",
+		",System,Audit Credential Validation,{0cce923f-69ae-11d9-bed3-505054503030},Success and Failure,,3",
+		",System,Audit Process Creation,{0cce922b-69ae-11d9-bed3-505054503030},Success,,1",
+		",System,Audit Logoff,{0cce9216-69ae-11d9-bed3-505054503030},No Auditing,,0",
+		",System,Audit Logon,{0cce9215-69ae-11d9-bed3-505054503030},Failure,,2",
+		"",
+	}, "\r\n")
+	if string(got) != expected {
+		t.Errorf("Encode() = %q, expected %q", got, expected)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	cases := map[string][]Setting{
+		"unknown subcategory": {{Subcategory: "Not A Subcategory", Success: true}},
+		"duplicate":           {{Subcategory: "Logon", Success: true}, {Subcategory: "Logon", Failure: true}},
+	}
+	for name, settings := range cases {
+		if _, err := Encode(settings); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	settings := []Setting{
+		{Subcategory: "Credential Validation", Success: true, Failure: true},
+		{Subcategory: "Logon", Failure: true},
+		{Subcategory: "Process Creation", Success: true},
+		{Subcategory: "Security Group Management"},
+	}
+	data, err := Encode(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, settings) {
+		t.Errorf("Parse(Encode()) = %v, expected %v", got, settings)
+	}
+}
+
+func TestParseIgnoresOptions(t *testing.T) {
+	data := strings.Join([]string{
+		"Machine Name,Policy Target,Subcategory,Subcategory GUID,Inclusion Setting,Exclusion Setting,Setting Value",
+		",System,Audit Logon,{0CCE9215-69AE-11D9-BED3-505054503030},Success,,1",
+		",,Option:CrashOnAuditFail,,Enabled,,1",
+		"",
+	}, "\r\n")
+	got, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Setting{{Subcategory: "Logon", Success: true}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Parse() = %v, expected %v", got, expected)
+	}
+}
+
+func TestParseInvalidValue(t *testing.T) {
+	data := "Machine Name,Policy Target,Subcategory,Subcategory GUID,Inclusion Setting,Exclusion Setting,Setting Value\r\n" +
+		",System,Audit Logon,{0cce9215-69ae-11d9-bed3-505054503030},Success,,8\r\n"
+	if _, err := Parse([]byte(data)); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestCatalogGUIDsAreUnique(t *testing.T) {
+	seen := map[string]string{}
+	for _, s := range Subcategories {
+		if other, ok := seen[strings.ToLower(s.GUID)]; ok {
+			t.Errorf("subcategories %q and %q share GUID %s", other, s.Name, s.GUID)
+		}
+		seen[strings.ToLower(s.GUID)] = s.Name
+	}
+}
@@ -0,0 +1,116 @@
+package auditcsv
+
+import (
+	"sort"
+	"strings"
+)
+
+// Subcategory describes an advanced audit policy subcategory
+type Subcategory struct {
+	Name     string
+	Category string
+	GUID     string
+}
+
+// Subcategories is the catalog of the advanced audit policy subcategories
+// (https://docs.microsoft.com/en-us/windows/security/threat-protection/auditing/advanced-security-audit-policy-settings)
+var Subcategories = []Subcategory{
+	{Name: "Credential Validation", Category: "Account Logon", GUID: "{0cce923f-69ae-11d9-bed3-505054503030}"},
+	{Name: "Kerberos Authentication Service", Category: "Account Logon", GUID: "{0cce9242-69ae-11d9-bed3-505054503030}"},
+	{Name: "Kerberos Service Ticket Operations", Category: "Account Logon", GUID: "{0cce9240-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Account Logon Events", Category: "Account Logon", GUID: "{0cce9241-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Application Group Management", Category: "Account Management", GUID: "{0cce9239-69ae-11d9-bed3-505054503030}"},
+	{Name: "Computer Account Management", Category: "Account Management", GUID: "{0cce9236-69ae-11d9-bed3-505054503030}"},
+	{Name: "Distribution Group Management", Category: "Account Management", GUID: "{0cce9238-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Account Management Events", Category: "Account Management", GUID: "{0cce923a-69ae-11d9-bed3-505054503030}"},
+	{Name: "Security Group Management", Category: "Account Management", GUID: "{0cce9237-69ae-11d9-bed3-505054503030}"},
+	{Name: "User Account Management", Category: "Account Management", GUID: "{0cce9235-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "DPAPI Activity", Category: "Detailed Tracking", GUID: "{0cce922d-69ae-11d9-bed3-505054503030}"},
+	{Name: "Plug and Play Events", Category: "Detailed Tracking", GUID: "{0cce9248-69ae-11d9-bed3-505054503030}"},
+	{Name: "Process Creation", Category: "Detailed Tracking", GUID: "{0cce922b-69ae-11d9-bed3-505054503030}"},
+	{Name: "Process Termination", Category: "Detailed Tracking", GUID: "{0cce922c-69ae-11d9-bed3-505054503030}"},
+	{Name: "RPC Events", Category: "Detailed Tracking", GUID: "{0cce922e-69ae-11d9-bed3-505054503030}"},
+	{Name: "Token Right Adjusted Events", Category: "Detailed Tracking", GUID: "{0cce924a-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Detailed Directory Service Replication", Category: "DS Access", GUID: "{0cce923e-69ae-11d9-bed3-505054503030}"},
+	{Name: "Directory Service Access", Category: "DS Access", GUID: "{0cce923b-69ae-11d9-bed3-505054503030}"},
+	{Name: "Directory Service Changes", Category: "DS Access", GUID: "{0cce923c-69ae-11d9-bed3-505054503030}"},
+	{Name: "Directory Service Replication", Category: "DS Access", GUID: "{0cce923d-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Account Lockout", Category: "Logon/Logoff", GUID: "{0cce9217-69ae-11d9-bed3-505054503030}"},
+	{Name: "Group Membership", Category: "Logon/Logoff", GUID: "{0cce9249-69ae-11d9-bed3-505054503030}"},
+	{Name: "IPsec Extended Mode", Category: "Logon/Logoff", GUID: "{0cce921a-69ae-11d9-bed3-505054503030}"},
+	{Name: "IPsec Main Mode", Category: "Logon/Logoff", GUID: "{0cce9218-69ae-11d9-bed3-505054503030}"},
+	{Name: "IPsec Quick Mode", Category: "Logon/Logoff", GUID: "{0cce9219-69ae-11d9-bed3-505054503030}"},
+	{Name: "Logoff", Category: "Logon/Logoff", GUID: "{0cce9216-69ae-11d9-bed3-505054503030}"},
+	{Name: "Logon", Category: "Logon/Logoff", GUID: "{0cce9215-69ae-11d9-bed3-505054503030}"},
+	{Name: "Network Policy Server", Category: "Logon/Logoff", GUID: "{0cce9243-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Logon/Logoff Events", Category: "Logon/Logoff", GUID: "{0cce921c-69ae-11d9-bed3-505054503030}"},
+	{Name: "Special Logon", Category: "Logon/Logoff", GUID: "{0cce921b-69ae-11d9-bed3-505054503030}"},
+	{Name: "User / Device Claims", Category: "Logon/Logoff", GUID: "{0cce9247-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Application Generated", Category: "Object Access", GUID: "{0cce9222-69ae-11d9-bed3-505054503030}"},
+	{Name: "Central Policy Staging", Category: "Object Access", GUID: "{0cce9246-69ae-11d9-bed3-505054503030}"},
+	{Name: "Certification Services", Category: "Object Access", GUID: "{0cce9221-69ae-11d9-bed3-505054503030}"},
+	{Name: "Detailed File Share", Category: "Object Access", GUID: "{0cce9244-69ae-11d9-bed3-505054503030}"},
+	{Name: "File Share", Category: "Object Access", GUID: "{0cce9224-69ae-11d9-bed3-505054503030}"},
+	{Name: "File System", Category: "Object Access", GUID: "{0cce921d-69ae-11d9-bed3-505054503030}"},
+	{Name: "Filtering Platform Connection", Category: "Object Access", GUID: "{0cce9226-69ae-11d9-bed3-505054503030}"},
+	{Name: "Filtering Platform Packet Drop", Category: "Object Access", GUID: "{0cce9225-69ae-11d9-bed3-505054503030}"},
+	{Name: "Handle Manipulation", Category: "Object Access", GUID: "{0cce9223-69ae-11d9-bed3-505054503030}"},
+	{Name: "Kernel Object", Category: "Object Access", GUID: "{0cce921f-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Object Access Events", Category: "Object Access", GUID: "{0cce9227-69ae-11d9-bed3-505054503030}"},
+	{Name: "Registry", Category: "Object Access", GUID: "{0cce921e-69ae-11d9-bed3-505054503030}"},
+	{Name: "Removable Storage", Category: "Object Access", GUID: "{0cce9245-69ae-11d9-bed3-505054503030}"},
+	{Name: "SAM", Category: "Object Access", GUID: "{0cce9220-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Audit Policy Change", Category: "Policy Change", GUID: "{0cce922f-69ae-11d9-bed3-505054503030}"},
+	{Name: "Authentication Policy Change", Category: "Policy Change", GUID: "{0cce9230-69ae-11d9-bed3-505054503030}"},
+	{Name: "Authorization Policy Change", Category: "Policy Change", GUID: "{0cce9231-69ae-11d9-bed3-505054503030}"},
+	{Name: "Filtering Platform Policy Change", Category: "Policy Change", GUID: "{0cce9233-69ae-11d9-bed3-505054503030}"},
+	{Name: "MPSSVC Rule-Level Policy Change", Category: "Policy Change", GUID: "{0cce9232-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Policy Change Events", Category: "Policy Change", GUID: "{0cce9234-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "Non Sensitive Privilege Use", Category: "Privilege Use", GUID: "{0cce9229-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other Privilege Use Events", Category: "Privilege Use", GUID: "{0cce922a-69ae-11d9-bed3-505054503030}"},
+	{Name: "Sensitive Privilege Use", Category: "Privilege Use", GUID: "{0cce9228-69ae-11d9-bed3-505054503030}"},
+
+	{Name: "IPsec Driver", Category: "System", GUID: "{0cce9213-69ae-11d9-bed3-505054503030}"},
+	{Name: "Other System Events", Category: "System", GUID: "{0cce9214-69ae-11d9-bed3-505054503030}"},
+	{Name: "Security State Change", Category: "System", GUID: "{0cce9210-69ae-11d9-bed3-505054503030}"},
+	{Name: "Security System Extension", Category: "System", GUID: "{0cce9211-69ae-11d9-bed3-505054503030}"},
+	{Name: "System Integrity", Category: "System", GUID: "{0cce9212-69ae-11d9-bed3-505054503030}"},
+}
+
+// SubcategoryNames returns the names of the subcategories of the catalog, sorted
+func SubcategoryNames() []string {
+	out := []string{}
+	for _, s := range Subcategories {
+		out = append(out, s.Name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LookupSubcategory returns the subcategory of the catalog with the given name
+func LookupSubcategory(name string) (Subcategory, bool) {
+	for _, s := range Subcategories {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Subcategory{}, false
+}
+
+// LookupSubcategoryGUID returns the subcategory of the catalog with the given GUID. GUIDs are
+// compared case-insensitively.
+func LookupSubcategoryGUID(guid string) (Subcategory, bool) {
+	for _, s := range Subcategories {
+		if strings.EqualFold(s.GUID, guid) {
+			return s, true
+		}
+	}
+	return Subcategory{}, false
+}
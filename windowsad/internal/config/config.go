@@ -305,6 +305,8 @@ type ProviderConf struct {
 	winRMClients   []*winrm.Client
 	winRMCPClients []*winrmcp.Winrmcp
 	mx             *sync.Mutex
+	gpoLocks       *KeyedMutex
+	objectLocks    *KeyedMutex
 }
 
 func NewProviderConf(settings *Settings) *ProviderConf {
@@ -313,6 +315,8 @@ func NewProviderConf(settings *Settings) *ProviderConf {
 		winRMClients:   make([]*winrm.Client, 0),
 		winRMCPClients: make([]*winrmcp.Winrmcp, 0),
 		mx:             &sync.Mutex{},
+		gpoLocks:       NewKeyedMutex(),
+		objectLocks:    NewKeyedMutex(),
 	}
 	return pcfg
 }
@@ -363,6 +367,29 @@ func (pcfg *ProviderConf) ReleaseWinRMCPClient(winRMCPClient *winrmcp.Winrmcp) {
 	pcfg.winRMCPClients = append(pcfg.winRMCPClients, winRMCPClient)
 }
 
+// LockGPO serialises the operations done by the provider on a GPO, so that resources sharing a GPO
+// don't overwrite each other's files and versions. GUIDs are case-insensitive.
+func (pcfg *ProviderConf) LockGPO(guid string) {
+	pcfg.gpoLocks.Lock(strings.ToUpper(guid))
+}
+
+// UnlockGPO releases the lock taken by LockGPO.
+func (pcfg *ProviderConf) UnlockGPO(guid string) {
+	pcfg.gpoLocks.Unlock(strings.ToUpper(guid))
+}
+
+// LockObject serialises the changes done by the provider to the security descriptor of a directory
+// object, so that resources sharing an object don't overwrite each other's ACEs. DNs are
+// case-insensitive.
+func (pcfg *ProviderConf) LockObject(dn string) {
+	pcfg.objectLocks.Lock(strings.ToLower(dn))
+}
+
+// UnlockObject releases the lock taken by LockObject.
+func (pcfg *ProviderConf) UnlockObject(dn string) {
+	pcfg.objectLocks.Unlock(strings.ToLower(dn))
+}
+
 // IsConnectionTypeLocal check if connection is local
 func (pcfg *ProviderConf) IsConnectionTypeLocal() bool {
 	log.Printf("[DEBUG] Checking if connection should be local")
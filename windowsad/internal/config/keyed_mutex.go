@@ -0,0 +1,52 @@
+package config
+
+import "sync"
+
+// KeyedMutex is a set of mutexes identified by a key. It is used to serialise operations working
+// on the same object while letting operations on other objects run in parallel.
+type KeyedMutex struct {
+	mx    *sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mx   sync.Mutex
+	refs int
+}
+
+// NewKeyedMutex returns an empty KeyedMutex
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{
+		mx:    &sync.Mutex{},
+		locks: map[string]*keyedLock{},
+	}
+}
+
+// Lock locks the mutex of the given key, waiting until it is available
+func (k *KeyedMutex) Lock(key string) {
+	k.mx.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mx.Unlock()
+
+	l.mx.Lock()
+}
+
+// Unlock unlocks the mutex of the given key. Mutexes nobody waits for are forgotten.
+func (k *KeyedMutex) Unlock(key string) {
+	k.mx.Lock()
+	defer k.mx.Unlock()
+	l, ok := k.locks[key]
+	if !ok {
+		panic("config: unlock of unlocked key " + key)
+	}
+	l.refs--
+	if l.refs == 0 {
+		delete(k.locks, key)
+	}
+	l.mx.Unlock()
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestKeyedMutexSerialisesSameKey tests that operations on the same key don't overlap
+func TestKeyedMutexSerialisesSameKey(t *testing.T) {
+	k := NewKeyedMutex()
+	var wg sync.WaitGroup
+	var mx sync.Mutex
+	running, maxRunning := 0, 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k.Lock("gpo")
+			defer k.Unlock("gpo")
+
+			mx.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mx.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mx.Lock()
+			running--
+			mx.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Errorf("expected 1 operation at a time, got %d", maxRunning)
+	}
+	if len(k.locks) != 0 {
+		t.Errorf("expected all locks to be released, %d left", len(k.locks))
+	}
+}
+
+// TestKeyedMutexIndependentKeys tests that different keys don't block each other
+func TestKeyedMutexIndependentKeys(t *testing.T) {
+	k := NewKeyedMutex()
+	k.Lock("first")
+	defer k.Unlock("first")
+
+	done := make(chan struct{})
+	go func() {
+		k.Lock("second")
+		k.Unlock("second")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("lock of another key is blocked")
+	}
+}
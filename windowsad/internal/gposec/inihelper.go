@@ -46,6 +46,9 @@ type SecuritySettings struct {
 	*RegistryValues   `ini:"Registry Values,omitempty" mapstructure:"registry_values,omitempty"`
 	*SystemServices   `ini:"Service General Setting,omitempty" mapstructure:"system_services,omitempty"`
 	*FileSystem       `ini:"File Security,omitempty" mapstructure:"filesystem,omitempty"`
+	*PrivilegeRights  `ini:"Privilege Rights,omitempty" mapstructure:"privilege_rights,omitempty"`
+	// Security Options are spread over the System Access and Registry Values sections
+	*SecurityOptions `ini:"Security Options,omitempty" mapstructure:"security_options,omitempty"`
 }
 
 // PopulateSecuritySettings populates the SecuritySettings struct from resource data
@@ -140,6 +143,10 @@ func (s *SecuritySettings) GetSectionData(section string, d *schema.ResourceData
 		iniSection = s.RegistryKeys
 	case "filesystem":
 		iniSection = s.FileSystem
+	case "privilege_rights":
+		iniSection = s.PrivilegeRights
+	case "security_options":
+		iniSection = s.SecurityOptions
 	default:
 		return fmt.Errorf("key %q is unknown", section)
 	}
@@ -169,13 +176,16 @@ var ListSectionGeneratorMap = map[string]interface{}{
 
 // SetSectionGeneratorMap maps a schema name to a function that returns an INI section from resource data
 // The difference with the map above is that this one deals with schema elements that are Sets instead
-// of Lists and therefore require different handling.
+// of Lists and therefore require different handling. security_options is a List but its keys are written
+// to sections shared with other blocks, so it is handled here as well.
 var SetSectionGeneratorMap = map[string]interface{}{
 	"restricted_groups": NewRestrictedGroupsFromResource,
 	"registry_values":   NewRegistryValuesFromResource,
 	"system_services":   NewSystemServicesFromResource,
 	"registry_keys":     NewRegistryKeysFromResource,
 	"filesystem":        NewFileSystemFromResource,
+	"privilege_rights":  NewPrivilegeRightsFromResource,
+	"security_options":  NewSecurityOptionsFromResource,
 }
 
 // SetSectionParserMap maps INI section names to functions that parse the sections and populate
@@ -187,6 +197,7 @@ var SetSectionParserMap = map[string]interface{}{
 	"Registry Keys":           LoadRegistryKeysFromIni,
 	"Registry Values":         LoadRegistryValuesFromIni,
 	"File Security":           LoadFileSystemFromIni,
+	PrivilegeRightsSection:    LoadPrivilegeRightsFromIni,
 }
 
 // Most of the schema blocks in the resource's config are items of type List
@@ -260,6 +271,11 @@ func ParseIniFile(iniBytes []byte, utf16Decode bool) (*SecuritySettings, error)
 		}
 	}
 
+	err = LoadSecurityOptionsFromIni(f, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return cfg, err
 }
 
@@ -0,0 +1,341 @@
+package gposec
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/adschema"
+	"golang.org/x/text/encoding/unicode"
+	"gopkg.in/ini.v1"
+)
+
+// ownedSections maps the resource blocks that own a whole INF section to the section's name
+var ownedSections = map[string]string{
+	"kerberos_policy":   "Kerberos Policy",
+	"event_audit":       "Event Audit",
+	"system_log":        "System Log",
+	"audit_log":         "Security Log",
+	"application_log":   "Application Log",
+	"restricted_groups": "Group Membership",
+	"registry_keys":     "Registry Keys",
+	"system_services":   "Service General Setting",
+	"filesystem":        "File Security",
+	"privilege_rights":  PrivilegeRightsSection,
+}
+
+// iniOwnership tells which sections and keys of an INF file are managed by the resource. Blocks
+// sharing a section (System Access, Registry Values) only own their keys.
+type iniOwnership struct {
+	sections        map[string]bool
+	systemAccess    map[string]bool
+	registryValues  bool
+	securityOptions bool
+}
+
+// newIniOwnership returns the ownership of the given resource blocks
+func newIniOwnership(blocks []string) *iniOwnership {
+	o := &iniOwnership{sections: map[string]bool{}, systemAccess: map[string]bool{}}
+	for _, block := range blocks {
+		if section, ok := ownedSections[block]; ok {
+			o.sections[strings.ToLower(section)] = true
+			continue
+		}
+		switch block {
+		case "password_policies":
+			addFieldNames(o.systemAccess, PasswordPolicies{})
+		case "account_lockout":
+			addFieldNames(o.systemAccess, AccountLockout{})
+		case "registry_values":
+			o.registryValues = true
+		case "security_options":
+			o.securityOptions = true
+		}
+	}
+	return o
+}
+
+// addFieldNames adds the INI key names of the fields of a struct to keys
+func addFieldNames(keys map[string]bool, s interface{}) {
+	t := reflect.TypeOf(s)
+	for i := 0; i < t.NumField(); i++ {
+		keys[strings.ToLower(t.Field(i).Name)] = true
+	}
+}
+
+// ownsSection returns true if the whole section is managed by the resource
+func (o *iniOwnership) ownsSection(section string) bool {
+	return o.sections[strings.ToLower(section)]
+}
+
+// ownsKey returns true if the key of a section is managed by the resource
+func (o *iniOwnership) ownsKey(section, key string) bool {
+	if o.ownsSection(section) {
+		return true
+	}
+	isSystemAccess := strings.EqualFold(section, systemAccessSection)
+	isRegistryValues := strings.EqualFold(section, registryValuesSection)
+	if !isSystemAccess && !isRegistryValues {
+		return false
+	}
+	if _, ok := adschema.LookupSecurityOption(key); ok {
+		return o.securityOptions
+	}
+	if isRegistryValues {
+		return o.registryValues
+	}
+	return o.systemAccess[strings.ToLower(key)]
+}
+
+// infSection is a section of an INF file kept as raw lines. The preamble of the file is a section
+// without a name.
+type infSection struct {
+	name   string
+	header string
+	lines  []string
+}
+
+// infFile is an INF file kept as raw lines, so that what isn't modified is written back unchanged
+type infFile struct {
+	utf16     bool
+	utf8BOM   bool
+	lineBreak string
+	sections  []*infSection
+}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// parseInfFile splits the contents of an INF file in sections. UTF-16LE files with a Byte Order
+// Mark are decoded.
+func parseInfFile(contents []byte) (*infFile, error) {
+	f := &infFile{lineBreak: "\r\n"}
+	if bytes.HasPrefix(contents, []byte{0xFF, 0xFE}) {
+		decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(contents)
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding INF file from UTF16-LE: %s", err)
+		}
+		contents = decoded
+		f.utf16 = true
+	} else if bytes.HasPrefix(contents, utf8BOM) {
+		contents = contents[len(utf8BOM):]
+		f.utf8BOM = true
+	}
+
+	text := string(contents)
+	if strings.Contains(text, "\n") && !strings.Contains(text, "\r\n") {
+		f.lineBreak = "\n"
+	}
+
+	current := &infSection{}
+	f.sections = append(f.sections, current)
+	if text == "" {
+		return f, nil
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			current = &infSection{name: strings.TrimSpace(trimmed[1 : len(trimmed)-1]), header: line}
+			f.sections = append(f.sections, current)
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	return f, nil
+}
+
+// getSection returns the section with the given name, or nil
+func (f *infFile) getSection(name string) *infSection {
+	for _, s := range f.sections[1:] {
+		if strings.EqualFold(s.name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// hasSettings returns true if the section holds at least one setting
+func (s *infSection) hasSettings() bool {
+	for _, line := range s.lines {
+		if isSettingLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// bytes returns the contents of the file, encoded the way it was read
+func (f *infFile) bytes() ([]byte, error) {
+	var sb strings.Builder
+	for _, s := range f.sections {
+		sb.WriteString(s.header)
+		for _, line := range s.lines {
+			sb.WriteString(line)
+		}
+	}
+	out := []byte(sb.String())
+	if f.utf16 {
+		encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes(out)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode INF file to UTF16-LE with BOM, error: %s", err)
+		}
+		return encoded, nil
+	}
+	if f.utf8BOM {
+		return append(append([]byte{}, utf8BOM...), out...), nil
+	}
+	return out, nil
+}
+
+// lineKey returns the key of an INF line, or the whole line if it isn't a key=value pair
+func lineKey(line string) string {
+	line = strings.TrimSpace(line)
+	if idx := strings.Index(line, "="); idx >= 0 {
+		return strings.TrimSpace(line[:idx])
+	}
+	return line
+}
+
+// isSettingLine returns false for blank lines and comments
+func isSettingLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" && !strings.HasPrefix(trimmed, ";")
+}
+
+// withLineBreak returns the line terminated by the given line break
+func withLineBreak(line, lineBreak string) string {
+	return strings.TrimRight(line, "\r\n") + lineBreak
+}
+
+// MergeIniContents merges the INI file generated from the resource's data into the contents of the
+// INF file found on the host. Only the sections and keys owned by the given resource blocks are
+// replaced: everything else, including the file's encoding, is preserved byte for byte. If the
+// host has no file, the generated file is returned.
+func MergeIniContents(hostContents []byte, iniFile *ini.File, blocks []string) ([]byte, error) {
+	ini.LineBreak = "\r\n"
+	buf := bytes.NewBuffer([]byte{})
+	_, err := iniFile.WriteTo(buf)
+	if err != nil {
+		return nil, fmt.Errorf("error while writing INI file in buffer: %s", err)
+	}
+	if len(hostContents) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	generated, err := parseInfFile(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	host, err := parseInfFile(hostContents)
+	if err != nil {
+		return nil, err
+	}
+	owner := newIniOwnership(blocks)
+
+	// remove what the resource owns from the host's file. Shared sections left without settings
+	// are dropped once the generated keys are added.
+	sections := []*infSection{host.sections[0]}
+	emptied := map[*infSection]bool{}
+	for _, s := range host.sections[1:] {
+		if owner.ownsSection(s.name) {
+			continue
+		}
+		lines := []string{}
+		removed := false
+		for _, line := range s.lines {
+			if isSettingLine(line) && owner.ownsKey(s.name, lineKey(line)) {
+				removed = true
+				continue
+			}
+			lines = append(lines, line)
+		}
+		s.lines = lines
+		emptied[s] = removed
+		sections = append(sections, s)
+	}
+	host.sections = sections
+
+	// add the owned keys of the generated file, and its sections missing from the host's file
+	for _, gs := range generated.sections[1:] {
+		lines := []string{}
+		for _, line := range gs.lines {
+			if isSettingLine(line) && owner.ownsKey(gs.name, lineKey(line)) {
+				lines = append(lines, withLineBreak(line, host.lineBreak))
+			}
+		}
+
+		hs := host.getSection(gs.name)
+		if hs == nil {
+			if len(lines) == 0 {
+				if !isHeaderSection(gs.name) {
+					continue
+				}
+				for _, line := range gs.lines {
+					if isSettingLine(line) {
+						lines = append(lines, withLineBreak(line, host.lineBreak))
+					}
+				}
+			}
+			last := host.sections[len(host.sections)-1]
+			if n := len(last.lines); n > 0 && !strings.HasSuffix(last.lines[n-1], "\n") {
+				last.lines[n-1] = last.lines[n-1] + host.lineBreak
+			} else if n == 0 && last.header != "" && !strings.HasSuffix(last.header, "\n") {
+				last.header = last.header + host.lineBreak
+			}
+			host.sections = append(host.sections, &infSection{
+				name:   gs.name,
+				header: withLineBreak(gs.header, host.lineBreak),
+				lines:  lines,
+			})
+			continue
+		}
+
+		// insert the lines after the last setting of the section, before trailing blank lines
+		insertAt := len(hs.lines)
+		for insertAt > 0 && !isSettingLine(hs.lines[insertAt-1]) {
+			insertAt--
+		}
+		if insertAt > 0 && !strings.HasSuffix(hs.lines[insertAt-1], "\n") {
+			hs.lines[insertAt-1] = hs.lines[insertAt-1] + host.lineBreak
+		} else if insertAt == 0 && !strings.HasSuffix(hs.header, "\n") {
+			hs.header = hs.header + host.lineBreak
+		}
+		merged := append([]string{}, hs.lines[:insertAt]...)
+		merged = append(merged, lines...)
+		hs.lines = append(merged, hs.lines[insertAt:]...)
+	}
+
+	sections = []*infSection{}
+	for _, s := range host.sections {
+		if emptied[s] && !s.hasSettings() {
+			continue
+		}
+		sections = append(sections, s)
+	}
+	host.sections = sections
+
+	return host.bytes()
+}
+
+// isHeaderSection returns true for the [Unicode] and [Version] sections every INF file starts with
+func isHeaderSection(name string) bool {
+	return strings.EqualFold(name, "Unicode") || strings.EqualFold(name, "Version")
+}
+
+// HasSettings returns true if the INF file holds settings other than the [Unicode] and [Version]
+// headers
+func HasSettings(contents []byte) (bool, error) {
+	f, err := parseInfFile(contents)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range f.sections[1:] {
+		if !isHeaderSection(s.name) && s.hasSettings() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package gposec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+	"gopkg.in/ini.v1"
+)
+
+const testHostInf = "[Unicode]\r\nUnicode=yes\r\n" +
+	"[System Access]\r\nMinimumPasswordAge = 1\r\nMinimumPasswordLength = 7\r\nLSAAnonymousNameLookup = 0\r\n" +
+	"[Privilege Rights]\r\nSeInteractiveLogonRight = *S-1-5-32-544\r\n" +
+	"[Registry Values]\r\nMACHINE\\System\\CurrentControlSet\\Control\\Lsa\\NoLMHash=4,1\r\nMACHINE\\Software\\Custom\\Value=4,0\r\n" +
+	"[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1\r\n"
+
+// testGeneratedIni returns an INI file as generated from a resource setting the password policies
+// and the security options
+func testGeneratedIni(t *testing.T) *ini.File {
+	f := ini.Empty(ini.LoadOptions{AllowBooleanKeys: true, KeyValueDelimiterOnWrite: "=", KeyValueDelimiters: "=", IgnoreInlineComment: true})
+	cfg := NewSecuritySettings()
+	cfg.SystemAccess = &SystemAccess{PasswordPolicies: &PasswordPolicies{MinimumPasswordLength: "14"}}
+	if err := f.ReflectFrom(cfg); err != nil {
+		t.Fatal(err)
+	}
+	err := (&SecurityOptions{Options: map[string]string{"no_lm_hash": "0"}}).SetIniData(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestMergeIniContentsPreservesUnmanaged(t *testing.T) {
+	got, err := MergeIniContents([]byte(testHostInf), testGeneratedIni(t), []string{"password_policies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[Unicode]\r\nUnicode=yes\r\n" +
+		"[System Access]\r\nLSAAnonymousNameLookup = 0\r\nMinimumPasswordLength = 14\r\n" +
+		"[Privilege Rights]\r\nSeInteractiveLogonRight = *S-1-5-32-544\r\n" +
+		"[Registry Values]\r\nMACHINE\\System\\CurrentControlSet\\Control\\Lsa\\NoLMHash=4,1\r\nMACHINE\\Software\\Custom\\Value=4,0\r\n" +
+		"[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1\r\n"
+	if string(got) != expected {
+		t.Errorf("MergeIniContents() = %q, expected %q", got, expected)
+	}
+}
+
+func TestMergeIniContentsSharedSections(t *testing.T) {
+	got, err := MergeIniContents([]byte(testHostInf), testGeneratedIni(t), []string{"password_policies", "security_options", "privilege_rights"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(got)
+	for _, unexpected := range []string{"[Privilege Rights]", "LSAAnonymousNameLookup", "NoLMHash=4,1", "MinimumPasswordAge"} {
+		if strings.Contains(s, unexpected) {
+			t.Errorf("merged file contains %q: %q", unexpected, s)
+		}
+	}
+	for _, expected := range []string{"MACHINE\\Software\\Custom\\Value=4,0\r\n", "NoLMHash=4,0\r\n", "MinimumPasswordLength = 14\r\n"} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("merged file does not contain %q: %q", expected, s)
+		}
+	}
+}
+
+func TestMergeIniContentsNoChange(t *testing.T) {
+	host := "[Unicode]\r\nUnicode=yes\r\n[System Access]\r\nMinimumPasswordLength = 14\r\n[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1\r\n"
+	got, err := MergeIniContents([]byte(host), testGeneratedIni(t), []string{"password_policies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != host {
+		t.Errorf("MergeIniContents() = %q, expected %q", got, host)
+	}
+}
+
+func TestMergeIniContentsUTF16(t *testing.T) {
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()
+	host, err := encoder.Bytes([]byte(testHostInf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := MergeIniContents(host, testGeneratedIni(t), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, host) {
+		t.Errorf("MergeIniContents() did not preserve the UTF-16 file: %q", got)
+	}
+}
+
+func TestMergeIniContentsNewSection(t *testing.T) {
+	host := "[Unicode]\r\nUnicode=yes\r\n[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1"
+	got, err := MergeIniContents([]byte(host), testGeneratedIni(t), []string{"password_policies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := host + "\r\n[System Access]\r\nMinimumPasswordLength = 14\r\n"
+	if string(got) != expected {
+		t.Errorf("MergeIniContents() = %q, expected %q", got, expected)
+	}
+}
+
+func TestMergeIniContentsEmptyHost(t *testing.T) {
+	got, err := MergeIniContents(nil, testGeneratedIni(t), []string{"password_policies"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "[Unicode]") || !strings.Contains(string(got), "MinimumPasswordLength") {
+		t.Errorf("MergeIniContents() = %q, expected the generated file", got)
+	}
+}
+
+func TestHasSettings(t *testing.T) {
+	headers := "[Unicode]\r\nUnicode=yes\r\n[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1\r\n"
+	if ok, err := HasSettings([]byte(headers)); err != nil || ok {
+		t.Errorf("HasSettings(headers) = %t, %v, expected false", ok, err)
+	}
+	if ok, err := HasSettings([]byte(testHostInf)); err != nil || !ok {
+		t.Errorf("HasSettings(testHostInf) = %t, %v, expected true", ok, err)
+	}
+}
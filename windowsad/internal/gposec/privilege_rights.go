@@ -0,0 +1,103 @@
+package gposec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/ini.v1"
+)
+
+// PrivilegeRightsSection is the name of the INI section holding the user rights assignments
+const PrivilegeRightsSection = "Privilege Rights"
+
+// PrivilegeRight represents a user right and the principals it is assigned to. Principals are
+// either account names or SIDs prefixed with a *, as found in the INF file.
+type PrivilegeRight struct {
+	Privilege  string
+	Principals []string
+}
+
+// PrivilegeRights represents the Privilege Rights (User Rights Assignment) section of the Security
+// Settings GPO extension
+type PrivilegeRights struct {
+	Rights []PrivilegeRight `mapstructure:"omitempty"`
+}
+
+// SetResourceData populates resource data based on the PrivilegeRights field values
+func (r *PrivilegeRights) SetResourceData(section string, d *schema.ResourceData) error {
+	out := []map[string]interface{}{}
+	for _, right := range r.Rights {
+		principals := []interface{}{}
+		for _, p := range right.Principals {
+			principals = append(principals, p)
+		}
+		out = append(out, map[string]interface{}{
+			"privilege":  right.Privilege,
+			"principals": principals,
+		})
+	}
+	//lintignore:R001
+	return d.Set(section, out)
+}
+
+// SetIniData populates the INI file with data from this struct
+func (r *PrivilegeRights) SetIniData(f *ini.File) error {
+	if len(r.Rights) == 0 {
+		return nil
+	}
+	section, err := f.NewSection(PrivilegeRightsSection)
+	if err != nil {
+		return fmt.Errorf("error while creation INI Section %q", PrivilegeRightsSection)
+	}
+
+	for _, right := range r.Rights {
+		_, err := section.NewKey(right.Privilege, strings.Join(right.Principals, ","))
+		if err != nil {
+			return fmt.Errorf("error while creating new key for privilege %q: %s", right.Privilege, err)
+		}
+	}
+	return nil
+}
+
+// NewPrivilegeRightsFromResource returns a new struct based on the resource's values
+func NewPrivilegeRightsFromResource(data interface{}) (IniSetSection, error) {
+	out := &PrivilegeRights{Rights: []PrivilegeRight{}}
+	for _, item := range data.(*schema.Set).List() {
+		pr := item.(map[string]interface{})
+		right := PrivilegeRight{
+			Privilege:  pr["privilege"].(string),
+			Principals: []string{},
+		}
+		for _, p := range pr["principals"].(*schema.Set).List() {
+			right.Principals = append(right.Principals, p.(string))
+		}
+		sort.Strings(right.Principals)
+		out.Rights = append(out.Rights, right)
+	}
+	sort.Slice(out.Rights, func(i, j int) bool { return out.Rights[i].Privilege < out.Rights[j].Privilege })
+	return out, nil
+}
+
+// LoadPrivilegeRightsFromIni loads the data from the related INI section inside the given SecuritySettings
+// struct
+func LoadPrivilegeRightsFromIni(sectionName string, iniFile *ini.File, cfg *SecuritySettings) error {
+	section, err := iniFile.GetSection(sectionName)
+	if err != nil {
+		return fmt.Errorf("error while parsing section %q: %s", sectionName, err)
+	}
+	out := &PrivilegeRights{Rights: []PrivilegeRight{}}
+	for _, key := range section.Keys() {
+		right := PrivilegeRight{Privilege: key.Name(), Principals: []string{}}
+		for _, p := range strings.Split(key.Value(), ",") {
+			p = strings.TrimSpace(p)
+			if p != "" {
+				right.Principals = append(right.Principals, p)
+			}
+		}
+		out.Rights = append(out.Rights, right)
+	}
+	cfg.PrivilegeRights = out
+	return nil
+}
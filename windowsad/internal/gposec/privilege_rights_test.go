@@ -0,0 +1,180 @@
+package gposec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/adschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/ini.v1"
+)
+
+func newPRFromResource() (*PrivilegeRights, error) {
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+
+	rData := []map[string]interface{}{
+		{
+			"privilege":  "SeServiceLogonRight",
+			"principals": []interface{}{"CONTOSO\\svc_sql", "*S-1-5-20"},
+		},
+		{
+			"privilege":  "SeDenyNetworkLogonRight",
+			"principals": []interface{}{"*S-1-5-32-546"},
+		},
+		{
+			"privilege":  "SeTcbPrivilege",
+			"principals": []interface{}{},
+		},
+	}
+	err := d.Set("privilege_rights", rData)
+	if err != nil {
+		return nil, err
+	}
+
+	prSection, err := NewPrivilegeRightsFromResource(d.Get("privilege_rights"))
+	if err != nil {
+		return nil, err
+	}
+	return prSection.(*PrivilegeRights), nil
+}
+
+func TestNewPrivilegeRightsFromResource(t *testing.T) {
+	pr, err := newPRFromResource()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	expected := []PrivilegeRight{
+		{Privilege: "SeDenyNetworkLogonRight", Principals: []string{"*S-1-5-32-546"}},
+		{Privilege: "SeServiceLogonRight", Principals: []string{"*S-1-5-20", "CONTOSO\\svc_sql"}},
+		{Privilege: "SeTcbPrivilege", Principals: []string{}},
+	}
+	if !reflect.DeepEqual(pr.Rights, expected) {
+		t.Errorf("unexpected privilege rights. expected %v got %v", expected, pr.Rights)
+	}
+}
+
+func TestPrivilegeRightsSetIniData(t *testing.T) {
+	pr, err := newPRFromResource()
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	loadOpts := ini.LoadOptions{
+		AllowBooleanKeys:         true,
+		KeyValueDelimiterOnWrite: "=",
+		KeyValueDelimiters:       "=",
+		IgnoreInlineComment:      true,
+	}
+	iniFile := ini.Empty(loadOpts)
+
+	err = pr.SetIniData(iniFile)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	section := iniFile.Section(PrivilegeRightsSection)
+	expected := map[string]string{
+		"SeDenyNetworkLogonRight": "*S-1-5-32-546",
+		"SeServiceLogonRight":     "*S-1-5-20,CONTOSO\\svc_sql",
+		"SeTcbPrivilege":          "",
+	}
+	for name, value := range expected {
+		key, err := section.GetKey(name)
+		if err != nil {
+			t.Errorf("key %s wasn't found: %s", name, err)
+			continue
+		}
+		if key.Value() != value {
+			t.Errorf("unexpected value for %s. expected %q got %q", name, value, key.Value())
+		}
+	}
+
+	iniFile = ini.Empty(loadOpts)
+	err = (&PrivilegeRights{}).SetIniData(iniFile)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, err := iniFile.GetSection(PrivilegeRightsSection); err == nil {
+		t.Errorf("empty PrivilegeRights should not create a section")
+	}
+}
+
+func TestLoadPrivilegeRightsFromIni(t *testing.T) {
+	iniData := "[Unicode]\r\nUnicode=yes\r\n" +
+		"[Privilege Rights]\r\n" +
+		"SeDenyNetworkLogonRight = *S-1-5-32-546\r\n" +
+		"SeServiceLogonRight = *S-1-5-20,*S-1-5-21-1-2-3-1105\r\n" +
+		"SeTcbPrivilege = \r\n"
+
+	cfg, err := ParseIniFile([]byte(iniData), false)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	if cfg.PrivilegeRights == nil {
+		t.Errorf("PrivilegeRights struct is nil.")
+		t.FailNow()
+	}
+
+	expected := []PrivilegeRight{
+		{Privilege: "SeDenyNetworkLogonRight", Principals: []string{"*S-1-5-32-546"}},
+		{Privilege: "SeServiceLogonRight", Principals: []string{"*S-1-5-20", "*S-1-5-21-1-2-3-1105"}},
+		{Privilege: "SeTcbPrivilege", Principals: []string{}},
+	}
+	if !reflect.DeepEqual(cfg.PrivilegeRights.Rights, expected) {
+		t.Errorf("unexpected privilege rights. expected %v got %v", expected, cfg.PrivilegeRights.Rights)
+	}
+}
+
+func TestPrivilegeRightsSetResourceData(t *testing.T) {
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+
+	pr := &PrivilegeRights{
+		Rights: []PrivilegeRight{
+			{Privilege: "SeInteractiveLogonRight", Principals: []string{"BUILTIN\\Administrators"}},
+		},
+	}
+	err := pr.SetResourceData("privilege_rights", d)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	prSet := d.Get("privilege_rights").(*schema.Set)
+	if prSet.Len() != 1 {
+		t.Errorf("unexpected number of privilege rights. expected 1 got %d", prSet.Len())
+		t.FailNow()
+	}
+	item := prSet.List()[0].(map[string]interface{})
+	principals := item["principals"].(*schema.Set).List()
+	if item["privilege"] != "SeInteractiveLogonRight" || len(principals) != 1 || principals[0] != "BUILTIN\\Administrators" {
+		t.Errorf("unexpected privilege right %v", item)
+	}
+}
+
+func TestGetSectionDataWithoutPrivilegeRights(t *testing.T) {
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+
+	cfg, err := ParseIniFile([]byte("[Unicode]\r\nUnicode=yes\r\n"), false)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	err = cfg.GetSectionData("privilege_rights", d)
+	if err != nil {
+		t.Error(err)
+	}
+	if d.Get("privilege_rights").(*schema.Set).Len() != 0 {
+		t.Errorf("privilege_rights should be empty")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/adschema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"gopkg.in/ini.v1"
 )
@@ -14,15 +15,45 @@ type RegistryValues struct {
 	Values []string
 }
 
+// parseRegistryValueLine splits a line of the Registry Values section into the key name, the value
+// type and the data. GPMC writes the lines as KEY=type,data while this provider writes them as
+// "KEY",type,"data".
+func parseRegistryValueLine(line string) (string, string, string, error) {
+	var keyName, rest string
+	equals, comma := strings.Index(line, "="), strings.Index(line, ",")
+	switch {
+	case strings.HasPrefix(line, `"`):
+		end := strings.Index(line[1:], `"`) + 1
+		if end == 0 || !strings.HasPrefix(line[end+1:], ",") {
+			return "", "", "", fmt.Errorf("malformed registry value %q: expected \"KEY\",type,\"value\"", line)
+		}
+		keyName, rest = line[1:end], line[end+2:]
+	case equals >= 0 && (comma < 0 || equals < comma):
+		keyName, rest = strings.TrimSpace(line[:equals]), strings.TrimSpace(line[equals+1:])
+	case comma >= 0:
+		keyName, rest = line[:comma], line[comma+1:]
+	default:
+		return "", "", "", fmt.Errorf("malformed registry value %q: expected KEY=type,value", line)
+	}
+	parts := strings.SplitN(rest, ",", 2)
+	if keyName == "" || len(parts) != 2 || parts[0] == "" {
+		return "", "", "", fmt.Errorf("malformed registry value %q: expected KEY=type,value", line)
+	}
+	return keyName, parts[0], strings.Trim(parts[1], `"`), nil
+}
+
 // SetResourceData populates the resource's filed for the given section using the struct's data.
 func (r *RegistryValues) SetResourceData(section string, d *schema.ResourceData) error {
 	out := []map[string]interface{}{}
 	for _, valuesLine := range r.Values {
-		values := strings.SplitN(valuesLine, ",", 3)
+		keyName, valueType, data, err := parseRegistryValueLine(valuesLine)
+		if err != nil {
+			return err
+		}
 		value := map[string]interface{}{
-			"key_name":   values[0],
-			"value_type": values[1],
-			"value":      values[2],
+			"key_name":   keyName,
+			"value_type": valueType,
+			"value":      data,
 		}
 		out = append(out, value)
 	}
@@ -30,18 +61,10 @@ func (r *RegistryValues) SetResourceData(section string, d *schema.ResourceData)
 	return d.Set(section, out)
 }
 
-// SetIniData populates the INI file with data from this struct
+// SetIniData populates the INI file with data from this struct. The section is shared with the
+// security options, so the values are appended to it.
 func (r *RegistryValues) SetIniData(f *ini.File) error {
-	if len(r.Values) == 0 {
-		return nil
-	}
-	sectionName := "Registry Values"
-	sectionBody := strings.Join(r.Values, "\r\n")
-	_, err := f.NewRawSection(sectionName, fmt.Sprintf("%s\r\n", sectionBody))
-	if err != nil {
-		return fmt.Errorf("error while setting section %q: %s", sectionName, err)
-	}
-	return nil
+	return appendRawSectionLines(f, registryValuesSection, r.Values)
 }
 
 // NewRegistryValuesFromResource returns a new struct based on the resoruce's values
@@ -62,7 +85,25 @@ func LoadRegistryValuesFromIni(sectionName string, iniFile *ini.File, cfg *Secur
 	if err != nil {
 		return fmt.Errorf("error while parsing section %q: %s", sectionName, err)
 	}
-	cfg.RegistryValues = &RegistryValues{Values: section.KeyStrings()}
+	values := []string{}
+	for _, key := range section.Keys() {
+		// the values of the security options catalog are loaded by LoadSecurityOptionsFromIni and
+		// rejected by the registry_values schema
+		if _, ok := adschema.LookupSecurityOption(key.Name()); ok {
+			continue
+		}
+		// lines written by this provider have no delimiter and are loaded as boolean keys, the
+		// KEY=type,value lines written by GPMC are split by the ini parser
+		line := key.Name()
+		if value := key.Value(); value != "true" {
+			line = line + "=" + value
+		}
+		if _, _, _, err := parseRegistryValueLine(line); err != nil {
+			return fmt.Errorf("error while parsing section %q: %s", sectionName, err)
+		}
+		values = append(values, line)
+	}
+	cfg.RegistryValues = &RegistryValues{Values: values}
 
 	return nil
 }
@@ -173,3 +173,73 @@ func TestLoadRegistryValuesFromIni(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParseRegistryValueLine(t *testing.T) {
+	tests := []struct {
+		line      string
+		keyName   string
+		valueType string
+		value     string
+		err       bool
+	}{
+		{`MACHINE\Software\Foo\Bar=4,1`, `MACHINE\Software\Foo\Bar`, "4", "1", false},
+		{`MACHINE\Software\Foo\Text=1,"a,b=c"`, `MACHINE\Software\Foo\Text`, "1", "a,b=c", false},
+		{`"MACHINE\Software\Foo\Bar",4,"1"`, `MACHINE\Software\Foo\Bar`, "4", "1", false},
+		{`HKLM\Some\Key,2,keyvalue`, `HKLM\Some\Key`, "2", "keyvalue", false},
+		{`MACHINE\Software\Foo\Bar`, "", "", "", true},
+		{`MACHINE\Software\Foo\Bar=4`, "", "", "", true},
+		{`"MACHINE\Software\Foo\Bar`, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		keyName, valueType, value, err := parseRegistryValueLine(tt.line)
+		if (err != nil) != tt.err {
+			t.Errorf("parseRegistryValueLine(%q) returned error %v, expected error: %t", tt.line, err, tt.err)
+			continue
+		}
+		if keyName != tt.keyName || valueType != tt.valueType || value != tt.value {
+			t.Errorf("parseRegistryValueLine(%q) = %q, %q, %q, want %q, %q, %q", tt.line, keyName, valueType, value, tt.keyName, tt.valueType, tt.value)
+		}
+	}
+}
+
+func TestRegistryValuesImportGPMCIni(t *testing.T) {
+	// GptTmpl.inf as written by GPMC, with the security options and other registry values mixed
+	iniData := "[Unicode]\r\nUnicode=yes\r\n" +
+		"[Registry Values]\r\n" +
+		"MACHINE\\System\\CurrentControlSet\\Control\\Lsa\\NoLMHash=4,1\r\n" +
+		"MACHINE\\Software\\Foo\\Bar=4,1\r\n" +
+		"[Version]\r\nsignature=\"$CHICAGO$\"\r\nRevision=1\r\n"
+
+	cfg, err := ParseIniFile([]byte(iniData), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+	err = HandleSectionRead(adschema.GPOSecuritySchemaKeys, cfg, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := d.Get("registry_values").(*schema.Set).List()
+	if len(values) != 1 {
+		t.Fatalf("expected 1 registry value, got %d: %v", len(values), values)
+	}
+	value := values[0].(map[string]interface{})
+	if value["key_name"] != `MACHINE\Software\Foo\Bar` || value["value_type"] != "4" || value["value"] != "1" {
+		t.Errorf(`unexpected registry value. Expected MACHINE\Software\Foo\Bar,4,1 got %v`, value)
+	}
+	if v := d.Get("security_options.0.no_lm_hash").(string); v != "1" {
+		t.Errorf("unexpected value for no_lm_hash. expected 1 got %q", v)
+	}
+}
+
+func TestLoadRegistryValuesFromIniMalformed(t *testing.T) {
+	_, err := ParseIniFile([]byte("[Registry Values]\r\nMACHINE\\Software\\Foo\\Bar=4\r\n"), false)
+	if err == nil || !strings.Contains(err.Error(), "malformed registry value") {
+		t.Errorf("expected a malformed registry value error, got %v", err)
+	}
+}
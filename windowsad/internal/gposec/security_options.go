@@ -0,0 +1,135 @@
+package gposec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/adschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	systemAccessSection   = "System Access"
+	registryValuesSection = "Registry Values"
+)
+
+// SecurityOptions holds the values of the Security Options of the adschema.SecurityOptions catalog,
+// keyed by their name in the resource's schema
+type SecurityOptions struct {
+	Options map[string]string
+}
+
+// SetResourceData populates resource data based on the SecurityOptions field values
+func (r *SecurityOptions) SetResourceData(section string, d *schema.ResourceData) error {
+	out := map[string]interface{}{}
+	for name, value := range r.Options {
+		out[name] = value
+	}
+	//lintignore:R001
+	return d.Set(section, []map[string]interface{}{out})
+}
+
+// SetIniData populates the INI file with data from this struct. Options are added to the System
+// Access and Registry Values sections, next to the keys other blocks write there.
+func (r *SecurityOptions) SetIniData(f *ini.File) error {
+	if len(r.Options) == 0 {
+		return nil
+	}
+
+	registryLines := []string{}
+	for _, opt := range adschema.SecurityOptions {
+		value, ok := r.Options[opt.Name]
+		if !ok {
+			continue
+		}
+		if opt.ValueType == adschema.SecurityOptionsSystemAccess {
+			if _, err := strconv.Atoi(value); err != nil {
+				value = fmt.Sprintf(`"%s"`, value)
+			}
+			_, err := f.Section(systemAccessSection).NewKey(opt.Key, value)
+			if err != nil {
+				return fmt.Errorf("error while creating new key for security option %q: %s", opt.Name, err)
+			}
+			continue
+		}
+		registryLines = append(registryLines, fmt.Sprintf("%s=%s", opt.Key, formatRegistryValue(opt.ValueType, value)))
+	}
+	return appendRawSectionLines(f, registryValuesSection, registryLines)
+}
+
+// formatRegistryValue returns the value of a registry value line, made of the type and the data
+func formatRegistryValue(valueType int, value string) string {
+	if valueType == adschema.RegistryValueString {
+		value = fmt.Sprintf(`"%s"`, value)
+	}
+	return fmt.Sprintf("%d,%s", valueType, value)
+}
+
+// parseRegistryValue returns the data of a registry value line, without the type and the quotes
+// around strings
+func parseRegistryValue(line string) string {
+	parts := strings.SplitN(line, ",", 2)
+	if len(parts) != 2 {
+		return line
+	}
+	return strings.Trim(parts[1], `"`)
+}
+
+// appendRawSectionLines adds lines at the end of a raw section, creating it if needed
+func appendRawSectionLines(f *ini.File, sectionName string, lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	body := ""
+	// Body() trims the line break at the end of the section
+	if section, err := f.GetSection(sectionName); err == nil && section.Body() != "" {
+		body = section.Body() + "\r\n"
+	}
+	body = body + strings.Join(lines, "\r\n") + "\r\n"
+	_, err := f.NewRawSection(sectionName, body)
+	if err != nil {
+		return fmt.Errorf("error while setting section %q: %s", sectionName, err)
+	}
+	return nil
+}
+
+// NewSecurityOptionsFromResource returns a new struct based on the resource's values
+func NewSecurityOptionsFromResource(data interface{}) (IniSetSection, error) {
+	out := &SecurityOptions{Options: map[string]string{}}
+	l := data.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return out, nil
+	}
+	for name, value := range l[0].(map[string]interface{}) {
+		if value.(string) != "" {
+			out.Options[name] = value.(string)
+		}
+	}
+	return out, nil
+}
+
+// LoadSecurityOptionsFromIni loads the Security Options of the catalog found in the System Access and
+// Registry Values sections inside the given SecuritySettings struct
+func LoadSecurityOptionsFromIni(iniFile *ini.File, cfg *SecuritySettings) error {
+	options := map[string]string{}
+	if section, err := iniFile.GetSection(systemAccessSection); err == nil {
+		for _, key := range section.Keys() {
+			if opt, ok := adschema.LookupSecurityOption(key.Name()); ok && opt.ValueType == adschema.SecurityOptionsSystemAccess {
+				options[opt.Name] = strings.Trim(key.Value(), `"`)
+			}
+		}
+	}
+	if section, err := iniFile.GetSection(registryValuesSection); err == nil {
+		for _, key := range section.Keys() {
+			if opt, ok := adschema.LookupSecurityOption(key.Name()); ok && opt.ValueType != adschema.SecurityOptionsSystemAccess {
+				options[opt.Name] = parseRegistryValue(key.Value())
+			}
+		}
+	}
+	if len(options) > 0 {
+		cfg.SecurityOptions = &SecurityOptions{Options: options}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package gposec
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/adschema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/ini.v1"
+)
+
+func newSecurityOptionsIni(t *testing.T) *ini.File {
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+
+	err := d.Set("security_options", []map[string]interface{}{
+		{
+			"enable_guest_account":   "0",
+			"new_administrator_name": "localadm",
+			"lm_compatibility_level": "5",
+			"no_lm_hash":             "1",
+			"legal_notice_caption":   "Authorized use only",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.Set("registry_values", []map[string]interface{}{
+		{
+			"key_name":   `MACHINE\Software\Example\Value`,
+			"value_type": "4",
+			"value":      "1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loadOpts := ini.LoadOptions{
+		AllowBooleanKeys:         true,
+		KeyValueDelimiterOnWrite: "=",
+		KeyValueDelimiters:       "=",
+		IgnoreInlineComment:      true,
+	}
+	iniFile := ini.Empty(loadOpts)
+	cfg := NewSecuritySettings()
+	err = iniFile.ReflectFrom(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.PopulateSecuritySettings(d, iniFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return iniFile
+}
+
+func TestSecurityOptionsSetIniData(t *testing.T) {
+	iniFile := newSecurityOptionsIni(t)
+
+	sa := iniFile.Section("System Access")
+	if v := sa.Key("EnableGuestAccount").Value(); v != "0" {
+		t.Errorf("unexpected value for EnableGuestAccount. expected 0 got %q", v)
+	}
+	if v := sa.Key("NewAdministratorName").Value(); v != `"localadm"` {
+		t.Errorf(`unexpected value for NewAdministratorName. expected "localadm" got %q`, v)
+	}
+
+	body := iniFile.Section("Registry Values").Body()
+	for _, expected := range []string{
+		`MACHINE\System\CurrentControlSet\Control\Lsa\LmCompatibilityLevel=4,5`,
+		`MACHINE\System\CurrentControlSet\Control\Lsa\NoLMHash=4,1`,
+		`MACHINE\Software\Microsoft\Windows\CurrentVersion\Policies\System\LegalNoticeCaption=1,"Authorized use only"`,
+		`"MACHINE\Software\Example\Value",4,"1"`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Registry Values section does not contain %q, body: %s", expected, body)
+		}
+	}
+}
+
+func TestLoadSecurityOptionsFromIni(t *testing.T) {
+	iniFile := newSecurityOptionsIni(t)
+	buf := bytes.NewBuffer([]byte{})
+	_, err := iniFile.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ParseIniFile(buf.Bytes(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SecurityOptions == nil {
+		t.Fatal("SecurityOptions struct is nil.")
+	}
+	expected := map[string]string{
+		"enable_guest_account":   "0",
+		"new_administrator_name": "localadm",
+		"lm_compatibility_level": "5",
+		"no_lm_hash":             "1",
+		"legal_notice_caption":   "Authorized use only",
+	}
+	for name, value := range expected {
+		if cfg.SecurityOptions.Options[name] != value {
+			t.Errorf("unexpected value for %s. expected %q got %q", name, value, cfg.SecurityOptions.Options[name])
+		}
+	}
+	if len(cfg.SecurityOptions.Options) != len(expected) {
+		t.Errorf("unexpected security options %v", cfg.SecurityOptions.Options)
+	}
+
+	// the security options must not show up as raw registry values
+	if len(cfg.RegistryValues.Values) != 1 || cfg.RegistryValues.Values[0] != `"MACHINE\Software\Example\Value",4,"1"` {
+		t.Errorf("unexpected registry values %v", cfg.RegistryValues.Values)
+	}
+
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+	err = cfg.GetSectionData("security_options", d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := d.Get("security_options.0.new_administrator_name").(string); v != "localadm" {
+		t.Errorf("unexpected value of security_options.0.new_administrator_name. expected localadm got %q", v)
+	}
+}
+
+func TestGetSectionDataWithoutSecurityOptions(t *testing.T) {
+	r := schema.Resource{}
+	r.Schema = adschema.GpoSecuritySchema()
+	d := r.TestResourceData()
+
+	cfg, err := ParseIniFile([]byte("[System Access]\r\nMinimumPasswordLength = 8\r\n"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cfg.GetSectionData("security_options", d)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(d.Get("security_options").([]interface{})) != 0 {
+		t.Errorf("security_options should be empty")
+	}
+}
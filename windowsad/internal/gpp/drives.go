@@ -0,0 +1,68 @@
+package gpp
+
+import "encoding/xml"
+
+// Class IDs of Drives.xml
+const (
+	DrivesClsid = "{8FDDCC1A-0C3C-43cd-A6B4-71A6DF20DA8C}"
+	DriveClsid  = "{935D1B74-9CB8-4e3c-9914-7DD559B7A417}"
+)
+
+// Drives is the contents of a Drives.xml file, the drive maps preferences
+type Drives struct {
+	XMLName     xml.Name      `xml:"Drives"`
+	Clsid       string        `xml:"clsid,attr"`
+	Drives      []Drive       `xml:"Drive"`
+	Unsupported []Unsupported `xml:",any"`
+}
+
+// Drive is a drive map preference item
+type Drive struct {
+	XMLName xml.Name `xml:"Drive"`
+	Item
+	Properties DriveProperties `xml:"Properties"`
+	Filters    *Filters        `xml:"Filters,omitempty"`
+}
+
+// DriveProperties are the settings of a mapped drive. ThisDrive and AllDrives are NOCHANGE, SHOW
+// or HIDE.
+type DriveProperties struct {
+	Action     string `xml:"action,attr"`
+	ThisDrive  string `xml:"thisDrive,attr"`
+	AllDrives  string `xml:"allDrives,attr"`
+	UserName   string `xml:"userName,attr"`
+	Path       string `xml:"path,attr"`
+	Label      string `xml:"label,attr"`
+	Persistent Bool   `xml:"persistent,attr"`
+	UseLetter  Bool   `xml:"useLetter,attr"`
+	Letter     string `xml:"letter,attr"`
+}
+
+// NewDrives returns an empty Drives.xml file
+func NewDrives() *Drives {
+	return &Drives{Clsid: DrivesClsid, Drives: []Drive{}}
+}
+
+// ParseDrives decodes the contents of a Drives.xml file. An empty input is treated as a file
+// without items.
+func ParseDrives(data []byte) (*Drives, error) {
+	d := NewDrives()
+	if len(data) == 0 {
+		return d, nil
+	}
+	if err := parse(data, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Encode returns the contents of the Drives.xml file
+func (d *Drives) Encode() ([]byte, error) {
+	return encode(d)
+}
+
+// KeepUnchanged keeps the uid and changed attributes of the items that are otherwise identical
+// to an item of the existing Drives.xml file
+func (d *Drives) KeepUnchanged(existing *Drives) error {
+	return keepUnchangedItems(d.Drives, existing.Drives, func(i *Drive) *Item { return &i.Item })
+}
@@ -0,0 +1,65 @@
+package gpp
+
+import "encoding/xml"
+
+// Class IDs of Files.xml
+const (
+	FilesClsid = "{215B2E53-57CE-475c-80FE-9EEC14635851}"
+	FileClsid  = "{50BE44C8-567A-4ed1-B1D0-9234FE1F38AF}"
+)
+
+// Files is the contents of a Files.xml file, the files preferences
+type Files struct {
+	XMLName     xml.Name      `xml:"Files"`
+	Clsid       string        `xml:"clsid,attr"`
+	Files       []File        `xml:"File"`
+	Unsupported []Unsupported `xml:",any"`
+}
+
+// File is a file preference item, copying a file to the computers the GPO applies to
+type File struct {
+	XMLName xml.Name `xml:"File"`
+	Item
+	Properties FileProperties `xml:"Properties"`
+	Filters    *Filters       `xml:"Filters,omitempty"`
+}
+
+// FileProperties are the settings of a file: where it is copied from and to, and its attributes
+type FileProperties struct {
+	Action     string `xml:"action,attr"`
+	FromPath   string `xml:"fromPath,attr"`
+	TargetPath string `xml:"targetPath,attr"`
+	ReadOnly   Bool   `xml:"readOnly,attr"`
+	Archive    Bool   `xml:"archive,attr"`
+	Hidden     Bool   `xml:"hidden,attr"`
+	Suppress   Bool   `xml:"suppress,attr"`
+}
+
+// NewFiles returns an empty Files.xml file
+func NewFiles() *Files {
+	return &Files{Clsid: FilesClsid, Files: []File{}}
+}
+
+// ParseFiles decodes the contents of a Files.xml file. An empty input is treated as a file without
+// items.
+func ParseFiles(data []byte) (*Files, error) {
+	f := NewFiles()
+	if len(data) == 0 {
+		return f, nil
+	}
+	if err := parse(data, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Encode returns the contents of the Files.xml file
+func (f *Files) Encode() ([]byte, error) {
+	return encode(f)
+}
+
+// KeepUnchanged keeps the uid and changed attributes of the items that are otherwise identical
+// to an item of the existing Files.xml file
+func (f *Files) KeepUnchanged(existing *Files) error {
+	return keepUnchangedItems(f.Files, existing.Files, func(i *File) *Item { return &i.Item })
+}
@@ -0,0 +1,262 @@
+// Package gpp encodes and decodes the XML files of Group Policy Preferences: Groups.xml,
+// Drives.xml, ScheduledTasks.xml, Files.xml and Registry.xml.
+// (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gppref/)
+package gpp
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+const header = `<?xml version="1.0" encoding="utf-8"?>` + "\r\n"
+
+// changedLayout is the layout of the changed attribute of the items
+const changedLayout = "2006-01-02 15:04:05"
+
+// actions maps the names of the actions of preference items to their code
+var actions = map[string]string{
+	"create":  "C",
+	"replace": "R",
+	"update":  "U",
+	"delete":  "D",
+}
+
+// actionImages are the icons shown by the Group Policy editor for each action
+var actionImages = map[string]int{"C": 0, "R": 1, "U": 2, "D": 3}
+
+// ActionNames returns the names of the actions of preference items, sorted
+func ActionNames() []string {
+	names := []string{}
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ActionCode returns the code of an action, e.g. U for update
+func ActionCode(name string) (string, error) {
+	code, ok := actions[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown action %q", name)
+	}
+	return code, nil
+}
+
+// ActionName returns the name of an action code, e.g. update for U
+func ActionName(code string) (string, error) {
+	for name, c := range actions {
+		if strings.EqualFold(c, code) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown action code %q", code)
+}
+
+// Bool is a boolean attribute, written as 1 or 0
+type Bool bool
+
+// MarshalXMLAttr implements xml.MarshalerAttr
+func (b Bool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	value := "0"
+	if b {
+		value = "1"
+	}
+	return xml.Attr{Name: name, Value: value}, nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr
+func (b *Bool) UnmarshalXMLAttr(attr xml.Attr) error {
+	switch strings.ToLower(attr.Value) {
+	case "1", "true":
+		*b = true
+	case "0", "false", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean value %q of attribute %s", attr.Value, attr.Name.Local)
+	}
+	return nil
+}
+
+// Item holds the attributes common to the items of all the preference files
+type Item struct {
+	Clsid        string `xml:"clsid,attr"`
+	Name         string `xml:"name,attr"`
+	Status       string `xml:"status,attr,omitempty"`
+	Image        int    `xml:"image,attr"`
+	Changed      string `xml:"changed,attr"`
+	UID          string `xml:"uid,attr"`
+	Desc         string `xml:"desc,attr,omitempty"`
+	Disabled     Bool   `xml:"disabled,attr,omitempty"`
+	UserContext  Bool   `xml:"userContext,attr,omitempty"`
+	RemovePolicy Bool   `xml:"removePolicy,attr,omitempty"`
+}
+
+// NewItem returns the attributes of a new item with the given class, name and action code, changed
+// at the given time and identified by a new uid
+func NewItem(clsid, name, action string, changed time.Time) (Item, error) {
+	uid, err := uuid.GenerateUUID()
+	if err != nil {
+		return Item{}, fmt.Errorf("error while generating the uid of item %q: %s", name, err)
+	}
+	return Item{
+		Clsid:   clsid,
+		Name:    name,
+		Status:  name,
+		Image:   actionImages[action],
+		Changed: changed.UTC().Format(changedLayout),
+		UID:     fmt.Sprintf("{%s}", strings.ToUpper(uid)),
+	}, nil
+}
+
+// Filter is an item-level targeting filter, e.g. FilterGroup or FilterComputer. The attributes
+// specific to the type of filter are kept as they are.
+type Filter struct {
+	Type       string
+	Or         bool
+	Not        bool
+	Attributes map[string]string
+}
+
+// MarshalXML implements xml.Marshaler
+func (f Filter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: xml.Name{Local: f.Type}}
+	boolean := "AND"
+	if f.Or {
+		boolean = "OR"
+	}
+	not := "0"
+	if f.Not {
+		not = "1"
+	}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "bool"}, Value: boolean}, xml.Attr{Name: xml.Name{Local: "not"}, Value: not})
+	keys := []string{}
+	for k := range f.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: k}, Value: f.Attributes[k]})
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Filter collections, which group filters, are not
+// supported.
+func (f *Filter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	f.Type = start.Name.Local
+	f.Attributes = map[string]string{}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "bool":
+			f.Or = strings.EqualFold(attr.Value, "OR")
+		case "not":
+			f.Not = attr.Value == "1"
+		default:
+			f.Attributes[attr.Name.Local] = attr.Value
+		}
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			return fmt.Errorf("filter %s: nested filters are not supported", t.Name.Local)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Filters is the item-level targeting of an item, its filters being combined in order
+type Filters struct {
+	Filters []Filter `xml:",any"`
+}
+
+// NewFilters returns the Filters element of a list of filters, or nil if the list is empty
+func NewFilters(filters []Filter) *Filters {
+	if len(filters) == 0 {
+		return nil
+	}
+	return &Filters{Filters: filters}
+}
+
+// List returns the filters of a possibly nil Filters element
+func (f *Filters) List() []Filter {
+	if f == nil {
+		return []Filter{}
+	}
+	return f.Filters
+}
+
+// Unsupported is an element of a preference file this package doesn't handle. Its attributes and
+// contents are kept as they are, so it's written back unchanged after the supported items.
+type Unsupported struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	InnerXML []byte     `xml:",innerxml"`
+}
+
+// keepUnchangedItems gives the items that only differ from one of the existing items by their uid
+// and changed attributes the ones of that item. Clients treat an item with a new uid as a new
+// item, removing and applying again the items that are removed when the GPO no longer applies.
+func keepUnchangedItems[T any](items, existing []T, item func(*T) *Item) error {
+	used := make([]bool, len(existing))
+	for i := range items {
+		for j := range existing {
+			if used[j] {
+				continue
+			}
+			candidate := items[i]
+			attrs, existingAttrs := item(&candidate), item(&existing[j])
+			attrs.UID, attrs.Changed = existingAttrs.UID, existingAttrs.Changed
+			same, err := sameXML(candidate, existing[j])
+			if err != nil {
+				return err
+			}
+			if same {
+				items[i] = candidate
+				used[j] = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// sameXML returns true if the two values are encoded to the same XML
+func sameXML(a, b interface{}) (bool, error) {
+	encodedA, err := xml.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	encodedB, err := xml.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(encodedA, encodedB), nil
+}
+
+func encode(v interface{}) ([]byte, error) {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), out...), nil
+}
+
+func parse(data []byte, v interface{}) error {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	return xml.Unmarshal(data, v)
+}
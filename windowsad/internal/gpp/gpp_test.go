@@ -0,0 +1,239 @@
+package gpp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActions(t *testing.T) {
+	for _, name := range ActionNames() {
+		code, err := ActionCode(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ActionName(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != name {
+			t.Errorf("ActionName(ActionCode(%q)) = %q", name, got)
+		}
+	}
+	if _, err := ActionCode("merge"); err == nil {
+		t.Error("ActionCode(merge): expected an error")
+	}
+}
+
+func TestNewItem(t *testing.T) {
+	item, err := NewItem(GroupClsid, "Administrators (built-in)", "U", time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Image != 2 || item.Changed != "2024-03-01 10:20:30" || item.Status != item.Name {
+		t.Errorf("NewItem() = %+v", item)
+	}
+	if len(item.UID) != 38 || !strings.HasPrefix(item.UID, "{") || item.UID != strings.ToUpper(item.UID) {
+		t.Errorf("NewItem() uid = %q, expected an upper case GUID in braces", item.UID)
+	}
+}
+
+func TestGroupsRoundTrip(t *testing.T) {
+	g := NewGroups()
+	g.Groups = append(g.Groups, Group{
+		Item: Item{Clsid: GroupClsid, Name: "Administrators (built-in)", Image: 2, Changed: "2024-03-01 10:20:30", UID: "{D5FE6B8D-A4A8-4E3B-8D5F-FD5C4C6E0C10}", RemovePolicy: true},
+		Properties: GroupProperties{
+			Action:    "U",
+			GroupSid:  "S-1-5-32-544",
+			GroupName: "Administrators (built-in)",
+			Members: &GroupMembers{Members: []GroupMember{
+				{Name: `CONTOSO\Workstation Admins`, Action: "ADD", Sid: "S-1-5-21-1-2-3-1105"},
+			}},
+		},
+		Filters: NewFilters([]Filter{{Type: "FilterGroup", Not: true, Attributes: map[string]string{"name": `CONTOSO\Kiosks`, "userContext": "0"}}}),
+	})
+
+	data, err := g.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<?xml version="1.0" encoding="utf-8"?>` + "\r\n" +
+		`<Groups clsid="{3125E937-EB16-4b4c-9934-544FC6D24D26}">` +
+		`<Group clsid="{6D4A79E4-529C-4481-ABD0-F5BD7EA93BA7}" name="Administrators (built-in)" image="2" changed="2024-03-01 10:20:30" uid="{D5FE6B8D-A4A8-4E3B-8D5F-FD5C4C6E0C10}" removePolicy="1">` +
+		`<Properties action="U" newName="" description="" deleteAllUsers="0" deleteAllGroups="0" removeAccounts="0" groupSid="S-1-5-32-544" groupName="Administrators (built-in)">` +
+		`<Members><Member name="CONTOSO\Workstation Admins" action="ADD" sid="S-1-5-21-1-2-3-1105"></Member></Members></Properties>` +
+		`<Filters><FilterGroup bool="AND" not="1" name="CONTOSO\Kiosks" userContext="0"></FilterGroup></Filters>` +
+		`</Group></Groups>`
+	if string(data) != expected {
+		t.Errorf("Encode() =\n%s\nexpected\n%s", data, expected)
+	}
+
+	parsed, err := ParseGroups(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed.XMLName = g.XMLName
+	parsed.Groups[0].XMLName = g.Groups[0].XMLName
+	if !reflect.DeepEqual(parsed.Groups, g.Groups) {
+		t.Errorf("ParseGroups() = %+v, expected %+v", parsed.Groups, g.Groups)
+	}
+}
+
+func TestParseUnsupported(t *testing.T) {
+	data := "\xEF\xBB\xBF" + `<?xml version="1.0" encoding="utf-8"?>
+<Groups clsid="{3125E937-EB16-4b4c-9934-544FC6D24D26}">
+  <User clsid="{DF5F1855-51E5-4d24-8B1A-D9BDE98BA1D1}" name="Guest"><Properties action="U" userName="Guest"/></User>
+  <Group clsid="{6D4A79E4-529C-4481-ABD0-F5BD7EA93BA7}" name="Users"><Properties action="U" groupName="Users" deleteAllUsers="1"/></Group>
+</Groups>`
+	g, err := ParseGroups([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Groups) != 1 || !g.Groups[0].Properties.DeleteAllUsers || g.Groups[0].Properties.Members != nil {
+		t.Errorf("ParseGroups() groups = %+v", g.Groups)
+	}
+	if len(g.Unsupported) != 1 || g.Unsupported[0].XMLName.Local != "User" {
+		t.Errorf("ParseGroups() unsupported = %+v, expected the User element", g.Unsupported)
+	}
+	out, err := g.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<User clsid="{DF5F1855-51E5-4d24-8B1A-D9BDE98BA1D1}" name="Guest"><Properties action="U" userName="Guest"/></User></Groups>`
+	if !strings.Contains(string(out), expected) {
+		t.Errorf("Encode() = %s, expected the User element to be written back as %s", out, expected)
+	}
+
+	empty, err := ParseGroups(nil)
+	if err != nil || len(empty.Groups) != 0 || empty.Clsid != GroupsClsid {
+		t.Errorf("ParseGroups(nil) = %+v, %v", empty, err)
+	}
+}
+
+func TestParseFilterErrors(t *testing.T) {
+	for _, data := range []string{
+		`<Drives clsid="x"><Drive name="S:"><Properties action="U"/><Filters><FilterCollection bool="AND" not="0"><FilterOs bool="AND" not="0"/></FilterCollection></Filters></Drive></Drives>`,
+		`<Drives clsid="x"><Drive name="S:"><Properties action="U" persistent="maybe"/></Drive></Drives>`,
+	} {
+		if _, err := ParseDrives([]byte(data)); err == nil {
+			t.Errorf("ParseDrives(%q): expected an error", data)
+		}
+	}
+}
+
+func TestRegistryValues(t *testing.T) {
+	for _, tc := range []struct {
+		valueType string
+		value     string
+		data      string
+	}{
+		{"REG_SZ", "https://wsus.example.com", "https://wsus.example.com"},
+		{"REG_DWORD", "1", "00000001"},
+		{"REG_DWORD", "4294967295", "FFFFFFFF"},
+		{"REG_QWORD", "10", "000000000000000A"},
+		{"REG_BINARY", "00ff", "00ff"},
+	} {
+		data, err := EncodeRegistryValue(tc.valueType, tc.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data != tc.data {
+			t.Errorf("EncodeRegistryValue(%s, %q) = %q, expected %q", tc.valueType, tc.value, data, tc.data)
+		}
+		value, err := DecodeRegistryValue(tc.valueType, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != tc.value {
+			t.Errorf("DecodeRegistryValue(%s, %q) = %q, expected %q", tc.valueType, data, value, tc.value)
+		}
+	}
+
+	for _, tc := range [][2]string{{"REG_DWORD", "4294967296"}, {"REG_DWORD", "0x10"}, {"REG_BINARY", "0g"}, {"REG_MULTI_SZ", "a"}} {
+		if _, err := EncodeRegistryValue(tc[0], tc[1]); err == nil {
+			t.Errorf("EncodeRegistryValue(%s, %q): expected an error", tc[0], tc[1])
+		}
+	}
+}
+
+func TestScheduledTasksRoundTrip(t *testing.T) {
+	task := NewTask(TaskPrincipal{UserID: `NT AUTHORITY\System`, LogonType: "S4U", RunLevel: "HighestAvailable"}, false, "PT1H")
+	task.RegistrationInfo.Description = "Cleans the temporary files"
+	task.Triggers.Boot = []BootTrigger{{Enabled: true, Delay: "PT5M"}}
+	task.Triggers.Calendar = []CalendarTrigger{{StartBoundary: "2024-01-01T03:00:00", Enabled: true, ScheduleByDay: &ScheduleByDay{DaysInterval: 1}}}
+	task.Actions.Exec = []ExecAction{{Command: "cleanmgr.exe", Arguments: "/sagerun:1"}}
+
+	s := NewScheduledTasks()
+	s.Tasks = append(s.Tasks, TaskV2{
+		Item:       Item{Clsid: TaskV2Clsid, Name: "Cleanup", Image: 1},
+		Properties: TaskProperties{Action: "R", Name: "Cleanup", RunAs: `NT AUTHORITY\System`, LogonType: "S4U", Task: task},
+	})
+	data, err := s.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fragment := range []string{
+		`<Principals><Principal id="Author"><UserId>NT AUTHORITY\System</UserId><LogonType>S4U</LogonType><RunLevel>HighestAvailable</RunLevel></Principal></Principals>`,
+		`<Enabled>false</Enabled>`,
+		`<Triggers><BootTrigger><Enabled>true</Enabled><Delay>PT5M</Delay></BootTrigger><CalendarTrigger><StartBoundary>2024-01-01T03:00:00</StartBoundary><Enabled>true</Enabled><ScheduleByDay><DaysInterval>1</DaysInterval></ScheduleByDay></CalendarTrigger></Triggers>`,
+		`<Actions Context="Author"><Exec><Command>cleanmgr.exe</Command><Arguments>/sagerun:1</Arguments></Exec></Actions>`,
+	} {
+		if !strings.Contains(string(data), fragment) {
+			t.Errorf("Encode() = %s\nexpected it to contain %s", data, fragment)
+		}
+	}
+
+	parsed, err := ParseScheduledTasks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.Tasks[0].Properties.Task, task) {
+		t.Errorf("ParseScheduledTasks() task = %+v, expected %+v", parsed.Tasks[0].Properties.Task, task)
+	}
+
+	// a task without an Enabled element is enabled
+	parsed, err = ParseScheduledTasks([]byte(`<ScheduledTasks clsid="x"><TaskV2 name="t"><Properties action="U"><Task version="1.2"><Settings/></Task></Properties></TaskV2></ScheduledTasks>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Tasks[0].Properties.Task.Settings.IsEnabled() {
+		t.Error("IsEnabled() = false, expected true")
+	}
+}
+
+func TestKeepUnchanged(t *testing.T) {
+	newDrive := func(letter, path string) Drive {
+		item, err := NewItem(DriveClsid, letter+":", "R", time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		item.RemovePolicy = true
+		return Drive{Item: item, Properties: DriveProperties{Action: "R", Path: path, UseLetter: true, Letter: letter}}
+	}
+
+	existing := NewDrives()
+	existing.Drives = append(existing.Drives, newDrive("S", `\\fileserver\share`), newDrive("T", `\\fileserver\team`))
+	existing.Drives[0].Changed = "2024-03-01 10:20:30"
+	data, err := existing.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err = ParseDrives(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDrives()
+	d.Drives = append(d.Drives, newDrive("S", `\\fileserver\share`), newDrive("T", `\\fileserver\other`))
+	err = d.KeepUnchanged(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Drives[0].UID != existing.Drives[0].UID || d.Drives[0].Changed != "2024-03-01 10:20:30" {
+		t.Errorf("KeepUnchanged() = %+v, expected the uid and changed attributes of %+v", d.Drives[0].Item, existing.Drives[0].Item)
+	}
+	if d.Drives[1].UID == existing.Drives[1].UID {
+		t.Errorf("KeepUnchanged() kept the uid %s of a changed item", d.Drives[1].UID)
+	}
+}
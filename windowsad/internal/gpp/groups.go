@@ -0,0 +1,80 @@
+package gpp
+
+import "encoding/xml"
+
+// Class IDs of Groups.xml
+const (
+	GroupsClsid = "{3125E937-EB16-4b4c-9934-544FC6D24D26}"
+	GroupClsid  = "{6D4A79E4-529C-4481-ABD0-F5BD7EA93BA7}"
+)
+
+// Groups is the contents of a Groups.xml file, the local users and groups preferences. Only
+// groups are supported, local users being listed as unsupported elements.
+type Groups struct {
+	XMLName     xml.Name      `xml:"Groups"`
+	Clsid       string        `xml:"clsid,attr"`
+	Groups      []Group       `xml:"Group"`
+	Unsupported []Unsupported `xml:",any"`
+}
+
+// Group is a local group preference item
+type Group struct {
+	XMLName xml.Name `xml:"Group"`
+	Item
+	Properties GroupProperties `xml:"Properties"`
+	Filters    *Filters        `xml:"Filters,omitempty"`
+}
+
+// GroupProperties are the settings of a local group
+type GroupProperties struct {
+	Action          string        `xml:"action,attr"`
+	NewName         string        `xml:"newName,attr"`
+	Description     string        `xml:"description,attr"`
+	DeleteAllUsers  Bool          `xml:"deleteAllUsers,attr"`
+	DeleteAllGroups Bool          `xml:"deleteAllGroups,attr"`
+	RemoveAccounts  Bool          `xml:"removeAccounts,attr"`
+	GroupSid        string        `xml:"groupSid,attr"`
+	GroupName       string        `xml:"groupName,attr"`
+	Members         *GroupMembers `xml:"Members,omitempty"`
+}
+
+// GroupMembers are the members added to or removed from a local group
+type GroupMembers struct {
+	Members []GroupMember `xml:"Member"`
+}
+
+// GroupMember is an account added to or removed from a local group. Action is ADD or REMOVE.
+type GroupMember struct {
+	Name   string `xml:"name,attr"`
+	Action string `xml:"action,attr"`
+	Sid    string `xml:"sid,attr"`
+}
+
+// NewGroups returns an empty Groups.xml file
+func NewGroups() *Groups {
+	return &Groups{Clsid: GroupsClsid, Groups: []Group{}}
+}
+
+// ParseGroups decodes the contents of a Groups.xml file. An empty input is treated as a file
+// without items.
+func ParseGroups(data []byte) (*Groups, error) {
+	g := NewGroups()
+	if len(data) == 0 {
+		return g, nil
+	}
+	if err := parse(data, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Encode returns the contents of the Groups.xml file
+func (g *Groups) Encode() ([]byte, error) {
+	return encode(g)
+}
+
+// KeepUnchanged keeps the uid and changed attributes of the items that are otherwise identical
+// to an item of the existing Groups.xml file
+func (g *Groups) KeepUnchanged(existing *Groups) error {
+	return keepUnchangedItems(g.Groups, existing.Groups, func(i *Group) *Item { return &i.Item })
+}
@@ -0,0 +1,131 @@
+package gpp
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Class IDs of Registry.xml
+const (
+	RegistrySettingsClsid = "{A3CCFC41-DFDB-43a5-8D26-0FE8B954DA51}"
+	RegistryClsid         = "{9CD4B2F4-923D-47f5-A062-E897DD1DAD50}"
+)
+
+// RegistryHives are the hives a registry preference item can write to
+var RegistryHives = []string{"HKEY_CLASSES_ROOT", "HKEY_CURRENT_CONFIG", "HKEY_CURRENT_USER", "HKEY_LOCAL_MACHINE", "HKEY_USERS"}
+
+// RegistryTypes are the types of registry values supported by this package
+var RegistryTypes = []string{"REG_BINARY", "REG_DWORD", "REG_EXPAND_SZ", "REG_QWORD", "REG_SZ"}
+
+// RegistrySettings is the contents of a Registry.xml file, the registry preferences. Collections,
+// the folders grouping registry items, are not supported.
+type RegistrySettings struct {
+	XMLName     xml.Name      `xml:"RegistrySettings"`
+	Clsid       string        `xml:"clsid,attr"`
+	Registry    []Registry    `xml:"Registry"`
+	Unsupported []Unsupported `xml:",any"`
+}
+
+// Registry is a registry value preference item
+type Registry struct {
+	XMLName xml.Name `xml:"Registry"`
+	Item
+	Properties RegistryProperties `xml:"Properties"`
+	Filters    *Filters           `xml:"Filters,omitempty"`
+}
+
+// RegistryProperties are the settings of a registry value. Default is set for the default value of
+// the key, in which case Name is empty. Value is encoded as written by EncodeRegistryValue.
+type RegistryProperties struct {
+	Action         string `xml:"action,attr"`
+	DisplayDecimal Bool   `xml:"displayDecimal,attr"`
+	Default        Bool   `xml:"default,attr"`
+	Hive           string `xml:"hive,attr"`
+	Key            string `xml:"key,attr"`
+	Name           string `xml:"name,attr"`
+	Type           string `xml:"type,attr"`
+	Value          string `xml:"value,attr"`
+}
+
+// NewRegistrySettings returns an empty Registry.xml file
+func NewRegistrySettings() *RegistrySettings {
+	return &RegistrySettings{Clsid: RegistrySettingsClsid, Registry: []Registry{}}
+}
+
+// ParseRegistrySettings decodes the contents of a Registry.xml file. An empty input is treated as
+// a file without items.
+func ParseRegistrySettings(data []byte) (*RegistrySettings, error) {
+	r := NewRegistrySettings()
+	if len(data) == 0 {
+		return r, nil
+	}
+	if err := parse(data, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Encode returns the contents of the Registry.xml file
+func (r *RegistrySettings) Encode() ([]byte, error) {
+	return encode(r)
+}
+
+// EncodeRegistryValue converts the data of a value to its representation in Registry.xml: numbers
+// are given in decimal and written in hexadecimal, binary data is given and written in hexadecimal.
+func EncodeRegistryValue(valueType, value string) (string, error) {
+	switch valueType {
+	case "REG_SZ", "REG_EXPAND_SZ":
+		return value, nil
+	case "REG_DWORD":
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid REG_DWORD value %q: %s", value, err)
+		}
+		return fmt.Sprintf("%08X", n), nil
+	case "REG_QWORD":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid REG_QWORD value %q: %s", value, err)
+		}
+		return fmt.Sprintf("%016X", n), nil
+	case "REG_BINARY":
+		if _, err := hex.DecodeString(value); err != nil {
+			return "", fmt.Errorf("invalid REG_BINARY value %q: %s", value, err)
+		}
+		return strings.ToLower(value), nil
+	}
+	return "", fmt.Errorf("unsupported registry value type %q", valueType)
+}
+
+// DecodeRegistryValue is the reverse of EncodeRegistryValue
+func DecodeRegistryValue(valueType, data string) (string, error) {
+	switch valueType {
+	case "REG_SZ", "REG_EXPAND_SZ":
+		return data, nil
+	case "REG_DWORD", "REG_QWORD":
+		bitSize := 32
+		if valueType == "REG_QWORD" {
+			bitSize = 64
+		}
+		n, err := strconv.ParseUint(data, 16, bitSize)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s data %q: %s", valueType, data, err)
+		}
+		return strconv.FormatUint(n, 10), nil
+	case "REG_BINARY":
+		if _, err := hex.DecodeString(data); err != nil {
+			return "", fmt.Errorf("invalid REG_BINARY data %q: %s", data, err)
+		}
+		return strings.ToLower(data), nil
+	}
+	return "", fmt.Errorf("unsupported registry value type %q", valueType)
+}
+
+// KeepUnchanged keeps the uid and changed attributes of the items that are otherwise identical
+// to an item of the existing Registry.xml file
+func (r *RegistrySettings) KeepUnchanged(existing *RegistrySettings) error {
+	return keepUnchangedItems(r.Registry, existing.Registry, func(i *Registry) *Item { return &i.Item })
+}
@@ -0,0 +1,187 @@
+package gpp
+
+import "encoding/xml"
+
+// Class IDs of ScheduledTasks.xml
+const (
+	ScheduledTasksClsid = "{CC63F200-7309-4ba0-B154-A71CD118DBCC}"
+	TaskV2Clsid         = "{D8896631-B747-47a7-84A6-C155337F3BC8}"
+)
+
+// ScheduledTasks is the contents of a ScheduledTasks.xml file, the scheduled tasks preferences.
+// Only the scheduled tasks of Windows Vista and later (TaskV2) are supported, the legacy and
+// immediate tasks being listed as unsupported elements.
+type ScheduledTasks struct {
+	XMLName     xml.Name      `xml:"ScheduledTasks"`
+	Clsid       string        `xml:"clsid,attr"`
+	Tasks       []TaskV2      `xml:"TaskV2"`
+	Unsupported []Unsupported `xml:",any"`
+}
+
+// TaskV2 is a scheduled task preference item
+type TaskV2 struct {
+	XMLName xml.Name `xml:"TaskV2"`
+	Item
+	Properties TaskProperties `xml:"Properties"`
+	Filters    *Filters       `xml:"Filters,omitempty"`
+}
+
+// TaskProperties are the settings of a scheduled task. The task itself is described in the
+// Task Scheduler schema.
+type TaskProperties struct {
+	Action    string `xml:"action,attr"`
+	Name      string `xml:"name,attr"`
+	RunAs     string `xml:"runAs,attr"`
+	LogonType string `xml:"logonType,attr"`
+	Task      Task   `xml:"Task"`
+}
+
+// Task is the subset of the Task Scheduler task definition handled by this package. Elements that
+// aren't modelled are dropped when parsing.
+type Task struct {
+	Version          string               `xml:"version,attr"`
+	RegistrationInfo TaskRegistrationInfo `xml:"RegistrationInfo"`
+	Principals       TaskPrincipals       `xml:"Principals"`
+	Settings         TaskSettings         `xml:"Settings"`
+	Triggers         TaskTriggers         `xml:"Triggers"`
+	Actions          TaskActions          `xml:"Actions"`
+}
+
+// TaskRegistrationInfo holds the description of a task
+type TaskRegistrationInfo struct {
+	Author      string `xml:"Author,omitempty"`
+	Description string `xml:"Description,omitempty"`
+}
+
+// TaskPrincipals holds the account a task runs as
+type TaskPrincipals struct {
+	Principal TaskPrincipal `xml:"Principal"`
+}
+
+// TaskPrincipal is the account a task runs as. LogonType is S4U or InteractiveToken, RunLevel is
+// LeastPrivilege or HighestAvailable.
+type TaskPrincipal struct {
+	ID        string `xml:"id,attr"`
+	UserID    string `xml:"UserId"`
+	LogonType string `xml:"LogonType"`
+	RunLevel  string `xml:"RunLevel"`
+}
+
+// TaskSettings are the settings of a task. A task without an Enabled element is enabled.
+type TaskSettings struct {
+	MultipleInstancesPolicy    string `xml:"MultipleInstancesPolicy"`
+	DisallowStartIfOnBatteries bool   `xml:"DisallowStartIfOnBatteries"`
+	StopIfGoingOnBatteries     bool   `xml:"StopIfGoingOnBatteries"`
+	AllowHardTerminate         bool   `xml:"AllowHardTerminate"`
+	AllowStartOnDemand         bool   `xml:"AllowStartOnDemand"`
+	Enabled                    *bool  `xml:"Enabled"`
+	Hidden                     bool   `xml:"Hidden"`
+	ExecutionTimeLimit         string `xml:"ExecutionTimeLimit,omitempty"`
+	Priority                   int    `xml:"Priority"`
+}
+
+// IsEnabled returns false if the task is explicitly disabled
+func (s TaskSettings) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// TaskTriggers are the triggers of a task, grouped by type. Delays and start boundaries use the
+// formats of the Task Scheduler: durations like PT30S and local times like 2024-01-01T03:00:00.
+type TaskTriggers struct {
+	Boot     []BootTrigger     `xml:"BootTrigger"`
+	Logon    []LogonTrigger    `xml:"LogonTrigger"`
+	Calendar []CalendarTrigger `xml:"CalendarTrigger"`
+	Time     []TimeTrigger     `xml:"TimeTrigger"`
+}
+
+// BootTrigger starts a task when the computer starts
+type BootTrigger struct {
+	Enabled bool   `xml:"Enabled"`
+	Delay   string `xml:"Delay,omitempty"`
+}
+
+// LogonTrigger starts a task when a user logs on
+type LogonTrigger struct {
+	Enabled bool   `xml:"Enabled"`
+	Delay   string `xml:"Delay,omitempty"`
+}
+
+// CalendarTrigger starts a task on a schedule. Only daily schedules are modelled, ScheduleByDay
+// being nil for the other ones.
+type CalendarTrigger struct {
+	StartBoundary string         `xml:"StartBoundary"`
+	Enabled       bool           `xml:"Enabled"`
+	ScheduleByDay *ScheduleByDay `xml:"ScheduleByDay"`
+}
+
+// ScheduleByDay runs a task every DaysInterval days
+type ScheduleByDay struct {
+	DaysInterval int `xml:"DaysInterval"`
+}
+
+// TimeTrigger starts a task once
+type TimeTrigger struct {
+	StartBoundary string `xml:"StartBoundary"`
+	Enabled       bool   `xml:"Enabled"`
+}
+
+// TaskActions are the programs run by a task
+type TaskActions struct {
+	Context string       `xml:"Context,attr"`
+	Exec    []ExecAction `xml:"Exec"`
+}
+
+// ExecAction runs a program
+type ExecAction struct {
+	Command          string `xml:"Command"`
+	Arguments        string `xml:"Arguments,omitempty"`
+	WorkingDirectory string `xml:"WorkingDirectory,omitempty"`
+}
+
+// NewTask returns the definition of a task running as the given principal with the settings the
+// Group Policy editor uses by default
+func NewTask(principal TaskPrincipal, enabled bool, executionTimeLimit string) Task {
+	principal.ID = "Author"
+	return Task{
+		Version:    "1.2",
+		Principals: TaskPrincipals{Principal: principal},
+		Settings: TaskSettings{
+			MultipleInstancesPolicy: "IgnoreNew",
+			AllowHardTerminate:      true,
+			AllowStartOnDemand:      true,
+			Enabled:                 &enabled,
+			ExecutionTimeLimit:      executionTimeLimit,
+			Priority:                7,
+		},
+		Actions: TaskActions{Context: "Author"},
+	}
+}
+
+// NewScheduledTasks returns an empty ScheduledTasks.xml file
+func NewScheduledTasks() *ScheduledTasks {
+	return &ScheduledTasks{Clsid: ScheduledTasksClsid, Tasks: []TaskV2{}}
+}
+
+// ParseScheduledTasks decodes the contents of a ScheduledTasks.xml file. An empty input is treated
+// as a file without items.
+func ParseScheduledTasks(data []byte) (*ScheduledTasks, error) {
+	s := NewScheduledTasks()
+	if len(data) == 0 {
+		return s, nil
+	}
+	if err := parse(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Encode returns the contents of the ScheduledTasks.xml file
+func (s *ScheduledTasks) Encode() ([]byte, error) {
+	return encode(s)
+}
+
+// KeepUnchanged keeps the uid and changed attributes of the items that are otherwise identical
+// to an item of the existing ScheduledTasks.xml file
+func (s *ScheduledTasks) KeepUnchanged(existing *ScheduledTasks) error {
+	return keepUnchangedItems(s.Tasks, existing.Tasks, func(i *TaskV2) *Item { return &i.Item })
+}
@@ -0,0 +1,264 @@
+// Package gpscripts reads and writes scripts.ini and psscripts.ini files, the files listing the
+// startup, shutdown, logon and logoff scripts of Group Policy Objects and their parameters.
+// (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpscr/)
+package gpscripts
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Script is an entry of a scripts section: the command line of the script, relative to the
+// section's folder, and its parameters
+type Script struct {
+	CmdLine    string
+	Parameters string
+}
+
+type section struct {
+	name    string
+	scripts []Script
+	// lines of sections that don't list scripts, kept as they are
+	lines []string
+}
+
+// File is the contents of a scripts.ini or psscripts.ini file
+type File struct {
+	sections []*section
+}
+
+// scriptSections are the sections holding scripts, the other ones are kept unchanged
+var scriptSections = []string{"Startup", "Shutdown", "Logon", "Logoff"}
+
+func isScriptSection(name string) bool {
+	for _, s := range scriptSections {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse decodes the contents of a scripts file, encoded in UTF-16LE with a Byte Order Mark or in
+// UTF-8. An empty input is treated as a file without scripts.
+func Parse(data []byte) (*File, error) {
+	f := &File{}
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		decoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding scripts file from UTF16-LE: %s", err)
+		}
+		data = decoded
+	}
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+
+	var current *section
+	// keys of the current scripts section, by index
+	entries := map[int]*Script{}
+	flush := func() error {
+		if current == nil || !isScriptSection(current.name) {
+			return nil
+		}
+		indexes := []int{}
+		for idx := range entries {
+			indexes = append(indexes, idx)
+		}
+		sort.Ints(indexes)
+		for _, idx := range indexes {
+			if entries[idx].CmdLine == "" {
+				return fmt.Errorf("script %d of section %q has no command line", idx, current.name)
+			}
+			current.scripts = append(current.scripts, *entries[idx])
+		}
+		entries = map[int]*Script{}
+		return nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = &section{name: strings.TrimSpace(trimmed[1 : len(trimmed)-1])}
+			f.sections = append(f.sections, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if !isScriptSection(current.name) {
+			current.lines = append(current.lines, line)
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		idx, key, value, err := parseScriptKey(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing section %q: %s", current.name, err)
+		}
+		if _, ok := entries[idx]; !ok {
+			entries[idx] = &Script{}
+		}
+		switch strings.ToLower(key) {
+		case "cmdline":
+			entries[idx].CmdLine = value
+		case "parameters":
+			entries[idx].Parameters = value
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseScriptKey parses a line like 0CmdLine=script.cmd
+func parseScriptKey(line string) (int, string, string, error) {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return 0, "", "", fmt.Errorf("invalid line %q", line)
+	}
+	key, value := strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:])
+	digits := 0
+	for digits < len(key) && key[digits] >= '0' && key[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 {
+		return 0, "", "", fmt.Errorf("key %q has no script index", key)
+	}
+	idx, err := strconv.Atoi(key[:digits])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid script index in key %q: %s", key, err)
+	}
+	return idx, key[digits:], value, nil
+}
+
+func (f *File) getSection(name string) *section {
+	for _, s := range f.sections {
+		if strings.EqualFold(s.name, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// Scripts returns the scripts of a section, in order
+func (f *File) Scripts(sectionName string) []Script {
+	s := f.getSection(sectionName)
+	if s == nil {
+		return []Script{}
+	}
+	return append([]Script{}, s.scripts...)
+}
+
+// Find returns the position of a script in a section, or -1. Command lines are compared
+// case-insensitively.
+func (f *File) Find(sectionName, cmdLine string) int {
+	for idx, s := range f.Scripts(sectionName) {
+		if strings.EqualFold(s.CmdLine, cmdLine) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// SetScript adds a script to a section or updates its parameters. The script is moved to the given
+// position of the section; if position is negative, a script already listed keeps its position
+// and a new one is added at the end.
+func (f *File) SetScript(sectionName string, script Script, position int) {
+	s := f.getSection(sectionName)
+	if s == nil {
+		s = &section{name: sectionName}
+		f.sections = append(f.sections, s)
+	}
+
+	current := f.Find(sectionName, script.CmdLine)
+	if current >= 0 {
+		s.scripts = append(s.scripts[:current], s.scripts[current+1:]...)
+		if position < 0 {
+			position = current
+		}
+	}
+	if position < 0 || position > len(s.scripts) {
+		position = len(s.scripts)
+	}
+	scripts := append([]Script{}, s.scripts[:position]...)
+	scripts = append(scripts, script)
+	s.scripts = append(scripts, s.scripts[position:]...)
+}
+
+// RemoveScript removes a script from a section. It returns false if the script wasn't listed.
+func (f *File) RemoveScript(sectionName, cmdLine string) bool {
+	idx := f.Find(sectionName, cmdLine)
+	if idx < 0 {
+		return false
+	}
+	s := f.getSection(sectionName)
+	s.scripts = append(s.scripts[:idx], s.scripts[idx+1:]...)
+	return true
+}
+
+// IsEmpty returns true if no section of the file lists a script
+func (f *File) IsEmpty() bool {
+	for _, s := range f.sections {
+		if len(s.scripts) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBlank returns true if the file lists no script and has no other section, in which case it
+// can be removed
+func (f *File) IsBlank() bool {
+	for _, s := range f.sections {
+		if !isScriptSection(s.name) {
+			return false
+		}
+	}
+	return f.IsEmpty()
+}
+
+// Encode returns the contents of the file encoded in UTF-16LE with a Byte Order Mark, the way the
+// Group Policy editor writes them. Scripts are numbered from 0 in the order of their section and
+// empty script sections are left out.
+func (f *File) Encode() ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString("\r\n")
+	for _, s := range f.sections {
+		if isScriptSection(s.name) {
+			if len(s.scripts) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "[%s]\r\n", s.name)
+			for idx, script := range s.scripts {
+				fmt.Fprintf(&sb, "%dCmdLine=%s\r\n", idx, script.CmdLine)
+				fmt.Fprintf(&sb, "%dParameters=%s\r\n", idx, script.Parameters)
+			}
+			continue
+		}
+		fmt.Fprintf(&sb, "[%s]\r\n", s.name)
+		lines := s.lines
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteString("\r\n")
+		}
+	}
+
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(sb.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode scripts file to UTF16-LE with BOM, error: %s", err)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,113 @@
+package gpscripts
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+func utf16(t *testing.T, s string) []byte {
+	out, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	data := utf16(t, "\r\n[Startup]\r\n1CmdLine=second.cmd\r\n1Parameters=/quiet\r\n0CmdLine=first.cmd\r\n0Parameters=\r\n[Shutdown]\r\n0CmdLine=stop.cmd\r\n0Parameters=-Force\r\n")
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Script{{CmdLine: "first.cmd"}, {CmdLine: "second.cmd", Parameters: "/quiet"}}
+	if got := f.Scripts("Startup"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Scripts(Startup) = %v, expected %v", got, expected)
+	}
+	if got := f.Scripts("shutdown"); !reflect.DeepEqual(got, []Script{{CmdLine: "stop.cmd", Parameters: "-Force"}}) {
+		t.Errorf("Scripts(shutdown) = %v", got)
+	}
+	if got := f.Scripts("Logon"); len(got) != 0 {
+		t.Errorf("Scripts(Logon) = %v, expected no script", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{
+		"[Startup]\r\nCmdLine=script.cmd\r\n",
+		"[Startup]\r\n0CmdLine\r\n",
+		"[Startup]\r\n0Parameters=/quiet\r\n",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q): expected an error", data)
+		}
+	}
+}
+
+func TestSetScript(t *testing.T) {
+	f, err := Parse(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetScript("Startup", Script{CmdLine: "a.cmd"}, -1)
+	f.SetScript("Startup", Script{CmdLine: "b.cmd"}, -1)
+	f.SetScript("Startup", Script{CmdLine: "c.cmd"}, 0)
+	f.SetScript("Startup", Script{CmdLine: "A.cmd", Parameters: "/x"}, -1)
+
+	expected := []Script{{CmdLine: "c.cmd"}, {CmdLine: "A.cmd", Parameters: "/x"}, {CmdLine: "b.cmd"}}
+	if got := f.Scripts("Startup"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Scripts() = %v, expected %v", got, expected)
+	}
+
+	f.SetScript("Startup", Script{CmdLine: "c.cmd"}, 10)
+	if idx := f.Find("Startup", "c.cmd"); idx != 2 {
+		t.Errorf("Find(c.cmd) = %d, expected 2", idx)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	data := utf16(t, "\r\n[Logon]\r\n0CmdLine=map.cmd\r\n0Parameters=\r\n1CmdLine=printers.cmd\r\n1Parameters=/all\r\n")
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.RemoveScript("Logon", "map.cmd") {
+		t.Fatal("RemoveScript() returned false")
+	}
+	if f.RemoveScript("Logon", "missing.cmd") {
+		t.Error("RemoveScript() of a missing script returned true")
+	}
+
+	got, err := f.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := utf16(t, "\r\n[Logon]\r\n0CmdLine=printers.cmd\r\n0Parameters=/all\r\n")
+	if string(got) != string(expected) {
+		t.Errorf("Encode() = %q, expected %q", got, expected)
+	}
+}
+
+func TestEncodeKeepsOtherSections(t *testing.T) {
+	f, err := Parse([]byte("[ScriptsConfig]\r\nStartExecutePSFirst=true\r\n\r\n[Startup]\r\n0CmdLine=a.ps1\r\n0Parameters=\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.RemoveScript("Startup", "a.ps1")
+	if !f.IsEmpty() {
+		t.Error("IsEmpty() = false, expected true")
+	}
+	if f.IsBlank() {
+		t.Error("IsBlank() = true, expected false")
+	}
+	got, err := f.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := utf16(t, "\r\n[ScriptsConfig]\r\nStartExecutePSFirst=true\r\n")
+	if string(got) != string(expected) {
+		t.Errorf("Encode() = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,210 @@
+package preg
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Registry value types
+const (
+	RegNone           uint32 = 0
+	RegSZ             uint32 = 1
+	RegExpandSZ       uint32 = 2
+	RegBinary         uint32 = 3
+	RegDWord          uint32 = 4
+	RegDWordBigEndian uint32 = 5
+	RegMultiSZ        uint32 = 7
+	RegQWord          uint32 = 11
+)
+
+// Value names of the directives that delete values on the client
+const (
+	directivePrefix     = "**"
+	deleteValuePrefix   = "**del."
+	deleteAllValuesName = "**delvals."
+)
+
+var typeNames = map[string]uint32{
+	"REG_NONE":             RegNone,
+	"REG_SZ":               RegSZ,
+	"REG_EXPAND_SZ":        RegExpandSZ,
+	"REG_BINARY":           RegBinary,
+	"REG_DWORD":            RegDWord,
+	"REG_DWORD_BIG_ENDIAN": RegDWordBigEndian,
+	"REG_MULTI_SZ":         RegMultiSZ,
+	"REG_QWORD":            RegQWord,
+}
+
+// Entry is a single entry of a registry policy file. Entries whose value name starts with ** are
+// directives to the client, like deleting a value, rather than values to set.
+type Entry struct {
+	Key       string
+	ValueName string
+	Type      uint32
+	Data      []byte
+}
+
+// TypeNames returns the names of the registry value types, sorted alphabetically
+func TypeNames() []string {
+	names := make([]string, 0, len(typeNames))
+	for name := range typeNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TypeFromName returns the registry value type for a name like REG_SZ
+func TypeFromName(name string) (uint32, error) {
+	t, ok := typeNames[strings.ToUpper(name)]
+	if !ok {
+		return 0, fmt.Errorf("unknown registry value type %q", name)
+	}
+	return t, nil
+}
+
+// TypeName returns the name of a registry value type, or its number if it is not known
+func TypeName(t uint32) string {
+	for name, value := range typeNames {
+		if value == t {
+			return name
+		}
+	}
+	return strconv.FormatUint(uint64(t), 10)
+}
+
+// NewEntry returns an entry that sets a value. value holds the data of all the types but
+// REG_MULTI_SZ, for which values is used: numbers are in decimal and binary data in hexadecimal.
+func NewEntry(key, valueName, typeName, value string, values []string) (Entry, error) {
+	t, err := TypeFromName(typeName)
+	if err != nil {
+		return Entry{}, err
+	}
+	if strings.HasPrefix(valueName, directivePrefix) {
+		return Entry{}, fmt.Errorf("value name %q is reserved for directives", valueName)
+	}
+	data, err := encodeData(t, value, values)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid %s value for %s\\%s: %s", TypeName(t), key, valueName, err)
+	}
+	return Entry{Key: key, ValueName: valueName, Type: t, Data: data}, nil
+}
+
+// NewDeleteValueEntry returns the directive deleting a value, or all the values of the key if
+// valueName is empty.
+func NewDeleteValueEntry(key, valueName string) Entry {
+	name := deleteAllValuesName
+	if valueName != "" {
+		name = deleteValuePrefix + valueName
+	}
+	// the Group Policy editor stores a single space as the data of directives
+	return Entry{Key: key, ValueName: name, Type: RegSZ, Data: encodeUTF16(" ")}
+}
+
+// IsDirective returns true if the entry is a directive rather than a value to set
+func (e Entry) IsDirective() bool {
+	return strings.HasPrefix(e.ValueName, directivePrefix)
+}
+
+// DeletedValue returns the name of the value deleted by a **del. or **delvals. directive, empty for
+// **delvals. which deletes all values. The second return value is false for any other entry.
+func (e Entry) DeletedValue() (string, bool) {
+	lower := strings.ToLower(e.ValueName)
+	if lower == deleteAllValuesName {
+		return "", true
+	}
+	if strings.HasPrefix(lower, deleteValuePrefix) {
+		return e.ValueName[len(deleteValuePrefix):], true
+	}
+	return "", false
+}
+
+// Value returns the data of the entry in the format used by NewEntry
+func (e Entry) Value() (string, []string, error) {
+	switch e.Type {
+	case RegSZ, RegExpandSZ:
+		return decodeUTF16(e.Data), nil, nil
+	case RegMultiSZ:
+		return "", decodeMultiSZ(e.Data), nil
+	case RegDWord:
+		if len(e.Data) != 4 {
+			return "", nil, fmt.Errorf("REG_DWORD data must be 4 bytes long, got %d", len(e.Data))
+		}
+		return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(e.Data)), 10), nil, nil
+	case RegDWordBigEndian:
+		if len(e.Data) != 4 {
+			return "", nil, fmt.Errorf("REG_DWORD_BIG_ENDIAN data must be 4 bytes long, got %d", len(e.Data))
+		}
+		return strconv.FormatUint(uint64(binary.BigEndian.Uint32(e.Data)), 10), nil, nil
+	case RegQWord:
+		if len(e.Data) != 8 {
+			return "", nil, fmt.Errorf("REG_QWORD data must be 8 bytes long, got %d", len(e.Data))
+		}
+		return strconv.FormatUint(binary.LittleEndian.Uint64(e.Data), 10), nil, nil
+	default:
+		return hex.EncodeToString(e.Data), nil, nil
+	}
+}
+
+func encodeData(t uint32, value string, values []string) ([]byte, error) {
+	switch t {
+	case RegSZ, RegExpandSZ:
+		return encodeUTF16(value), nil
+	case RegMultiSZ:
+		out := []byte{}
+		for _, v := range values {
+			if v == "" {
+				return nil, fmt.Errorf("REG_MULTI_SZ values can't hold empty strings")
+			}
+			out = append(out, encodeUTF16(v)...)
+		}
+		return append(out, 0, 0), nil
+	case RegDWord, RegDWordBigEndian:
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 32 bit unsigned decimal number", value)
+		}
+		out := make([]byte, 4)
+		if t == RegDWord {
+			binary.LittleEndian.PutUint32(out, uint32(v))
+		} else {
+			binary.BigEndian.PutUint32(out, uint32(v))
+		}
+		return out, nil
+	case RegQWord:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a 64 bit unsigned decimal number", value)
+		}
+		out := make([]byte, 8)
+		binary.LittleEndian.PutUint64(out, v)
+		return out, nil
+	default:
+		out, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a hexadecimal string", value)
+		}
+		return out, nil
+	}
+}
+
+// decodeMultiSZ splits REG_MULTI_SZ data, a list of null terminated strings followed by an
+// empty string
+func decodeMultiSZ(data []byte) []string {
+	values := []string{}
+	start := 0
+	for i := 0; i+1 < len(data); i += 2 {
+		if data[i] == 0 && data[i+1] == 0 {
+			if i == start {
+				break
+			}
+			values = append(values, decodeUTF16(data[start:i]))
+			start = i + 2
+		}
+	}
+	return values
+}
@@ -0,0 +1,211 @@
+// Package preg encodes and decodes Registry.pol files, the registry policy file format used by
+// the administrative templates of Group Policy Objects.
+// (https://docs.microsoft.com/en-us/previous-versions/windows/desktop/policy/registry-policy-file-format)
+package preg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Signature and Version form the header of every Registry.pol file
+const (
+	Signature uint32 = 0x67655250 // "PReg"
+	Version   uint32 = 1
+)
+
+const headerSize = 8
+
+// Parse decodes the contents of a Registry.pol file. An empty input is treated as a file without
+// entries.
+func Parse(data []byte) ([]Entry, error) {
+	entries := []Entry{}
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("registry policy file is too short (%d bytes)", len(data))
+	}
+	if sig := binary.LittleEndian.Uint32(data[0:4]); sig != Signature {
+		return nil, fmt.Errorf("invalid registry policy file signature 0x%08x", sig)
+	}
+	if ver := binary.LittleEndian.Uint32(data[4:8]); ver != Version {
+		return nil, fmt.Errorf("unsupported registry policy file version %d", ver)
+	}
+
+	r := &reader{data: data, pos: headerSize}
+	for r.pos < len(r.data) {
+		entry, err := r.readEntry()
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing entry at offset %d: %s", r.pos, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Encode returns the contents of a Registry.pol file holding the given entries, in order.
+func Encode(entries []Entry) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, headerSize))
+	_ = binary.Write(buf, binary.LittleEndian, Signature)
+	_ = binary.Write(buf, binary.LittleEndian, Version)
+
+	for _, e := range entries {
+		writeChar(buf, '[')
+		writeString(buf, e.Key)
+		writeChar(buf, ';')
+		writeString(buf, e.ValueName)
+		writeChar(buf, ';')
+		_ = binary.Write(buf, binary.LittleEndian, e.Type)
+		writeChar(buf, ';')
+		_ = binary.Write(buf, binary.LittleEndian, uint32(len(e.Data)))
+		writeChar(buf, ';')
+		buf.Write(e.Data)
+		writeChar(buf, ']')
+	}
+	return buf.Bytes()
+}
+
+// Sort orders entries the way the Group Policy editor writes them: by key, directives that delete
+// values before the values of the same key, then by value name. Keys and value names are compared
+// case insensitively, like the registry does.
+func Sort(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		ki, kj := strings.ToLower(entries[i].Key), strings.ToLower(entries[j].Key)
+		if ki != kj {
+			return ki < kj
+		}
+		di, dj := entries[i].IsDirective(), entries[j].IsDirective()
+		if di != dj {
+			return di
+		}
+		return strings.ToLower(entries[i].ValueName) < strings.ToLower(entries[j].ValueName)
+	})
+}
+
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) readEntry() (Entry, error) {
+	e := Entry{}
+	var err error
+	if err = r.expect('['); err != nil {
+		return e, err
+	}
+	if e.Key, err = r.readString(); err != nil {
+		return e, fmt.Errorf("while reading key: %s", err)
+	}
+	if err = r.expect(';'); err != nil {
+		return e, err
+	}
+	if e.ValueName, err = r.readString(); err != nil {
+		return e, fmt.Errorf("while reading value name: %s", err)
+	}
+	if err = r.expect(';'); err != nil {
+		return e, err
+	}
+	if e.Type, err = r.readUint32(); err != nil {
+		return e, fmt.Errorf("while reading type: %s", err)
+	}
+	if err = r.expect(';'); err != nil {
+		return e, err
+	}
+	size, err := r.readUint32()
+	if err != nil {
+		return e, fmt.Errorf("while reading size: %s", err)
+	}
+	if err = r.expect(';'); err != nil {
+		return e, err
+	}
+	if int(size) > len(r.data)-r.pos {
+		return e, fmt.Errorf("data size %d exceeds the end of the file", size)
+	}
+	e.Data = append([]byte{}, r.data[r.pos:r.pos+int(size)]...)
+	r.pos += int(size)
+	if err = r.expect(']'); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+func (r *reader) readChar() (uint16, error) {
+	if r.pos+2 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of file")
+	}
+	c := binary.LittleEndian.Uint16(r.data[r.pos:])
+	r.pos += 2
+	return c, nil
+}
+
+func (r *reader) expect(expected rune) error {
+	c, err := r.readChar()
+	if err != nil {
+		return err
+	}
+	if rune(c) != expected {
+		return fmt.Errorf("expected %q, found %q", expected, rune(c))
+	}
+	return nil
+}
+
+// readString reads a null terminated UTF-16LE string
+func (r *reader) readString() (string, error) {
+	chars := []uint16{}
+	for {
+		c, err := r.readChar()
+		if err != nil {
+			return "", err
+		}
+		if c == 0 {
+			return string(utf16.Decode(chars)), nil
+		}
+		chars = append(chars, c)
+	}
+}
+
+func (r *reader) readUint32() (uint32, error) {
+	if r.pos+4 > len(r.data) {
+		return 0, fmt.Errorf("unexpected end of file")
+	}
+	v := binary.LittleEndian.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func writeChar(buf *bytes.Buffer, c rune) {
+	_ = binary.Write(buf, binary.LittleEndian, uint16(c))
+}
+
+// writeString writes a null terminated UTF-16LE string
+func writeString(buf *bytes.Buffer, s string) {
+	buf.Write(encodeUTF16(s))
+}
+
+// encodeUTF16 returns the null terminated UTF-16LE form of a string
+func encodeUTF16(s string) []byte {
+	chars := utf16.Encode([]rune(s))
+	out := make([]byte, 0, (len(chars)+1)*2)
+	for _, c := range chars {
+		out = append(out, byte(c), byte(c>>8))
+	}
+	return append(out, 0, 0)
+}
+
+// decodeUTF16 decodes UTF-16LE data, stopping at the first null character
+func decodeUTF16(data []byte) string {
+	chars := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		c := binary.LittleEndian.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	return string(utf16.Decode(chars))
+}
@@ -0,0 +1,187 @@
+package preg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// utf16le is a test helper returning the UTF-16LE encoding of an ASCII string
+func utf16le(s string) []byte {
+	out := []byte{}
+	for _, c := range s {
+		out = append(out, byte(c), 0)
+	}
+	return out
+}
+
+func TestEncodeDWordEntry(t *testing.T) {
+	entry, err := NewEntry(`Software\Policies\Microsoft\Windows\WindowsUpdate\AU`, "NoAutoUpdate", "REG_DWORD", "1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0x50, 0x52, 0x65, 0x67, 0x01, 0x00, 0x00, 0x00}
+	expected = append(expected, utf16le("[")...)
+	expected = append(expected, utf16le(`Software\Policies\Microsoft\Windows\WindowsUpdate\AU`)...)
+	expected = append(expected, 0, 0)
+	expected = append(expected, utf16le(";")...)
+	expected = append(expected, utf16le("NoAutoUpdate")...)
+	expected = append(expected, 0, 0)
+	expected = append(expected, utf16le(";")...)
+	expected = append(expected, 0x04, 0x00, 0x00, 0x00)
+	expected = append(expected, utf16le(";")...)
+	expected = append(expected, 0x04, 0x00, 0x00, 0x00)
+	expected = append(expected, utf16le(";")...)
+	expected = append(expected, 0x01, 0x00, 0x00, 0x00)
+	expected = append(expected, utf16le("]")...)
+
+	got := Encode([]Entry{entry})
+	if !bytes.Equal(got, expected) {
+		t.Errorf("Encode() = % x, want % x", got, expected)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		typeName string
+		value    string
+		values   []string
+	}{
+		{"REG_SZ", "C:\\Windows\\Web\\Wallpaper.jpg", nil},
+		{"REG_SZ", "", nil},
+		{"REG_EXPAND_SZ", "%SystemRoot%\\system32", nil},
+		{"REG_DWORD", "4294967295", nil},
+		{"REG_DWORD_BIG_ENDIAN", "258", nil},
+		{"REG_QWORD", "18446744073709551615", nil},
+		{"REG_BINARY", "00ff10", nil},
+		{"REG_MULTI_SZ", "", []string{"first", "second", "été"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			entry, err := NewEntry(`Software\Policies\Test`, "Value", tt.typeName, tt.value, tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries, err := Parse(Encode([]Entry{entry, NewDeleteValueEntry(`Software\Policies\Test`, "Old")}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("expected 2 entries, got %d", len(entries))
+			}
+			if !reflect.DeepEqual(entries[0], entry) {
+				t.Errorf("Parse() = %+v, want %+v", entries[0], entry)
+			}
+			value, values, err := entries[0].Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tt.value {
+				t.Errorf("Value() = %q, want %q", value, tt.value)
+			}
+			if tt.values != nil && !reflect.DeepEqual(values, tt.values) {
+				t.Errorf("Value() = %v, want %v", values, tt.values)
+			}
+			if name, ok := entries[1].DeletedValue(); !ok || name != "Old" {
+				t.Errorf("DeletedValue() = %q, %t, want \"Old\", true", name, ok)
+			}
+		})
+	}
+}
+
+func TestNewEntryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		valueName string
+		typeName  string
+		value     string
+		values    []string
+	}{
+		{"unknown type", "Value", "REG_FOO", "1", nil},
+		{"directive name", "**del.Value", "REG_SZ", "x", nil},
+		{"dword not a number", "Value", "REG_DWORD", "0x10", nil},
+		{"dword overflow", "Value", "REG_DWORD", "4294967296", nil},
+		{"binary not hex", "Value", "REG_BINARY", "zz", nil},
+		{"multi sz empty string", "Value", "REG_MULTI_SZ", "", []string{"a", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEntry(`Software\Policies\Test`, tt.valueName, tt.typeName, tt.value, tt.values)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	all := NewDeleteValueEntry(`Software\Policies\Test`, "")
+	if all.ValueName != "**delvals." || !all.IsDirective() {
+		t.Errorf("unexpected delete all values directive %+v", all)
+	}
+	if name, ok := all.DeletedValue(); !ok || name != "" {
+		t.Errorf("DeletedValue() = %q, %t, want \"\", true", name, ok)
+	}
+
+	// other directives are recognized but don't delete a single value
+	secure := Entry{Key: `Software\Policies\Test`, ValueName: "**SecureKey", Type: RegDWord, Data: []byte{1, 0, 0, 0}}
+	if !secure.IsDirective() {
+		t.Error("**SecureKey should be a directive")
+	}
+	if _, ok := secure.DeletedValue(); ok {
+		t.Error("**SecureKey should not delete a value")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	valid := Encode([]Entry{NewDeleteValueEntry("Key", "")})
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"short header", []byte{0x50, 0x52}},
+		{"bad signature", []byte{0x50, 0x52, 0x45, 0x47, 0x01, 0x00, 0x00, 0x00}},
+		{"bad version", []byte{0x50, 0x52, 0x65, 0x67, 0x02, 0x00, 0x00, 0x00}},
+		{"truncated entry", valid[:len(valid)-4]},
+		{"missing bracket", append(append([]byte{}, valid[:8]...), utf16le("(")...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	entries, err := Parse(nil)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Parse(nil) = %v, %v, want no entries", entries, err)
+	}
+	entries, err = Parse(Encode(nil))
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Parse(header) = %v, %v, want no entries", entries, err)
+	}
+}
+
+func TestSort(t *testing.T) {
+	entries := []Entry{
+		{Key: `Software\B`, ValueName: "a"},
+		{Key: `Software\a`, ValueName: "Z"},
+		{Key: `Software\a`, ValueName: "b"},
+		{Key: `Software\a`, ValueName: "**delvals."},
+	}
+	Sort(entries)
+	got := []string{}
+	for _, e := range entries {
+		got = append(got, e.Key+"|"+e.ValueName)
+	}
+	expected := []string{`Software\a|**delvals.`, `Software\a|b`, `Software\a|Z`, `Software\B|a`}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Sort() = %v, want %v", got, expected)
+	}
+}
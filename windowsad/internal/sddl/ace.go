@@ -0,0 +1,261 @@
+package sddl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ACE types, as written in SDDL strings
+const (
+	ACETypeAccessAllowed       = "A"
+	ACETypeAccessDenied        = "D"
+	ACETypeObjectAccessAllowed = "OA"
+	ACETypeObjectAccessDenied  = "OD"
+	ACETypeAudit               = "AU"
+	ACETypeObjectAudit         = "OU"
+)
+
+// ACE flags
+const (
+	FlagObjectInherit      uint8 = 0x01
+	FlagContainerInherit   uint8 = 0x02
+	FlagNoPropagate        uint8 = 0x04
+	FlagInheritOnly        uint8 = 0x08
+	FlagInherited          uint8 = 0x10
+	FlagAuditSuccess       uint8 = 0x40
+	FlagAuditFailure       uint8 = 0x80
+	inheritanceFlagsFilter       = FlagObjectInherit | FlagContainerInherit | FlagNoPropagate | FlagInheritOnly
+)
+
+// aceFlagTokens lists the ACE flags in the order Windows writes them
+var aceFlagTokens = []struct {
+	token string
+	flag  uint8
+}{
+	{"OI", FlagObjectInherit},
+	{"CI", FlagContainerInherit},
+	{"NP", FlagNoPropagate},
+	{"IO", FlagInheritOnly},
+	{"ID", FlagInherited},
+	{"SA", FlagAuditSuccess},
+	{"FA", FlagAuditFailure},
+}
+
+var guidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ACE is a single access control entry. ACEs with a type this package does not know
+// about, like conditional or resource attribute ACEs, are kept as is in Raw.
+type ACE struct {
+	Type                string
+	Flags               uint8
+	Mask                uint32
+	ObjectType          string
+	InheritedObjectType string
+	Trustee             string
+	Raw                 string
+}
+
+// ParseACE parses a single ACE string, including the surrounding parentheses
+func ParseACE(s string) (*ACE, error) {
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, fmt.Errorf("ACE %q must be enclosed in parentheses", s)
+	}
+
+	fields := strings.Split(s[1:len(s)-1], ";")
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("ACE %q has %d fields, expected at least 6", s, len(fields))
+	}
+
+	aceType := strings.ToUpper(fields[0])
+	switch aceType {
+	case ACETypeAccessAllowed, ACETypeAccessDenied, ACETypeObjectAccessAllowed, ACETypeObjectAccessDenied,
+		ACETypeAudit, ACETypeObjectAudit:
+	default:
+		return &ACE{Type: aceType, Raw: s}, nil
+	}
+	if len(fields) != 6 {
+		return &ACE{Type: aceType, Raw: s}, nil
+	}
+
+	flags, err := parseACEFlags(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("ACE %q: %s", s, err)
+	}
+	mask, err := ParseAccessMask(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("ACE %q: %s", s, err)
+	}
+	for _, guid := range fields[3:5] {
+		if guid != "" && !guidRegexp.MatchString(guid) {
+			return nil, fmt.Errorf("ACE %q: %q is not a valid GUID", s, guid)
+		}
+	}
+	if fields[5] == "" {
+		return nil, fmt.Errorf("ACE %q has no trustee", s)
+	}
+
+	return &ACE{
+		Type:                aceType,
+		Flags:               flags,
+		Mask:                mask,
+		ObjectType:          strings.ToLower(fields[3]),
+		InheritedObjectType: strings.ToLower(fields[4]),
+		Trustee:             strings.ToUpper(fields[5]),
+	}, nil
+}
+
+func parseACEFlags(s string) (uint8, error) {
+	if len(s)%2 != 0 {
+		return 0, fmt.Errorf("invalid ACE flags %q", s)
+	}
+	var flags uint8
+	for i := 0; i < len(s); i += 2 {
+		token := strings.ToUpper(s[i : i+2])
+		found := false
+		for _, f := range aceFlagTokens {
+			if f.token == token {
+				flags |= f.flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown ACE flag %q", token)
+		}
+	}
+	return flags, nil
+}
+
+// String returns the ACE in SDDL form, with the trustee as it is stored in the struct
+func (a ACE) String() string {
+	if a.Raw != "" {
+		return a.Raw
+	}
+
+	var flags strings.Builder
+	for _, f := range aceFlagTokens {
+		if a.Flags&f.flag != 0 {
+			flags.WriteString(f.token)
+		}
+	}
+
+	return fmt.Sprintf("(%s;%s;%s;%s;%s;%s)", a.Type, flags.String(), FormatAccessMask(a.Mask),
+		a.ObjectType, a.InheritedObjectType, a.Trustee)
+}
+
+// IsInherited reports whether the ACE was inherited from a parent object
+func (a ACE) IsInherited() bool {
+	return a.Flags&FlagInherited != 0
+}
+
+// IsDeny reports whether the ACE denies access
+func (a ACE) IsDeny() bool {
+	return a.Type == ACETypeAccessDenied || a.Type == ACETypeObjectAccessDenied
+}
+
+// Equal reports whether two ACEs grant the same access to the same trustee. The
+// inherited flag is ignored, and object and non object ACE types without GUIDs are
+// treated as the same type.
+func (a ACE) Equal(b ACE) bool {
+	if a.Raw != "" || b.Raw != "" {
+		return a.Raw == b.Raw
+	}
+	return baseType(a) == baseType(b) &&
+		a.Flags&^FlagInherited == b.Flags&^FlagInherited &&
+		a.Mask == b.Mask &&
+		strings.EqualFold(a.ObjectType, b.ObjectType) &&
+		strings.EqualFold(a.InheritedObjectType, b.InheritedObjectType) &&
+		strings.EqualFold(a.Trustee, b.Trustee)
+}
+
+func baseType(a ACE) string {
+	if a.ObjectType != "" || a.InheritedObjectType != "" {
+		return a.Type
+	}
+	switch a.Type {
+	case ACETypeObjectAccessAllowed:
+		return ACETypeAccessAllowed
+	case ACETypeObjectAccessDenied:
+		return ACETypeAccessDenied
+	case ACETypeObjectAudit:
+		return ACETypeAudit
+	}
+	return a.Type
+}
+
+// Inheritance values, named after System.DirectoryServices.ActiveDirectorySecurityInheritance
+const (
+	InheritanceNone            = "None"
+	InheritanceAll             = "All"
+	InheritanceDescendents     = "Descendents"
+	InheritanceSelfAndChildren = "SelfAndChildren"
+	InheritanceChildren        = "Children"
+)
+
+var inheritanceFlags = map[string]uint8{
+	InheritanceNone:            0,
+	InheritanceAll:             FlagContainerInherit,
+	InheritanceDescendents:     FlagContainerInherit | FlagInheritOnly,
+	InheritanceSelfAndChildren: FlagContainerInherit | FlagNoPropagate,
+	InheritanceChildren:        FlagContainerInherit | FlagNoPropagate | FlagInheritOnly,
+}
+
+// InheritanceNames returns the supported inheritance values
+func InheritanceNames() []string {
+	return []string{InheritanceNone, InheritanceAll, InheritanceDescendents, InheritanceSelfAndChildren, InheritanceChildren}
+}
+
+// InheritanceToFlags returns the ACE flags for an inheritance value
+func InheritanceToFlags(inheritance string) (uint8, error) {
+	for name, flags := range inheritanceFlags {
+		if strings.EqualFold(name, inheritance) {
+			return flags, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown inheritance %q, expected one of %s", inheritance, strings.Join(InheritanceNames(), ", "))
+}
+
+// FlagsToInheritance returns the inheritance value matching the inheritance flags of an ACE
+func FlagsToInheritance(flags uint8) (string, error) {
+	for name, f := range inheritanceFlags {
+		if f == flags&inheritanceFlagsFilter {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("ACE flags 0x%x do not match an Active Directory inheritance type", flags)
+}
+
+// NewObjectACE builds an allow or deny ACE for a directory object. The object ACE types
+// are used when objectType or inheritedObjectType is set.
+func NewObjectACE(deny bool, mask uint32, objectType, inheritedObjectType, trusteeSID, inheritance string) (*ACE, error) {
+	flags, err := InheritanceToFlags(inheritance)
+	if err != nil {
+		return nil, err
+	}
+	for _, guid := range []string{objectType, inheritedObjectType} {
+		if guid != "" && !guidRegexp.MatchString(guid) {
+			return nil, fmt.Errorf("%q is not a valid GUID", guid)
+		}
+	}
+	if !IsSID(trusteeSID) {
+		return nil, fmt.Errorf("%q is not a SID", trusteeSID)
+	}
+
+	aceType := ACETypeAccessAllowed
+	if deny {
+		aceType = ACETypeAccessDenied
+	}
+	if objectType != "" || inheritedObjectType != "" {
+		aceType = "O" + aceType
+	}
+
+	return &ACE{
+		Type:                aceType,
+		Flags:               flags,
+		Mask:                mask,
+		ObjectType:          strings.ToLower(objectType),
+		InheritedObjectType: strings.ToLower(inheritedObjectType),
+		Trustee:             strings.ToUpper(trusteeSID),
+	}, nil
+}
@@ -0,0 +1,89 @@
+package sddl
+
+import (
+	"testing"
+)
+
+func TestACEEqual(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected bool
+	}{
+		{"(A;;RPWP;;;AU)", "(A;;WPRP;;;au)", true},
+		{"(A;CI;RP;;;AU)", "(A;CIID;RP;;;AU)", true},
+		{"(OA;;RP;;;AU)", "(A;;RP;;;AU)", true},
+		{"(OA;;RP;BF967ABA-0DE6-11D0-A285-00AA003049E2;;AU)", "(OA;;RP;bf967aba-0de6-11d0-a285-00aa003049e2;;AU)", true},
+		{"(A;CI;RP;;;AU)", "(A;;RP;;;AU)", false},
+		{"(A;;RP;;;AU)", "(D;;RP;;;AU)", false},
+		{"(OA;;RP;bf967aba-0de6-11d0-a285-00aa003049e2;;AU)", "(OA;;RP;;bf967aba-0de6-11d0-a285-00aa003049e2;AU)", false},
+	}
+
+	for _, tt := range tests {
+		a, err := ParseACE(tt.a)
+		if err != nil {
+			t.Fatalf("ParseACE(%q) unexpected error: %s", tt.a, err)
+		}
+		b, err := ParseACE(tt.b)
+		if err != nil {
+			t.Fatalf("ParseACE(%q) unexpected error: %s", tt.b, err)
+		}
+		if got := a.Equal(*b); got != tt.expected {
+			t.Errorf("%s.Equal(%s) = %t, want %t", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestNewObjectACE(t *testing.T) {
+	tests := []struct {
+		deny                bool
+		mask                uint32
+		objectType          string
+		inheritedObjectType string
+		trustee             string
+		inheritance         string
+		expected            string
+		wantErr             bool
+	}{
+		{false, RightCreateChild | RightDeleteChild, "bf967a86-0de6-11d0-a285-00aa003049e2", "", "S-1-5-21-1-2-3-1105", "All",
+			"(OA;CI;CCDC;bf967a86-0de6-11d0-a285-00aa003049e2;;S-1-5-21-1-2-3-1105)", false},
+		{false, RightExtendedRight, "00299570-246d-11d0-a768-00aa006e0529", "bf967aba-0de6-11d0-a285-00aa003049e2", "S-1-5-21-1-2-3-1105", "Descendents",
+			"(OA;CIIO;CR;00299570-246d-11d0-a768-00aa006e0529;bf967aba-0de6-11d0-a285-00aa003049e2;S-1-5-21-1-2-3-1105)", false},
+		{true, RightWriteProperty, "", "", "s-1-5-11", "None", "(D;;WP;;;S-1-5-11)", false},
+		{false, RightReadProperty, "", "", "S-1-5-11", "Children", "(A;CINPIO;RP;;;S-1-5-11)", false},
+		{false, RightReadProperty, "", "", "AU", "None", "", true},
+		{false, RightReadProperty, "user", "", "S-1-5-11", "None", "", true},
+		{false, RightReadProperty, "", "", "S-1-5-11", "Everything", "", true},
+	}
+
+	for _, tt := range tests {
+		ace, err := NewObjectACE(tt.deny, tt.mask, tt.objectType, tt.inheritedObjectType, tt.trustee, tt.inheritance)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewObjectACE(%q) error = %v, wantErr %t", tt.expected, err, tt.wantErr)
+			continue
+		}
+		if err == nil && ace.String() != tt.expected {
+			t.Errorf("NewObjectACE() = %q, want %q", ace.String(), tt.expected)
+		}
+	}
+}
+
+func TestFlagsToInheritance(t *testing.T) {
+	for _, name := range InheritanceNames() {
+		flags, err := InheritanceToFlags(name)
+		if err != nil {
+			t.Fatalf("InheritanceToFlags(%q) unexpected error: %s", name, err)
+		}
+		got, err := FlagsToInheritance(flags | FlagInherited)
+		if err != nil {
+			t.Fatalf("FlagsToInheritance(0x%x) unexpected error: %s", flags, err)
+		}
+		if got != name {
+			t.Errorf("FlagsToInheritance(InheritanceToFlags(%q)) = %q", name, got)
+		}
+	}
+
+	if _, err := FlagsToInheritance(FlagObjectInherit); err == nil {
+		t.Error("FlagsToInheritance(OI) expected an error")
+	}
+}
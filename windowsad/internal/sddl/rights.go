@@ -0,0 +1,213 @@
+package sddl
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Access rights of directory service objects
+const (
+	RightCreateChild          uint32 = 0x00000001
+	RightDeleteChild          uint32 = 0x00000002
+	RightListChildren         uint32 = 0x00000004
+	RightSelf                 uint32 = 0x00000008
+	RightReadProperty         uint32 = 0x00000010
+	RightWriteProperty        uint32 = 0x00000020
+	RightDeleteTree           uint32 = 0x00000040
+	RightListObject           uint32 = 0x00000080
+	RightExtendedRight        uint32 = 0x00000100
+	RightDelete               uint32 = 0x00010000
+	RightReadControl          uint32 = 0x00020000
+	RightWriteDacl            uint32 = 0x00040000
+	RightWriteOwner           uint32 = 0x00080000
+	RightSynchronize          uint32 = 0x00100000
+	RightAccessSystemSecurity uint32 = 0x01000000
+	RightGenericAll           uint32 = 0x10000000
+	RightGenericExecute       uint32 = 0x20000000
+	RightGenericWrite         uint32 = 0x40000000
+	RightGenericRead          uint32 = 0x80000000
+)
+
+// Generic rights, as mapped by Active Directory when an ACE is stored
+const (
+	MappedGenericRead    = RightReadControl | RightListChildren | RightReadProperty | RightListObject
+	MappedGenericWrite   = RightReadControl | RightWriteProperty | RightSelf
+	MappedGenericExecute = RightReadControl | RightListChildren
+	MappedGenericAll     = RightCreateChild | RightDeleteChild | RightListChildren | RightSelf | RightReadProperty |
+		RightWriteProperty | RightDeleteTree | RightListObject | RightExtendedRight | RightDelete | RightReadControl |
+		RightWriteDacl | RightWriteOwner
+)
+
+// maskTokens lists the SDDL access right tokens in the order Windows writes them
+var maskTokens = []struct {
+	token string
+	mask  uint32
+}{
+	{"GA", RightGenericAll},
+	{"GR", RightGenericRead},
+	{"GW", RightGenericWrite},
+	{"GX", RightGenericExecute},
+	{"CC", RightCreateChild},
+	{"DC", RightDeleteChild},
+	{"LC", RightListChildren},
+	{"SW", RightSelf},
+	{"RP", RightReadProperty},
+	{"WP", RightWriteProperty},
+	{"DT", RightDeleteTree},
+	{"LO", RightListObject},
+	{"CR", RightExtendedRight},
+	{"SD", RightDelete},
+	{"RC", RightReadControl},
+	{"WD", RightWriteDacl},
+	{"WO", RightWriteOwner},
+}
+
+// rightNames maps the names of System.DirectoryServices.ActiveDirectoryRights to access masks.
+// Generic rights are mapped to the specific rights Active Directory stores.
+var rightNames = map[string]uint32{
+	"CreateChild":          RightCreateChild,
+	"DeleteChild":          RightDeleteChild,
+	"ListChildren":         RightListChildren,
+	"Self":                 RightSelf,
+	"ReadProperty":         RightReadProperty,
+	"WriteProperty":        RightWriteProperty,
+	"DeleteTree":           RightDeleteTree,
+	"ListObject":           RightListObject,
+	"ExtendedRight":        RightExtendedRight,
+	"Delete":               RightDelete,
+	"ReadControl":          RightReadControl,
+	"WriteDacl":            RightWriteDacl,
+	"WriteOwner":           RightWriteOwner,
+	"Synchronize":          RightSynchronize,
+	"AccessSystemSecurity": RightAccessSystemSecurity,
+	"GenericRead":          MappedGenericRead,
+	"GenericWrite":         MappedGenericWrite,
+	"GenericExecute":       MappedGenericExecute,
+	"GenericAll":           MappedGenericAll,
+}
+
+// specificRightNames lists the rights that map to a single bit, used to describe a mask
+var specificRightNames = []string{
+	"CreateChild", "DeleteChild", "ListChildren", "Self", "ReadProperty", "WriteProperty", "DeleteTree",
+	"ListObject", "ExtendedRight", "Delete", "ReadControl", "WriteDacl", "WriteOwner", "Synchronize",
+	"AccessSystemSecurity",
+}
+
+// RightNames returns the names of the supported access rights, sorted alphabetically
+func RightNames() []string {
+	names := make([]string, 0, len(rightNames))
+	for name := range rightNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RightsToMask returns the access mask for a list of ActiveDirectoryRights names
+func RightsToMask(rights []string) (uint32, error) {
+	var mask uint32
+	for _, right := range rights {
+		found := false
+		for name, m := range rightNames {
+			if strings.EqualFold(name, right) {
+				mask |= m
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown access right %q", right)
+		}
+	}
+	return mask, nil
+}
+
+// MaskToRights describes an access mask as a list of single bit ActiveDirectoryRights names
+func MaskToRights(mask uint32) []string {
+	mask = mapGenericRights(mask)
+	rights := []string{}
+	for _, name := range specificRightNames {
+		if mask&rightNames[name] != 0 {
+			rights = append(rights, name)
+		}
+	}
+	return rights
+}
+
+func mapGenericRights(mask uint32) uint32 {
+	generic := []struct {
+		bit    uint32
+		mapped uint32
+	}{
+		{RightGenericAll, MappedGenericAll},
+		{RightGenericRead, MappedGenericRead},
+		{RightGenericWrite, MappedGenericWrite},
+		{RightGenericExecute, MappedGenericExecute},
+	}
+	for _, g := range generic {
+		if mask&g.bit != 0 {
+			mask = mask&^g.bit | g.mapped
+		}
+	}
+	return mask
+}
+
+// ParseAccessMask parses the rights field of an ACE, either a hexadecimal or decimal
+// number or a list of two letter tokens like RPWP. Generic rights are mapped to the
+// specific rights Active Directory stores.
+func ParseAccessMask(s string) (uint32, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	lower := strings.ToLower(s)
+	if strings.HasPrefix(lower, "0x") {
+		v, err := strconv.ParseUint(lower[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid access mask %q: %s", s, err)
+		}
+		return mapGenericRights(uint32(v)), nil
+	}
+	if v, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return mapGenericRights(uint32(v)), nil
+	}
+
+	if len(s)%2 != 0 {
+		return 0, fmt.Errorf("invalid access mask %q", s)
+	}
+	var mask uint32
+	for i := 0; i < len(s); i += 2 {
+		token := strings.ToUpper(s[i : i+2])
+		found := false
+		for _, t := range maskTokens {
+			if t.token == token {
+				mask |= t.mask
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown access right %q in %q", token, s)
+		}
+	}
+	return mapGenericRights(mask), nil
+}
+
+// FormatAccessMask returns the SDDL form of an access mask. A hexadecimal number is used
+// if the mask has bits that can't be written as tokens.
+func FormatAccessMask(mask uint32) string {
+	var b strings.Builder
+	remaining := mask
+	for _, t := range maskTokens {
+		if remaining&t.mask != 0 {
+			b.WriteString(t.token)
+			remaining &^= t.mask
+		}
+	}
+	if remaining != 0 {
+		return fmt.Sprintf("0x%x", mask)
+	}
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package sddl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccessMask(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected uint32
+		wantErr  bool
+	}{
+		{"", 0, false},
+		{"RPWP", RightReadProperty | RightWriteProperty, false},
+		{"rpwp", RightReadProperty | RightWriteProperty, false},
+		{"GA", MappedGenericAll, false},
+		{"GR", MappedGenericRead, false},
+		{"0x30", RightReadProperty | RightWriteProperty, false},
+		{"983551", MappedGenericAll, false},
+		{"RPW", 0, true},
+		{"RPXX", 0, true},
+		{"0xZZ", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAccessMask(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAccessMask(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseAccessMask(%q) = 0x%x, want 0x%x", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatAccessMask(t *testing.T) {
+	tests := []struct {
+		mask     uint32
+		expected string
+	}{
+		{0, ""},
+		{RightReadProperty | RightWriteProperty, "RPWP"},
+		{MappedGenericAll, "CCDCLCSWRPWPDTLOCRSDRCWDWO"},
+		{RightReadProperty | RightSynchronize, "0x100010"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAccessMask(tt.mask); got != tt.expected {
+			t.Errorf("FormatAccessMask(0x%x) = %q, want %q", tt.mask, got, tt.expected)
+		}
+	}
+}
+
+func TestRightsToMask(t *testing.T) {
+	mask, err := RightsToMask([]string{"ReadProperty", "writeproperty"})
+	if err != nil {
+		t.Fatalf("RightsToMask() unexpected error: %s", err)
+	}
+	if mask != RightReadProperty|RightWriteProperty {
+		t.Errorf("RightsToMask() = 0x%x", mask)
+	}
+
+	mask, err = RightsToMask([]string{"GenericAll"})
+	if err != nil {
+		t.Fatalf("RightsToMask() unexpected error: %s", err)
+	}
+	if mask != MappedGenericAll {
+		t.Errorf("RightsToMask(GenericAll) = 0x%x, want 0x%x", mask, MappedGenericAll)
+	}
+
+	if _, err := RightsToMask([]string{"FullControl"}); err == nil {
+		t.Error("RightsToMask(FullControl) expected an error")
+	}
+}
+
+func TestMaskToRights(t *testing.T) {
+	got := MaskToRights(RightGenericRead)
+	expected := []string{"ListChildren", "ReadProperty", "ListObject", "ReadControl"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("MaskToRights(GenericRead) = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,77 @@
+package sddl
+
+import (
+	"strings"
+)
+
+// wellKnownSchemaGUIDs maps the names of schema classes, attributes, property sets and
+// extended rights that are part of the base Active Directory schema to their GUIDs.
+// These GUIDs are the same in every forest, so they can be resolved without querying the
+// schema. Other names have to be looked up in the schema of the forest.
+var wellKnownSchemaGUIDs = map[string]string{
+	// classes
+	"computer":                        "bf967a86-0de6-11d0-a285-00aa003049e2",
+	"contact":                         "5cb41ed0-0e4c-11d0-a286-00aa003049e2",
+	"group":                           "bf967a9c-0de6-11d0-a285-00aa003049e2",
+	"groupPolicyContainer":            "f30e3bc2-9ff0-11d1-b603-0000f80367c1",
+	"inetOrgPerson":                   "4828cc14-1437-45bc-9b07-ad6f015e5f28",
+	"msDS-GroupManagedServiceAccount": "7b8b558a-93a5-4af7-adca-c017e67f1057",
+	"organizationalUnit":              "bf967aa5-0de6-11d0-a285-00aa003049e2",
+	"printQueue":                      "bf967aa8-0de6-11d0-a285-00aa003049e2",
+	"user":                            "bf967aba-0de6-11d0-a285-00aa003049e2",
+
+	// attributes
+	"description": "bf967950-0de6-11d0-a285-00aa003049e2",
+	"displayName": "bf967953-0de6-11d0-a285-00aa003049e2",
+	"gPLink":      "f30e3bbe-9ff0-11d1-b603-0000f80367c1",
+	"gPOptions":   "f30e3bbf-9ff0-11d1-b603-0000f80367c1",
+	"lockoutTime": "28630ebf-41d5-11d1-a9c1-0000f80367c1",
+	"managedBy":   "0296c120-40da-11d1-a9c0-0000f80367c1",
+	"member":      "bf9679c0-0de6-11d0-a285-00aa003049e2",
+	"msDS-AllowedToActOnBehalfOfOtherIdentity": "3f78c3e5-f79a-46bd-a0b8-9d18116ddc79",
+	"msDS-KeyCredentialLink":                   "5b47d60f-6090-40b2-9f37-2a4de88f3063",
+	"pwdLastSet":                               "bf967a0a-0de6-11d0-a285-00aa003049e2",
+	"servicePrincipalName":                     "f3a64788-5306-11d1-a9c5-0000f80367c1",
+	"userAccountControl":                       "bf967a68-0de6-11d0-a285-00aa003049e2",
+
+	// property sets
+	"General-Information":       "59ba2f42-79a2-11d0-9020-00c04fc2d3cf",
+	"Membership":                "bc0ac240-79a9-11d0-9020-00c04fc2d4cf",
+	"Personal-Information":      "77b5b886-944a-11d1-aebd-0000f80367c1",
+	"Public-Information":        "e48d0154-bcf8-11d1-8702-00c04fb96050",
+	"User-Account-Restrictions": "4c164200-20c0-11d0-a768-00aa006e0529",
+	"User-Logon":                "5f202010-79a5-11d0-9020-00c04fc2d4cf",
+
+	// extended rights and validated writes
+	"Allowed-To-Authenticate":        "68b1d179-0d15-4d4f-ab71-46152e79a7bc",
+	"Apply-Group-Policy":             "edacfd8f-ffb3-11d1-b41d-00a0c968f939",
+	"Change Password":                "ab721a53-1e2f-11d0-9819-00aa0040529b",
+	"DS-Replication-Get-Changes":     "1131f6aa-9c07-11d1-f79f-00c04fc2dcd2",
+	"DS-Replication-Get-Changes-All": "1131f6ad-9c07-11d1-f79f-00c04fc2dcd2",
+	"Receive-As":                     "ab721a56-1e2f-11d0-9819-00aa0040529b",
+	"Reset Password":                 "00299570-246d-11d0-a768-00aa006e0529",
+	"Self-Membership":                "bf9679c0-0de6-11d0-a285-00aa003049e2",
+	"Send-As":                        "ab721a54-1e2f-11d0-9819-00aa0040529b",
+	"User-Change-Password":           "ab721a53-1e2f-11d0-9819-00aa0040529b",
+	"User-Force-Change-Password":     "00299570-246d-11d0-a768-00aa006e0529",
+	"Validated-DNS-Host-Name":        "72e39547-7b18-11d1-adef-00c04fd8d5cd",
+	"Validated-SPN":                  "f3a64788-5306-11d1-a9c5-0000f80367c1",
+}
+
+// LookupSchemaGUID returns the GUID for a well known schema object or extended right
+// name, compared case insensitively. GUIDs are returned as is, lower cased. The second
+// return value is false if the name is not known.
+func LookupSchemaGUID(name string) (string, bool) {
+	if name == "" {
+		return "", true
+	}
+	if IsGUID(name) {
+		return strings.ToLower(name), true
+	}
+	for n, guid := range wellKnownSchemaGUIDs {
+		if strings.EqualFold(n, name) {
+			return guid, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,27 @@
+package sddl
+
+import (
+	"testing"
+)
+
+func TestLookupSchemaGUID(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		found    bool
+	}{
+		{"", "", true},
+		{"user", "bf967aba-0de6-11d0-a285-00aa003049e2", true},
+		{"User", "bf967aba-0de6-11d0-a285-00aa003049e2", true},
+		{"Reset Password", "00299570-246d-11d0-a768-00aa006e0529", true},
+		{"BF967ABA-0DE6-11D0-A285-00AA003049E2", "bf967aba-0de6-11d0-a285-00aa003049e2", true},
+		{"ms-Mcs-AdmPwd", "", false},
+	}
+
+	for _, tt := range tests {
+		got, found := LookupSchemaGUID(tt.name)
+		if got != tt.expected || found != tt.found {
+			t.Errorf("LookupSchemaGUID(%q) = %q, %t, want %q, %t", tt.name, got, found, tt.expected, tt.found)
+		}
+	}
+}
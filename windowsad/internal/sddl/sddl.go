@@ -0,0 +1,211 @@
+// Package sddl parses and serializes security descriptors in the Security Descriptor
+// Definition Language (SDDL) so access control entries can be compared without a round
+// trip to a domain controller.
+// (https://docs.microsoft.com/en-us/windows/win32/secauthz/security-descriptor-string-format)
+package sddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SecurityDescriptor holds the parts of a security descriptor string. Parts that are
+// not present in the string are left empty.
+type SecurityDescriptor struct {
+	Owner     string
+	Group     string
+	DACLFlags string
+	DACL      []ACE
+	HasDACL   bool
+	SACLFlags string
+	SACL      []ACE
+	HasSACL   bool
+}
+
+// Parse parses a security descriptor string such as
+// O:DAG:DAD:PAI(A;;RPWP;;;AU)(OA;CI;CCDC;bf967aba-0de6-11d0-a285-00aa003049e2;;DA)
+func Parse(s string) (*SecurityDescriptor, error) {
+	sd := &SecurityDescriptor{}
+	rest := strings.TrimSpace(s)
+
+	for rest != "" {
+		if len(rest) < 2 || rest[1] != ':' {
+			return nil, fmt.Errorf("invalid security descriptor %q: expected a component at %q", s, rest)
+		}
+		component := rest[0]
+		rest = rest[2:]
+		end := nextComponent(rest)
+		value := rest[:end]
+		rest = rest[end:]
+
+		var err error
+		switch component {
+		case 'O':
+			sd.Owner = value
+		case 'G':
+			sd.Group = value
+		case 'D':
+			sd.HasDACL = true
+			sd.DACLFlags, sd.DACL, err = parseACL(value)
+		case 'S':
+			sd.HasSACL = true
+			sd.SACLFlags, sd.SACL, err = parseACL(value)
+		default:
+			return nil, fmt.Errorf("invalid security descriptor %q: unknown component %q", s, string(component))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid security descriptor %q: %s", s, err)
+		}
+	}
+
+	return sd, nil
+}
+
+// nextComponent returns the index at which the next O:, G:, D: or S: component starts.
+// Parentheses are skipped so the content of ACEs is never mistaken for a component.
+func nextComponent(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth == 0 && i+1 < len(s) && s[i+1] == ':' && strings.IndexByte("OGDS", s[i]) >= 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// parseACL splits an ACL string into its flags and ACEs
+func parseACL(s string) (string, []ACE, error) {
+	idx := strings.IndexByte(s, '(')
+	if idx < 0 {
+		return s, []ACE{}, nil
+	}
+	flags := s[:idx]
+	aces := []ACE{}
+
+	rest := s[idx:]
+	for rest != "" {
+		if rest[0] != '(' {
+			return "", nil, fmt.Errorf("unexpected %q between ACEs", rest)
+		}
+		end := matchingParenthesis(rest)
+		if end < 0 {
+			return "", nil, fmt.Errorf("unbalanced parentheses in %q", rest)
+		}
+		ace, err := ParseACE(rest[:end+1])
+		if err != nil {
+			return "", nil, err
+		}
+		aces = append(aces, *ace)
+		rest = rest[end+1:]
+	}
+	return flags, aces, nil
+}
+
+func matchingParenthesis(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// String returns the security descriptor in SDDL form
+func (sd *SecurityDescriptor) String() string {
+	var b strings.Builder
+	if sd.Owner != "" {
+		b.WriteString("O:" + sd.Owner)
+	}
+	if sd.Group != "" {
+		b.WriteString("G:" + sd.Group)
+	}
+	if sd.HasDACL {
+		b.WriteString("D:" + sd.DACLFlags)
+		for _, ace := range sd.DACL {
+			b.WriteString(ace.String())
+		}
+	}
+	if sd.HasSACL {
+		b.WriteString("S:" + sd.SACLFlags)
+		for _, ace := range sd.SACL {
+			b.WriteString(ace.String())
+		}
+	}
+	return b.String()
+}
+
+// NormalizeTrustees replaces the SID aliases of the owner, group and ACE trustees with
+// the SIDs they stand for. domainSID and rootDomainSID are used for aliases that are
+// relative to a domain, like DA or EA. Aliases that can't be resolved are left as is.
+func (sd *SecurityDescriptor) NormalizeTrustees(domainSID, rootDomainSID string) {
+	sd.Owner = ResolveSIDAlias(sd.Owner, domainSID, rootDomainSID)
+	sd.Group = ResolveSIDAlias(sd.Group, domainSID, rootDomainSID)
+	for idx := range sd.DACL {
+		sd.DACL[idx].Trustee = ResolveSIDAlias(sd.DACL[idx].Trustee, domainSID, rootDomainSID)
+	}
+	for idx := range sd.SACL {
+		sd.SACL[idx].Trustee = ResolveSIDAlias(sd.SACL[idx].Trustee, domainSID, rootDomainSID)
+	}
+}
+
+// ContainsExplicitACE reports whether the DACL has an ACE, not inherited from a parent,
+// that is equal to ace
+func (sd *SecurityDescriptor) ContainsExplicitACE(ace ACE) bool {
+	for _, a := range sd.DACL {
+		if !a.IsInherited() && a.Equal(ace) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddACE adds ace to the DACL in canonical order: explicit deny ACEs first, then explicit
+// allow ACEs, then inherited ACEs. Nothing is done if an equal explicit ACE is already present.
+func (sd *SecurityDescriptor) AddACE(ace ACE) {
+	sd.HasDACL = true
+	if sd.ContainsExplicitACE(ace) {
+		return
+	}
+
+	pos := len(sd.DACL)
+	for idx, a := range sd.DACL {
+		if a.IsInherited() || (ace.IsDeny() && !a.IsDeny()) {
+			pos = idx
+			break
+		}
+	}
+
+	sd.DACL = append(sd.DACL, ACE{})
+	copy(sd.DACL[pos+1:], sd.DACL[pos:])
+	sd.DACL[pos] = ace
+}
+
+// RemoveACE removes all the explicit ACEs of the DACL that are equal to ace and returns
+// the number of ACEs that were removed
+func (sd *SecurityDescriptor) RemoveACE(ace ACE) int {
+	removed := 0
+	dacl := make([]ACE, 0, len(sd.DACL))
+	for _, a := range sd.DACL {
+		if !a.IsInherited() && a.Equal(ace) {
+			removed++
+			continue
+		}
+		dacl = append(dacl, a)
+	}
+	sd.DACL = dacl
+	return removed
+}
@@ -0,0 +1,108 @@
+package sddl
+
+import (
+	"testing"
+)
+
+const testDomainSID = "S-1-5-21-1004336348-1177238915-682003330"
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "O:DAG:DUD:PAI(A;;RPWP;;;AU)(OA;CI;CCDC;bf967aba-0de6-11d0-a285-00aa003049e2;;DA)",
+			expected: "O:DAG:DUD:PAI(A;;RPWP;;;AU)(OA;CI;CCDC;bf967aba-0de6-11d0-a285-00aa003049e2;;DA)",
+		},
+		{
+			input:    "D:(A;CIID;GA;;;SY)",
+			expected: "D:(A;CIID;CCDCLCSWRPWPDTLOCRSDRCWDWO;;;SY)",
+		},
+		{
+			input:    "D:AI(XA;;FX;;;S-1-1-0;(@User.Title==\"PM\"))(A;;0x100;;;BA)",
+			expected: "D:AI(XA;;FX;;;S-1-1-0;(@User.Title==\"PM\"))(A;;CR;;;BA)",
+		},
+		{
+			input:    "D:PS:(AU;SA;WD;;;WD)",
+			expected: "D:PS:(AU;SA;WD;;;WD)",
+		},
+		{
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		sd, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %s", tt.input, err)
+			continue
+		}
+		if got := sd.String(); got != tt.expected {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		"X:DA",
+		"D:(A;;RPWP;;AU)",
+		"D:(A;;ZZ;;;AU)",
+		"D:(A;;RP;not-a-guid;;AU)",
+		"D:(A;;RP;;;AU",
+		"D:(A;;RP;;;AU)junk",
+	}
+
+	for _, input := range inputs {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected an error", input)
+		}
+	}
+}
+
+func TestNormalizeTrustees(t *testing.T) {
+	sd, err := Parse("O:DAG:DUD:(A;;RP;;;AU)(A;;RP;;;EA)(A;;RP;;;s-1-5-21-1-2-3-1105)")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %s", err)
+	}
+	sd.NormalizeTrustees(testDomainSID, "")
+
+	expected := "O:" + testDomainSID + "-512G:" + testDomainSID + "-513D:(A;;RP;;;S-1-5-11)(A;;RP;;;" + testDomainSID + "-519)(A;;RP;;;S-1-5-21-1-2-3-1105)"
+	if got := sd.String(); got != expected {
+		t.Errorf("NormalizeTrustees() = %q, want %q", got, expected)
+	}
+}
+
+func TestAddAndRemoveACE(t *testing.T) {
+	sd, err := Parse("D:PAI(D;;WP;;;S-1-5-21-1-2-3-1000)(A;;RP;;;S-1-5-11)(A;CIID;RP;;;S-1-5-18)")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %s", err)
+	}
+
+	allow, _ := ParseACE("(A;;CC;;;S-1-5-21-1-2-3-1001)")
+	deny, _ := ParseACE("(D;;DC;;;S-1-5-21-1-2-3-1002)")
+	sd.AddACE(*allow)
+	sd.AddACE(*deny)
+	sd.AddACE(*allow)
+
+	expected := "D:PAI(D;;WP;;;S-1-5-21-1-2-3-1000)(D;;DC;;;S-1-5-21-1-2-3-1002)(A;;RP;;;S-1-5-11)(A;;CC;;;S-1-5-21-1-2-3-1001)(A;CIID;RP;;;S-1-5-18)"
+	if got := sd.String(); got != expected {
+		t.Errorf("AddACE() = %q, want %q", got, expected)
+	}
+
+	inherited, _ := ParseACE("(A;CI;RP;;;S-1-5-18)")
+	if sd.ContainsExplicitACE(*inherited) {
+		t.Error("ContainsExplicitACE() returned true for an inherited ACE")
+	}
+	if removed := sd.RemoveACE(*inherited); removed != 0 {
+		t.Errorf("RemoveACE() removed %d inherited ACEs", removed)
+	}
+	if removed := sd.RemoveACE(*allow); removed != 1 {
+		t.Errorf("RemoveACE() removed %d ACEs, want 1", removed)
+	}
+	if sd.ContainsExplicitACE(*allow) {
+		t.Error("ContainsExplicitACE() returned true for a removed ACE")
+	}
+}
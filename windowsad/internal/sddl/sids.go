@@ -0,0 +1,116 @@
+package sddl
+
+import (
+	"regexp"
+	"strings"
+)
+
+var sidRegexp = regexp.MustCompile(`^[sS]-1(-\d+)+$`)
+
+// IsSID reports whether s is a SID in its string form, e.g. S-1-5-32-544
+func IsSID(s string) bool {
+	return sidRegexp.MatchString(s)
+}
+
+// IsGUID reports whether s is a GUID in its string form
+func IsGUID(s string) bool {
+	return guidRegexp.MatchString(s)
+}
+
+// wellKnownSIDAliases maps the SDDL aliases of well known SIDs to their values
+// (https://docs.microsoft.com/en-us/windows/win32/secauthz/sid-strings)
+var wellKnownSIDAliases = map[string]string{
+	"AC": "S-1-15-2-1",
+	"AN": "S-1-5-7",
+	"AO": "S-1-5-32-548",
+	"AS": "S-1-18-1",
+	"AU": "S-1-5-11",
+	"BA": "S-1-5-32-544",
+	"BG": "S-1-5-32-546",
+	"BO": "S-1-5-32-551",
+	"BU": "S-1-5-32-545",
+	"CD": "S-1-5-32-574",
+	"CG": "S-1-3-1",
+	"CO": "S-1-3-0",
+	"CY": "S-1-5-32-569",
+	"ED": "S-1-5-9",
+	"ER": "S-1-5-32-573",
+	"ES": "S-1-5-32-576",
+	"HA": "S-1-5-32-578",
+	"HI": "S-1-16-12288",
+	"IS": "S-1-5-32-568",
+	"IU": "S-1-5-4",
+	"LS": "S-1-5-19",
+	"LU": "S-1-5-32-559",
+	"LW": "S-1-16-4096",
+	"ME": "S-1-16-8192",
+	"MU": "S-1-5-32-558",
+	"NO": "S-1-5-32-556",
+	"NS": "S-1-5-20",
+	"NU": "S-1-5-2",
+	"OW": "S-1-3-4",
+	"PO": "S-1-5-32-550",
+	"PS": "S-1-5-10",
+	"PU": "S-1-5-32-547",
+	"RA": "S-1-5-32-575",
+	"RC": "S-1-5-12",
+	"RD": "S-1-5-32-555",
+	"RE": "S-1-5-32-552",
+	"RM": "S-1-5-32-580",
+	"RU": "S-1-5-32-554",
+	"SI": "S-1-16-16384",
+	"SO": "S-1-5-32-549",
+	"SS": "S-1-18-2",
+	"SU": "S-1-5-6",
+	"SY": "S-1-5-18",
+	"WD": "S-1-1-0",
+	"WR": "S-1-5-33",
+}
+
+// domainSIDAliases maps the SDDL aliases of domain accounts to their relative ID
+var domainSIDAliases = map[string]string{
+	"AP": "525",
+	"CA": "517",
+	"CN": "522",
+	"DA": "512",
+	"DC": "515",
+	"DD": "516",
+	"DG": "514",
+	"DU": "513",
+	"KA": "526",
+	"LA": "500",
+	"LG": "501",
+	"PA": "520",
+	"RS": "553",
+}
+
+// rootDomainSIDAliases maps the SDDL aliases of forest wide accounts, which live in the
+// forest root domain, to their relative ID
+var rootDomainSIDAliases = map[string]string{
+	"EA": "519",
+	"EK": "527",
+	"RO": "498",
+	"SA": "518",
+}
+
+// ResolveSIDAlias returns the SID an SDDL alias stands for. SIDs and unknown aliases are
+// returned unchanged, upper cased. Domain relative aliases are only resolved if the
+// matching domain SID is given.
+func ResolveSIDAlias(alias, domainSID, rootDomainSID string) string {
+	alias = strings.ToUpper(alias)
+	if sid, ok := wellKnownSIDAliases[alias]; ok {
+		return sid
+	}
+	if rid, ok := domainSIDAliases[alias]; ok && domainSID != "" {
+		return strings.ToUpper(domainSID) + "-" + rid
+	}
+	if rid, ok := rootDomainSIDAliases[alias]; ok {
+		if rootDomainSID == "" {
+			rootDomainSID = domainSID
+		}
+		if rootDomainSID != "" {
+			return strings.ToUpper(rootDomainSID) + "-" + rid
+		}
+	}
+	return alias
+}
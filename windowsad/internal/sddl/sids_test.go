@@ -0,0 +1,44 @@
+package sddl
+
+import (
+	"testing"
+)
+
+func TestResolveSIDAlias(t *testing.T) {
+	tests := []struct {
+		alias         string
+		domainSID     string
+		rootDomainSID string
+		expected      string
+	}{
+		{"AU", "", "", "S-1-5-11"},
+		{"ba", "", "", "S-1-5-32-544"},
+		{"DA", "S-1-5-21-1-2-3", "", "S-1-5-21-1-2-3-512"},
+		{"DA", "", "", "DA"},
+		{"EA", "S-1-5-21-1-2-3", "", "S-1-5-21-1-2-3-519"},
+		{"EA", "S-1-5-21-1-2-3", "S-1-5-21-4-5-6", "S-1-5-21-4-5-6-519"},
+		{"s-1-5-21-1-2-3-1105", "S-1-5-21-1-2-3", "", "S-1-5-21-1-2-3-1105"},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveSIDAlias(tt.alias, tt.domainSID, tt.rootDomainSID); got != tt.expected {
+			t.Errorf("ResolveSIDAlias(%q, %q, %q) = %q, want %q", tt.alias, tt.domainSID, tt.rootDomainSID, got, tt.expected)
+		}
+	}
+}
+
+func TestIsSID(t *testing.T) {
+	valid := []string{"S-1-5-11", "s-1-5-21-1004336348-1177238915-682003330-512", "S-1-1-0"}
+	invalid := []string{"", "AU", "S-1", "S-1-5-", "S-2-5-11", "CN=user,DC=contoso,DC=com"}
+
+	for _, s := range valid {
+		if !IsSID(s) {
+			t.Errorf("IsSID(%q) = false, want true", s)
+		}
+	}
+	for _, s := range invalid {
+		if IsSID(s) {
+			t.Errorf("IsSID(%q) = true, want false", s)
+		}
+	}
+}
@@ -13,30 +13,66 @@ import (
 
 // Computer struct represents an AD Computer account object
 type Computer struct {
-	Name           string
-	GUID           string `json:"ObjectGuid"`
-	DN             string `json:"DistinguishedName"`
-	Description    string
-	SAMAccountName string `json:"SamAccountName"`
-	Path           string
-	SID            SID `json:"SID"`
+	Name                       string
+	GUID                       string `json:"ObjectGuid"`
+	DN                         string `json:"DistinguishedName"`
+	Description                string
+	SAMAccountName             string `json:"SamAccountName"`
+	Path                       string
+	SID                        SID `json:"SID"`
+	Enabled                    bool
+	DNSHostName                string `json:"DNSHostName"`
+	Location                   string
+	ManagedBy                  string
+	ServicePrincipalNames      []string
+	OperatingSystem            string
+	OperatingSystemVersion     string
+	OperatingSystemServicePack string
+	OperatingSystemHotfix      string
+	TrustedForDelegation       bool
+	Protected                  bool `json:"ProtectedFromAccidentalDeletion"`
+	// Constrained and resource-based constrained delegation settings, see KerberosDelegation
+	AllowedToDelegateTo                  []string `json:"msDS-AllowedToDelegateTo"`
+	TrustedToAuthForDelegation           bool
+	PrincipalsAllowedToDelegateToAccount []string
+	CustomAttributes                     map[string]interface{} `json:"-"`
 }
 
 // NewComputerFromResource returns a new Machine struct populated from resource data
-func NewComputerFromResource(d *schema.ResourceData) *Computer {
-	return &Computer{
-		Name:           SanitiseTFInput(d, "name"),
-		DN:             SanitiseTFInput(d, "dn"),
-		Description:    SanitiseTFInput(d, "description"),
-		GUID:           SanitiseTFInput(d, "guid"),
-		SAMAccountName: SanitiseTFInput(d, "pre2kname"),
-		Path:           SanitiseTFInput(d, "container"),
+func NewComputerFromResource(d *schema.ResourceData) (*Computer, error) {
+	computer := &Computer{
+		Name:                 SanitiseTFInput(d, "name"),
+		DN:                   SanitiseTFInput(d, "dn"),
+		Description:          SanitiseTFInput(d, "description"),
+		GUID:                 SanitiseTFInput(d, "guid"),
+		SAMAccountName:       SanitiseTFInput(d, "pre2kname"),
+		Path:                 SanitiseTFInput(d, "container"),
+		Enabled:              d.Get("enabled").(bool),
+		DNSHostName:          SanitiseTFInput(d, "dns_host_name"),
+		Location:             SanitiseTFInput(d, "location"),
+		ManagedBy:            SanitiseTFInput(d, "managed_by"),
+		TrustedForDelegation: d.Get("trusted_for_delegation").(bool),
+		Protected:            d.Get("protected_from_accidental_deletion").(bool),
 	}
+
+	if spns, ok := d.GetOk("service_principal_names"); ok {
+		for _, spn := range spns.(*schema.Set).List() {
+			computer.ServicePrincipalNames = append(computer.ServicePrincipalNames, SanitiseString(spn.(string)))
+		}
+	}
+
+	customAttributes, err := getCustomAttributesFromResource(d)
+	if err != nil {
+		return nil, err
+	}
+	computer.CustomAttributes = customAttributes
+
+	return computer, nil
 }
 
 // NewComputerFromHost return a new Machine struct populated from data we get
 // from the domain controller
-func NewComputerFromHost(conf *config.ProviderConf, identity string) (*Computer, error) {
+func NewComputerFromHost(conf *config.ProviderConf, identity string, customAttributes []string) (*Computer, error) {
 	cmd := fmt.Sprintf("Get-ADComputer -Identity %q -Properties *", identity)
 	conn, err := conf.AcquireWinRMClient()
 	if err != nil {
@@ -67,6 +103,11 @@ func NewComputerFromHost(conf *config.ProviderConf, identity string) (*Computer,
 	}
 	computer.Path = strings.TrimPrefix(computer.DN, fmt.Sprintf("CN=%s,", computer.Name))
 
+	computer.CustomAttributes, err = unmarshallCustomAttributes([]byte(result.Stdout), customAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("NewComputerFromHost: %s", err)
+	}
+
 	return computer, nil
 }
 
@@ -89,6 +130,28 @@ func (m *Computer) Create(conf *config.ProviderConf) (string, error) {
 		cmd = fmt.Sprintf("%s -Description %q", cmd, m.Description)
 	}
 
+	cmd = fmt.Sprintf("%s -Enabled $%t -TrustedForDelegation $%t", cmd, m.Enabled, m.TrustedForDelegation)
+
+	if m.DNSHostName != "" {
+		cmd = fmt.Sprintf("%s -DNSHostName %q", cmd, m.DNSHostName)
+	}
+
+	if m.Location != "" {
+		cmd = fmt.Sprintf("%s -Location %q", cmd, m.Location)
+	}
+
+	if m.ManagedBy != "" {
+		cmd = fmt.Sprintf("%s -ManagedBy %q", cmd, m.ManagedBy)
+	}
+
+	if len(m.ServicePrincipalNames) > 0 {
+		cmd = fmt.Sprintf("%s -ServicePrincipalNames @(%s)", cmd, getServicePrincipalNamesList(m.ServicePrincipalNames))
+	}
+
+	if m.CustomAttributes != nil {
+		cmd = fmt.Sprintf("%s -OtherAttributes %s", cmd, getOtherAttributes(m.CustomAttributes))
+	}
+
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      true,
 		ForceArray:      false,
@@ -112,15 +175,36 @@ func (m *Computer) Create(conf *config.ProviderConf) (string, error) {
 		return "", fmt.Errorf("Computer.Create: %s", err)
 	}
 
+	if m.Protected {
+		err = setProtectedFromAccidentalDeletion(conf, computer.GUID, true)
+		if err != nil {
+			return computer.GUID, err
+		}
+	}
+
 	return computer.GUID, nil
 }
 
-// Update updates an existing Computer objects in the AD tree
+// Update updates an existing Computer objects in the AD tree.
+// The custom_attributes change, if present, is expected to hold the old and the new JSON documents.
 func (m *Computer) Update(conf *config.ProviderConf, changes map[string]interface{}) error {
 	if m.GUID == "" {
 		return fmt.Errorf("cannot update computer object with name %q, guid is not set", m.Name)
 	}
 
+	// Protected objects cannot be moved, so we temporarily lift the protection. If the
+	// protected_from_accidental_deletion change is present the object was protected before
+	// or will be protected afterwards, either way it is safe to lift the protection.
+	_, protectedChanged := changes["protected_from_accidental_deletion"]
+	unprotected := false
+	if _, ok := changes["container"]; ok && (m.Protected || protectedChanged) {
+		err := setProtectedFromAccidentalDeletion(conf, m.GUID, false)
+		if err != nil {
+			return err
+		}
+		unprotected = true
+	}
+
 	if path, ok := changes["container"]; ok {
 		cmd := fmt.Sprintf("Move-AdObject -Identity %q -TargetPath %q", m.GUID, path.(string))
 		conn, err := conf.AcquireWinRMClient()
@@ -147,6 +231,13 @@ func (m *Computer) Update(conf *config.ProviderConf, changes map[string]interfac
 		}
 	}
 
+	if unprotected || protectedChanged {
+		err := setProtectedFromAccidentalDeletion(conf, m.GUID, m.Protected)
+		if err != nil {
+			return err
+		}
+	}
+
 	if description, ok := changes["description"]; ok {
 		if description == "" {
 			description = "$null"
@@ -178,11 +269,89 @@ func (m *Computer) Update(conf *config.ProviderConf, changes map[string]interfac
 		}
 	}
 
+	strKeyMap := map[string]string{
+		"dns_host_name": "DNSHostName",
+		"location":      "Location",
+		"managed_by":    "ManagedBy",
+	}
+	boolKeyMap := map[string]string{
+		"enabled":                "Enabled",
+		"trusted_for_delegation": "TrustedForDelegation",
+	}
+
+	cmds := []string{fmt.Sprintf("Set-ADComputer -Identity %q", m.GUID)}
+	for k, param := range strKeyMap {
+		if value, ok := changes[k]; ok {
+			if value.(string) == "" {
+				cmds = append(cmds, fmt.Sprintf("-%s $null", param))
+			} else {
+				cmds = append(cmds, fmt.Sprintf(`-%s "%s"`, param, SanitiseString(value.(string))))
+			}
+		}
+	}
+	for k, param := range boolKeyMap {
+		if value, ok := changes[k]; ok {
+			cmds = append(cmds, fmt.Sprintf("-%s $%t", param, value.(bool)))
+		}
+	}
+	if _, ok := changes["service_principal_names"]; ok {
+		cmds = append(cmds, getServicePrincipalNamesParam(m.ServicePrincipalNames))
+	}
+	if len(cmds) > 1 {
+		err := m.runSetADComputer(conf, cmds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ca, ok := changes["custom_attributes"]; ok {
+		values := ca.([]string)
+		caCmds, err := customAttributesChanges(values[0], values[1])
+		if err != nil {
+			return err
+		}
+		if len(caCmds) > 0 {
+			cmds = append([]string{fmt.Sprintf("Set-ADComputer -Identity %q", m.GUID)}, caCmds...)
+			err = m.runSetADComputer(conf, cmds)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Computer) runSetADComputer(conf *config.ProviderConf, cmds []string) error {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while modifying computer object: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("Set-ADComputer exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
 	return nil
 }
 
 // Delete deletes an existing Computer objects from the AD tree
 func (m *Computer) Delete(conf *config.ProviderConf) error {
+	if m.Protected {
+		err := setProtectedFromAccidentalDeletion(conf, m.GUID, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd := fmt.Sprintf("Remove-ADObject -Confirm:$false -Recursive -Identity %q", m.GUID)
 	conn, err := conf.AcquireWinRMClient()
 	if err != nil {
@@ -0,0 +1,112 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DefaultDomainPasswordPolicyKeys are the resource attributes that map to a Set-ADDefaultDomainPasswordPolicy parameter.
+// They use the same parameter names as the fine-grained password policy cmdlets.
+var DefaultDomainPasswordPolicyKeys = []string{
+	"complexity_enabled",
+	"min_password_length",
+	"password_history_count",
+	"min_password_age",
+	"max_password_age",
+	"lockout_threshold",
+	"lockout_duration",
+	"lockout_observation_window",
+	"reversible_encryption_enabled",
+}
+
+// DefaultDomainPasswordPolicy represents the password and lockout policy stored on the domain object
+type DefaultDomainPasswordPolicy struct {
+	DN                          string `json:"DistinguishedName"`
+	ComplexityEnabled           bool
+	MinPasswordLength           int
+	PasswordHistoryCount        int
+	MinPasswordAge              string
+	MaxPasswordAge              string
+	LockoutThreshold            int
+	LockoutDuration             string
+	LockoutObservationWindow    string
+	ReversibleEncryptionEnabled bool
+}
+
+// defaultDomainPasswordPolicySelect selects the properties of the default domain password policy
+const defaultDomainPasswordPolicySelect = "Select-Object DistinguishedName, ComplexityEnabled, MinPasswordLength, " +
+	"PasswordHistoryCount, LockoutThreshold, ReversibleEncryptionEnabled, " + passwordPolicyTimeSpanSelect
+
+// SetDefaultDomainPasswordPolicy applies the given attributes of the resource to the password policy of the domain
+func SetDefaultDomainPasswordPolicy(conf *config.ProviderConf, d *schema.ResourceData, domain string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cmds := []string{fmt.Sprintf("Set-ADDefaultDomainPasswordPolicy -Identity %q", domain)}
+	cmds = append(cmds, getPSOParams(d, keys)...)
+
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while setting the default domain password policy: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return fmt.Errorf("command Set-ADDefaultDomainPasswordPolicy exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+	return nil
+}
+
+// GetDefaultDomainPasswordPolicyFromHost returns the password policy stored on the domain object
+func GetDefaultDomainPasswordPolicyFromHost(conf *config.ProviderConf, domain string) (*DefaultDomainPasswordPolicy, error) {
+	cmd := fmt.Sprintf("Get-ADDefaultDomainPasswordPolicy -Identity %q | %s", domain, defaultDomainPasswordPolicySelect)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return nil, fmt.Errorf("command Get-ADDefaultDomainPasswordPolicy exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	return unmarshallDefaultDomainPasswordPolicy([]byte(result.Stdout))
+}
+
+func unmarshallDefaultDomainPasswordPolicy(input []byte) (*DefaultDomainPasswordPolicy, error) {
+	var policy DefaultDomainPasswordPolicy
+	err := json.Unmarshal(input, &policy)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling default domain password policy json document: %s", err)
+	}
+	if policy.DN == "" {
+		return nil, fmt.Errorf("invalid data while unmarshalling DefaultDomainPasswordPolicy data, json doc was: %s", string(input))
+	}
+	return &policy, nil
+}
@@ -0,0 +1,23 @@
+package winrmhelper
+
+import (
+	"testing"
+)
+
+func TestUnmarshallDefaultDomainPasswordPolicy(t *testing.T) {
+	input := `{"DistinguishedName":"DC=contoso,DC=com","ComplexityEnabled":true,"MinPasswordLength":7,"PasswordHistoryCount":24,` +
+		`"LockoutThreshold":0,"ReversibleEncryptionEnabled":false,"MinPasswordAge":"1.00:00:00","MaxPasswordAge":"42.00:00:00",` +
+		`"LockoutDuration":"00:10:00","LockoutObservationWindow":"00:10:00"}`
+
+	policy, err := unmarshallDefaultDomainPasswordPolicy([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallDefaultDomainPasswordPolicy() unexpected error: %s", err)
+	}
+	if policy.DN != "DC=contoso,DC=com" || policy.MinPasswordLength != 7 || policy.PasswordHistoryCount != 24 || policy.LockoutDuration != "00:10:00" {
+		t.Errorf("unmarshallDefaultDomainPasswordPolicy() = %+v", policy)
+	}
+
+	if _, err := unmarshallDefaultDomainPasswordPolicy([]byte(`{}`)); err == nil {
+		t.Error("unmarshallDefaultDomainPasswordPolicy() expected an error for a document without DN")
+	}
+}
@@ -0,0 +1,110 @@
+package winrmhelper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// KerberosDelegationKeys lists the resource attributes handled by KerberosDelegation
+var KerberosDelegationKeys = []string{
+	"allowed_to_delegate_to",
+	"trusted_to_auth_for_delegation",
+	"principals_allowed_to_delegate_to_account",
+}
+
+// KerberosDelegation holds the constrained and resource-based constrained delegation settings of an account
+type KerberosDelegation struct {
+	// AllowedToDelegateTo holds the SPNs of the services the account can delegate to (msDS-AllowedToDelegateTo)
+	AllowedToDelegateTo []string
+	// TrustedToAuthForDelegation enables protocol transition (S4U2Self) for constrained delegation
+	TrustedToAuthForDelegation bool
+	// PrincipalsAllowedToDelegateToAccount holds the principals allowed to delegate to this account (resource-based constrained delegation)
+	PrincipalsAllowedToDelegateToAccount []string
+}
+
+// NewKerberosDelegationFromResource returns a KerberosDelegation struct populated from resource data
+func NewKerberosDelegationFromResource(d *schema.ResourceData) *KerberosDelegation {
+	k := &KerberosDelegation{
+		TrustedToAuthForDelegation: d.Get("trusted_to_auth_for_delegation").(bool),
+	}
+	if spns, ok := d.GetOk("allowed_to_delegate_to"); ok {
+		for _, spn := range spns.(*schema.Set).List() {
+			k.AllowedToDelegateTo = append(k.AllowedToDelegateTo, SanitiseString(spn.(string)))
+		}
+	}
+	if principals, ok := d.GetOk("principals_allowed_to_delegate_to_account"); ok {
+		for _, p := range principals.(*schema.Set).List() {
+			k.PrincipalsAllowedToDelegateToAccount = append(k.PrincipalsAllowedToDelegateToAccount, SanitiseString(p.(string)))
+		}
+	}
+	return k
+}
+
+// ConfiguredKeys returns the delegation attributes that hold a non default value. It's used
+// to find out what needs to be applied after an account was created.
+func (k *KerberosDelegation) ConfiguredKeys() []string {
+	keys := []string{}
+	if len(k.AllowedToDelegateTo) > 0 {
+		keys = append(keys, "allowed_to_delegate_to")
+	}
+	if k.TrustedToAuthForDelegation {
+		keys = append(keys, "trusted_to_auth_for_delegation")
+	}
+	if len(k.PrincipalsAllowedToDelegateToAccount) > 0 {
+		keys = append(keys, "principals_allowed_to_delegate_to_account")
+	}
+	return keys
+}
+
+// getCmds returns the commands needed to apply the given delegation attributes on an account.
+// cmdlet is the Set-AD* cmdlet matching the account type, such as Set-ADUser or Set-ADComputer.
+func (k *KerberosDelegation) getCmds(cmdlet, identity string, keys []string) []string {
+	cmds := []string{}
+	for _, key := range keys {
+		switch key {
+		case "allowed_to_delegate_to":
+			if len(k.AllowedToDelegateTo) == 0 {
+				cmds = append(cmds, fmt.Sprintf("Set-ADObject -Identity %q -Clear 'msDS-AllowedToDelegateTo'", identity))
+			} else {
+				cmds = append(cmds, fmt.Sprintf("Set-ADObject -Identity %q -Replace @{'msDS-AllowedToDelegateTo'=@(%s)}", identity, getServicePrincipalNamesList(k.AllowedToDelegateTo)))
+			}
+		case "trusted_to_auth_for_delegation":
+			cmds = append(cmds, fmt.Sprintf("Set-ADAccountControl -Identity %q -TrustedToAuthForDelegation $%t", identity, k.TrustedToAuthForDelegation))
+		case "principals_allowed_to_delegate_to_account":
+			if len(k.PrincipalsAllowedToDelegateToAccount) == 0 {
+				cmds = append(cmds, fmt.Sprintf("%s -Identity %q -PrincipalsAllowedToDelegateToAccount $null", cmdlet, identity))
+			} else {
+				cmds = append(cmds, fmt.Sprintf("%s -Identity %q -PrincipalsAllowedToDelegateToAccount %s", cmdlet, identity, getPrincipalsList(k.PrincipalsAllowedToDelegateToAccount)))
+			}
+		}
+	}
+	return cmds
+}
+
+// Apply applies the given delegation attributes on the account identified by identity
+func (k *KerberosDelegation) Apply(conf *config.ProviderConf, cmdlet, identity string, keys []string) error {
+	for _, cmd := range k.getCmds(cmdlet, identity, keys) {
+		psOpts := CreatePSCommandOpts{
+			JSONOutput:      false,
+			ForceArray:      false,
+			ExecLocally:     conf.IsConnectionTypeLocal(),
+			PassCredentials: conf.IsPassCredentialsEnabled(),
+			Username:        conf.Settings.WinRMUsername,
+			Password:        conf.Settings.WinRMPassword,
+			Server:          conf.IdentifyDomainController(),
+		}
+		psCmd := NewPSCommand([]string{cmd}, psOpts)
+		result, err := psCmd.Run(conf)
+		if err != nil {
+			return fmt.Errorf("winrm execution failure while configuring kerberos delegation: %s", err)
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("command %q exited with a non zero exit code (%d), stderr: %s", strings.Fields(cmd)[0], result.ExitCode, result.StdErr)
+		}
+	}
+	return nil
+}
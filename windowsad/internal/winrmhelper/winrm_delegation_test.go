@@ -0,0 +1,147 @@
+package winrmhelper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestKerberosDelegation_ConfiguredKeys(t *testing.T) {
+	k := KerberosDelegation{}
+	if keys := k.ConfiguredKeys(); len(keys) != 0 {
+		t.Errorf("ConfiguredKeys() = %v, expected no keys for default settings", keys)
+	}
+
+	k = KerberosDelegation{
+		AllowedToDelegateTo:                  []string{"MSSQLSvc/sql01.example.com:1433"},
+		TrustedToAuthForDelegation:           true,
+		PrincipalsAllowedToDelegateToAccount: []string{"CN=WEB01,OU=Servers,DC=example,DC=com"},
+	}
+	if keys := k.ConfiguredKeys(); !reflect.DeepEqual(keys, KerberosDelegationKeys) {
+		t.Errorf("ConfiguredKeys() = %v, want %v", keys, KerberosDelegationKeys)
+	}
+}
+
+func TestKerberosDelegation_GetCmds(t *testing.T) {
+	identity := "11111111-1111-1111-1111-111111111111"
+	tests := []struct {
+		name     string
+		k        KerberosDelegation
+		keys     []string
+		expected []string
+	}{
+		{
+			name: "constrained delegation with protocol transition",
+			k: KerberosDelegation{
+				AllowedToDelegateTo:        []string{"MSSQLSvc/sql01.example.com:1433", "MSSQLSvc/sql01.example.com"},
+				TrustedToAuthForDelegation: true,
+			},
+			keys: []string{"allowed_to_delegate_to", "trusted_to_auth_for_delegation"},
+			expected: []string{
+				`Set-ADObject -Identity "11111111-1111-1111-1111-111111111111" -Replace @{'msDS-AllowedToDelegateTo'=@("MSSQLSvc/sql01.example.com:1433","MSSQLSvc/sql01.example.com")}`,
+				`Set-ADAccountControl -Identity "11111111-1111-1111-1111-111111111111" -TrustedToAuthForDelegation $true`,
+			},
+		},
+		{
+			name: "clear settings",
+			k:    KerberosDelegation{},
+			keys: KerberosDelegationKeys,
+			expected: []string{
+				`Set-ADObject -Identity "11111111-1111-1111-1111-111111111111" -Clear 'msDS-AllowedToDelegateTo'`,
+				`Set-ADAccountControl -Identity "11111111-1111-1111-1111-111111111111" -TrustedToAuthForDelegation $false`,
+				`Set-ADComputer -Identity "11111111-1111-1111-1111-111111111111" -PrincipalsAllowedToDelegateToAccount $null`,
+			},
+		},
+		{
+			name: "resource-based constrained delegation",
+			k: KerberosDelegation{
+				PrincipalsAllowedToDelegateToAccount: []string{"CN=WEB01,OU=Servers,DC=example,DC=com"},
+			},
+			keys: []string{"principals_allowed_to_delegate_to_account"},
+			expected: []string{
+				`Set-ADComputer -Identity "11111111-1111-1111-1111-111111111111" -PrincipalsAllowedToDelegateToAccount @("CN=WEB01,OU=Servers,DC=example,DC=com")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := tt.k.getCmds("Set-ADComputer", identity, tt.keys)
+			if !reflect.DeepEqual(cmds, tt.expected) {
+				t.Errorf("getCmds() = %v, want %v", cmds, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKerberosDelegation_ClearFromResource(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"allowed_to_delegate_to":                    {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		"trusted_to_auth_for_delegation":            {Type: schema.TypeBool, Optional: true, Computed: true},
+		"principals_allowed_to_delegate_to_account": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+	}
+	populated := schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"allowed_to_delegate_to":                    []interface{}{"MSSQLSvc/sql01.example.com:1433"},
+		"trusted_to_auth_for_delegation":            true,
+		"principals_allowed_to_delegate_to_account": []interface{}{"CN=WEB01,OU=Servers,DC=example,DC=com"},
+	})
+	populated.SetId("11111111-1111-1111-1111-111111111111")
+	state := populated.State()
+
+	// Going from populated sets to empty ones must clear the attributes on the account
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"allowed_to_delegate_to":                    []interface{}{},
+		"trusted_to_auth_for_delegation":            true,
+		"principals_allowed_to_delegate_to_account": []interface{}{},
+	})
+	diff, err := schema.InternalMap(s).Diff(context.Background(), state, config, nil, nil, true)
+	if err != nil {
+		t.Fatalf("Diff() unexpected error: %s", err)
+	}
+	d, err := schema.InternalMap(s).Data(state, diff)
+	if err != nil {
+		t.Fatalf("Data() unexpected error: %s", err)
+	}
+
+	changedKeys := []string{}
+	for _, key := range KerberosDelegationKeys {
+		if d.HasChange(key) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	expected := []string{
+		`Set-ADObject -Identity "11111111-1111-1111-1111-111111111111" -Clear 'msDS-AllowedToDelegateTo'`,
+		`Set-ADUser -Identity "11111111-1111-1111-1111-111111111111" -PrincipalsAllowedToDelegateToAccount $null`,
+	}
+	cmds := NewKerberosDelegationFromResource(d).getCmds("Set-ADUser", d.Id(), changedKeys)
+	if !reflect.DeepEqual(cmds, expected) {
+		t.Errorf("getCmds() = %v, want %v", cmds, expected)
+	}
+}
+
+func TestComputer_JSONUnmarshal_Delegation(t *testing.T) {
+	input := `{
+		"ObjectGuid": "11111111-1111-1111-1111-111111111111",
+		"Name": "SQL01",
+		"msDS-AllowedToDelegateTo": ["MSSQLSvc/sql01.example.com:1433"],
+		"TrustedToAuthForDelegation": true,
+		"PrincipalsAllowedToDelegateToAccount": ["CN=WEB01,OU=Servers,DC=example,DC=com"]
+	}`
+
+	computer, err := unmarshallComputer([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallComputer() unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(computer.AllowedToDelegateTo, []string{"MSSQLSvc/sql01.example.com:1433"}) {
+		t.Errorf("AllowedToDelegateTo = %v", computer.AllowedToDelegateTo)
+	}
+	if !computer.TrustedToAuthForDelegation {
+		t.Errorf("TrustedToAuthForDelegation = false, want true")
+	}
+	if !reflect.DeepEqual(computer.PrincipalsAllowedToDelegateToAccount, []string{"CN=WEB01,OU=Servers,DC=example,DC=com"}) {
+		t.Errorf("PrincipalsAllowedToDelegateToAccount = %v", computer.PrincipalsAllowedToDelegateToAccount)
+	}
+}
@@ -0,0 +1,151 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+)
+
+// DeletedObject represents an object in the Deleted Objects container that can still be restored
+// from the AD Recycle Bin
+type DeletedObject struct {
+	GUID              string `json:"ObjectGUID"`
+	Name              string `json:"Name"`
+	DistinguishedName string `json:"DistinguishedName"`
+	ObjectClass       string `json:"ObjectClass"`
+	SAMAccountName    string `json:"SamAccountName"`
+	SID               string `json:"SID"`
+	LastKnownParent   string `json:"LastKnownParent"`
+	DeletedAt         string `json:"DeletedAt"`
+}
+
+// DeletedObjectFilter limits the deleted objects that are returned. Empty fields are ignored.
+type DeletedObjectFilter struct {
+	ObjectClass    string
+	SAMAccountName string
+	Name           string
+}
+
+// deletedObjectSelect formats the properties of deleted objects. Name is the name the object had
+// before it was deleted, the actual name of a deleted object is mangled with its GUID.
+const deletedObjectSelect = "Select-Object @{Name='ObjectGUID';Expression={$_.ObjectGUID.ToString()}}, " +
+	"@{Name='Name';Expression={$_.'msDS-LastKnownRDN'}}, DistinguishedName, ObjectClass, " +
+	"@{Name='SamAccountName';Expression={$_.sAMAccountName}}, " +
+	"@{Name='SID';Expression={if ($_.objectSid) { $_.objectSid.Value } else { '' }}}, " +
+	"@{Name='LastKnownParent';Expression={$_.lastKnownParent}}, " +
+	"@{Name='DeletedAt';Expression={$_.whenChanged.ToUniversalTime().ToString(\"yyyy-MM-dd'T'HH:mm:ss'Z'\")}}"
+
+// getDeletedObjectLDAPFilter returns an LDAP filter matching the deleted objects that can be restored.
+// Recycled objects have lost their attributes and can't be restored anymore.
+func getDeletedObjectLDAPFilter(filter DeletedObjectFilter) string {
+	clauses := []string{"(isDeleted=TRUE)", "(!(isRecycled=TRUE))"}
+	if filter.ObjectClass != "" {
+		clauses = append(clauses, fmt.Sprintf("(objectClass=%s)", escapeLDAPFilterValue(filter.ObjectClass)))
+		// computers are users too as far as objectClass is concerned
+		if strings.EqualFold(filter.ObjectClass, "user") {
+			clauses = append(clauses, "(!(objectClass=computer))")
+		}
+	}
+	if filter.SAMAccountName != "" {
+		clauses = append(clauses, fmt.Sprintf("(sAMAccountName=%s)", escapeLDAPFilterValue(filter.SAMAccountName)))
+	}
+	if filter.Name != "" {
+		clauses = append(clauses, fmt.Sprintf("(msDS-LastKnownRDN=%s)", escapeLDAPFilterValue(filter.Name)))
+	}
+	return fmt.Sprintf("(&%s)", strings.Join(clauses, ""))
+}
+
+// GetDeletedObjects returns the deleted objects matching the filter, most recently deleted first
+func GetDeletedObjects(conf *config.ProviderConf, filter DeletedObjectFilter) ([]DeletedObject, error) {
+	ldapFilter := strings.ReplaceAll(getDeletedObjectLDAPFilter(filter), "'", "''")
+	cmd := fmt.Sprintf("Get-ADObject -IncludeDeletedObjects -LDAPFilter '%s' "+
+		"-Properties msDS-LastKnownRDN, lastKnownParent, sAMAccountName, objectSid, whenChanged | "+
+		"Sort-Object whenChanged -Descending | %s", ldapFilter, deletedObjectSelect)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      true,
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, fmt.Errorf("winrm execution failure while searching deleted objects: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("Get-ADObject exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	return unmarshallDeletedObjects([]byte(result.Stdout))
+}
+
+// RestoreDeletedObject restores a deleted object from the AD Recycle Bin. If targetPath or newName
+// are empty the object is restored to its last known parent or with its last known name.
+func RestoreDeletedObject(conf *config.ProviderConf, guid, targetPath, newName string) error {
+	cmd := fmt.Sprintf("Restore-ADObject -Identity %q", guid)
+	if targetPath != "" {
+		cmd = fmt.Sprintf("%s -TargetPath %q", cmd, targetPath)
+	}
+	if newName != "" {
+		cmd = fmt.Sprintf("%s -NewName %q", cmd, newName)
+	}
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while restoring deleted object %q: %s", guid, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("Restore-ADObject exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+	return nil
+}
+
+// RestoreMatchingDeletedObject looks for the most recently deleted object of the given class with the
+// given SAM account name and restores it to targetPath as newName. It returns the GUID of the restored
+// object, or an empty string if no matching deleted object was found.
+func RestoreMatchingDeletedObject(conf *config.ProviderConf, objectClass, samAccountName, targetPath, newName string) (string, error) {
+	objects, err := GetDeletedObjects(conf, DeletedObjectFilter{ObjectClass: objectClass, SAMAccountName: samAccountName})
+	if err != nil {
+		return "", err
+	}
+	if len(objects) == 0 {
+		return "", nil
+	}
+
+	deleted := objects[0]
+	log.Printf("[INFO] Restoring deleted %s %q (%s), deleted at %s", objectClass, samAccountName, deleted.GUID, deleted.DeletedAt)
+	err = RestoreDeletedObject(conf, deleted.GUID, targetPath, newName)
+	if err != nil {
+		return "", err
+	}
+	return deleted.GUID, nil
+}
+
+func unmarshallDeletedObjects(input []byte) ([]DeletedObject, error) {
+	objects := []DeletedObject{}
+	if strings.TrimSpace(string(input)) == "" {
+		return objects, nil
+	}
+	err := json.Unmarshal(input, &objects)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall deleted objects json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling deleted objects json document: %s", err)
+	}
+	return objects, nil
+}
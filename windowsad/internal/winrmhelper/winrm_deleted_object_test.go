@@ -0,0 +1,75 @@
+package winrmhelper
+
+import (
+	"testing"
+)
+
+func TestGetDeletedObjectLDAPFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   DeletedObjectFilter
+		expected string
+	}{
+		{
+			name:     "no filter",
+			filter:   DeletedObjectFilter{},
+			expected: "(&(isDeleted=TRUE)(!(isRecycled=TRUE)))",
+		},
+		{
+			name:     "users exclude computers",
+			filter:   DeletedObjectFilter{ObjectClass: "user", SAMAccountName: "jdoe"},
+			expected: "(&(isDeleted=TRUE)(!(isRecycled=TRUE))(objectClass=user)(!(objectClass=computer))(sAMAccountName=jdoe))",
+		},
+		{
+			name:     "computer",
+			filter:   DeletedObjectFilter{ObjectClass: "computer", SAMAccountName: "PC01$"},
+			expected: "(&(isDeleted=TRUE)(!(isRecycled=TRUE))(objectClass=computer)(sAMAccountName=PC01$))",
+		},
+		{
+			name:     "escaped name",
+			filter:   DeletedObjectFilter{Name: "Sales (EMEA)*"},
+			expected: `(&(isDeleted=TRUE)(!(isRecycled=TRUE))(msDS-LastKnownRDN=Sales \28EMEA\29\2a))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDeletedObjectLDAPFilter(tt.filter); got != tt.expected {
+				t.Errorf("getDeletedObjectLDAPFilter() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmarshallDeletedObjects(t *testing.T) {
+	input := `[{
+		"ObjectGUID": "c1b2a3d4-0000-1111-2222-333344445555",
+		"Name": "jdoe",
+		"DistinguishedName": "CN=jdoe\\0ADEL:c1b2a3d4-0000-1111-2222-333344445555,CN=Deleted Objects,DC=contoso,DC=com",
+		"ObjectClass": "user",
+		"SamAccountName": "jdoe",
+		"SID": "S-1-5-21-1-2-3-1105",
+		"LastKnownParent": "OU=Users,DC=contoso,DC=com",
+		"DeletedAt": "2024-05-01T10:00:00Z"
+	}]`
+
+	objects, err := unmarshallDeletedObjects([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallDeletedObjects error: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 deleted object, got %d", len(objects))
+	}
+	o := objects[0]
+	if o.Name != "jdoe" || o.SID != "S-1-5-21-1-2-3-1105" || o.LastKnownParent != "OU=Users,DC=contoso,DC=com" || o.DeletedAt != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected deleted object %+v", o)
+	}
+
+	objects, err = unmarshallDeletedObjects([]byte("  "))
+	if err != nil {
+		t.Fatalf("unmarshallDeletedObjects error: %v", err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("expected no deleted objects, got %d", len(objects))
+	}
+}
@@ -2,7 +2,6 @@ package winrmhelper
 
 import (
 	"bytes"
-	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -361,26 +360,35 @@ func getSysVolPath(conf *config.ProviderConf) (string, error) {
 	return result.Stdout, nil
 }
 
-// GetGPOVersions returns the GPO versions for user and machine
+// splitGPOVersion returns the user and computer versions packed in the version number of a GPO.
+// The computer version is stored in the low 16 bits and the user version in the high 16 bits.
+// (https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpol/)
+func splitGPOVersion(version uint32) (userVersion, computerVersion uint16) {
+	return uint16(version >> 16), uint16(version & 0xFFFF)
+}
+
+// joinGPOVersion returns the version number of a GPO holding the given user and computer versions
+func joinGPOVersion(userVersion, computerVersion uint16) uint32 {
+	return uint32(userVersion)<<16 | uint32(computerVersion)
+}
+
+// loadGPOVersions loads the GPO versions for user and machine from gpt.ini
 func (g *GPO) loadGPOVersions() error {
 	gpoVersionString, err := g.gptIni.Section("General").GetKey("Version")
 	if err != nil {
 		return fmt.Errorf("error while reading version for GPO: %q", g.ID)
 	}
-	gpoVersion, err := strconv.ParseInt(gpoVersionString.String(), 10, 32)
+	gpoVersion, err := strconv.ParseUint(gpoVersionString.String(), 10, 32)
 	if err != nil {
 		return fmt.Errorf("failed to convert gpo version %s to uint32: %s", gpoVersionString, err)
 	}
-	buf := make([]byte, 4)
-	binary.LittleEndian.PutUint32(buf, uint32(gpoVersion))
-	g.userVersion = binary.LittleEndian.Uint16(buf[:2])
-	g.computerVersion = binary.LittleEndian.Uint16(buf[2:])
+	g.userVersion, g.computerVersion = splitGPOVersion(uint32(gpoVersion))
 	return nil
 }
 
-// SetADGPOVersions updates AD with the given versions for a GPO
-func (g *GPO) SetADGPOVersions(conf *config.ProviderConf, gpoVersion uint32) error {
-
+// getADGPOVersion returns the version number of the GPO stored in AD
+func (g *GPO) getADGPOVersion(conf *config.ProviderConf) (uint32, error) {
+	cmd := fmt.Sprintf("(Get-ADObject -LDAPFilter '(&(objectClass=groupPolicyContainer)(cn={%s}))' -Properties versionNumber).versionNumber", g.ID)
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
 		ForceArray:      false,
@@ -389,27 +397,48 @@ func (g *GPO) SetADGPOVersions(conf *config.ProviderConf, gpoVersion uint32) err
 		Username:        conf.Settings.WinRMUsername,
 		Password:        conf.Settings.WinRMPassword,
 		Server:          conf.IdentifyDomainController(),
-		SkipCredPrefix:  true,
 	}
-
-	tmpCmd := fmt.Sprintf("Get-ADObject  -LDAPFilter '(&(objectClass=groupPolicyContainer)(cn={%s}))' -Properties *", g.ID)
-	cmds := []string{
-		fmt.Sprintf("$o=(%s)", NewPSCommand([]string{tmpCmd}, psOpts).String()),
-		NewPSCommand([]string{fmt.Sprintf("$o.VersionNumber=%d;Set-AdObject -Instance $o", gpoVersion)}, psOpts).String(),
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return 0, fmt.Errorf("error while getting the version of GPO %q in AD: %s", g.ID, err)
+	}
+	if result.ExitCode != 0 {
+		return 0, fmt.Errorf("command to get the version of GPO %q in AD failed, stderr: %s, stdout: %s", g.ID, result.StdErr, result.Stdout)
+	}
+	out := strings.TrimSpace(result.Stdout)
+	if out == "" {
+		return 0, nil
+	}
+	version, err := strconv.ParseUint(out, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert AD version %q of GPO %q to uint32: %s", out, g.ID, err)
 	}
+	return uint32(version), nil
+}
 
-	cmd := strings.Join(cmds, ";")
-	psOpts = CreatePSCommandOpts{
+// errGPOVersionConflict is returned when the version of a GPO changed since it was read
+var errGPOVersionConflict = fmt.Errorf("GPO version changed concurrently")
+
+// getSetADGPOVersionCmd returns the command setting the version of a GPO in AD, unless it changed
+// from the expected value, in which case "conflict" is printed.
+func getSetADGPOVersionCmd(dn string, expected, gpoVersion uint32) string {
+	return fmt.Sprintf(`$o=Get-ADObject -Identity "%s" -Properties versionNumber; if ([uint32]$o.versionNumber -ne %d) { Write-Output "conflict" } else { $o.versionNumber=%d; Set-ADObject -Instance $o }`, dn, expected, gpoVersion)
+}
+
+// SetADGPOVersions updates AD with the given versions for a GPO. errGPOVersionConflict is returned
+// if the version found in AD isn't the expected one anymore.
+func (g *GPO) SetADGPOVersions(conf *config.ProviderConf, expected, gpoVersion uint32) error {
+	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
 		ForceArray:      false,
 		ExecLocally:     conf.IsConnectionTypeLocal(),
 		PassCredentials: conf.IsPassCredentialsEnabled(),
 		Username:        conf.Settings.WinRMUsername,
 		Password:        conf.Settings.WinRMPassword,
-		Server:          "",
-		SkipCredSuffix:  true,
+		Server:          conf.IdentifyDomainController(),
 	}
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	psCmd := NewPSCommand([]string{getSetADGPOVersionCmd(g.DN, expected, gpoVersion)}, psOpts)
 	result, err := psCmd.Run(conf)
 	if err != nil {
 		return fmt.Errorf("error while setting new version in AD for GPO %q: %s", g.ID, err)
@@ -417,6 +446,9 @@ func (g *GPO) SetADGPOVersions(conf *config.ProviderConf, gpoVersion uint32) err
 	if result.ExitCode != 0 {
 		return fmt.Errorf("command to set the version of GPO %q in AD failed, stderr: %s, stdout: %s", g.ID, result.StdErr, result.Stdout)
 	}
+	if strings.TrimSpace(result.Stdout) == "conflict" {
+		return errGPOVersionConflict
+	}
 	return nil
 }
 
@@ -443,23 +475,79 @@ func (g *GPO) SetINIGPOVersions(conf *config.ProviderConf, cpConn *winrmcp.Winrm
 	return nil
 }
 
-// SetGPOVersions updates gpt.ini on the DC with the given values for user and computer version of a GPO.
-func (g *GPO) SetGPOVersions(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp, userVersion, computerVersion uint16) error {
-	outBuf := make([]byte, 4)
-	binary.LittleEndian.PutUint16(outBuf[:2], computerVersion)
-	binary.LittleEndian.PutUint16(outBuf[2:], userVersion)
-	newVersion := binary.LittleEndian.Uint32(outBuf)
+// gpoVersionAttempts is the number of times a version update is attempted when the GPO is being
+// modified concurrently
+const gpoVersionAttempts = 5
+
+// IncrementGPOVersions increments the user and computer versions of a GPO in gpt.ini and AD, so
+// that clients apply the updated settings. The versions are read again right before they are
+// written: if another writer changes them in the meantime, the update is retried on top of the
+// new values.
+func (g *GPO) IncrementGPOVersions(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp, userIncrement, computerIncrement uint16) error {
+	for attempt := 1; attempt <= gpoVersionAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt-1) * time.Second)
+		}
 
-	err := g.SetINIGPOVersions(conf, cpConn, newVersion)
-	if err != nil {
-		return err
-	}
+		err := g.loadGPTIni(conf)
+		if err != nil {
+			return err
+		}
+		err = g.loadGPOVersions()
+		if err != nil {
+			return err
+		}
+		adVersion, err := g.getADGPOVersion(conf)
+		if err != nil {
+			return err
+		}
 
-	err = g.SetADGPOVersions(conf, newVersion)
-	if err != nil {
-		return err
+		// gpt.ini and AD should hold the same version, use the highest one if they don't
+		userVersion, computerVersion := g.userVersion, g.computerVersion
+		adUserVersion, adComputerVersion := splitGPOVersion(adVersion)
+		if adUserVersion > userVersion {
+			userVersion = adUserVersion
+		}
+		if adComputerVersion > computerVersion {
+			computerVersion = adComputerVersion
+		}
+		iniVersion := joinGPOVersion(g.userVersion, g.computerVersion)
+		newVersion := joinGPOVersion(userVersion+userIncrement, computerVersion+computerIncrement)
+
+		err = g.SetINIGPOVersions(conf, cpConn, newVersion)
+		if err != nil {
+			return err
+		}
+		err = g.SetADGPOVersions(conf, adVersion, newVersion)
+		if err == errGPOVersionConflict {
+			log.Printf("[DEBUG] version of GPO %q changed in AD while updating it (attempt %d)", g.ID, attempt)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// make sure nobody wrote gpt.ini between our read and our write
+		err = g.loadGPTIni(conf)
+		if err != nil {
+			return err
+		}
+		err = g.loadGPOVersions()
+		if err != nil {
+			return err
+		}
+		if current := joinGPOVersion(g.userVersion, g.computerVersion); current != newVersion {
+			log.Printf("[DEBUG] version of GPO %q in gpt.ini is %d instead of %d, it was %d (attempt %d)", g.ID, current, newVersion, iniVersion, attempt)
+			continue
+		}
+		return nil
 	}
-	return nil
+	return fmt.Errorf("failed to update the version of GPO %q after %d attempts, it is being modified concurrently", g.ID, gpoVersionAttempts)
+}
+
+// IncrementComputerVersion increments the computer version of a GPO, see IncrementGPOVersions.
+func (g *GPO) IncrementComputerVersion(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp) error {
+	return g.IncrementGPOVersions(conf, cpConn, 0, 1)
 }
 
 func (g *GPO) loadGPTIni(conf *config.ProviderConf) error {
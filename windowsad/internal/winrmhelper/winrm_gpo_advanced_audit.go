@@ -0,0 +1,45 @@
+package winrmhelper
+
+import (
+	"bytes"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/packer-community/winrmcp/winrmcp"
+)
+
+// AdvancedAuditExtensionNames are the client-side extension and tool extension GUIDs of the
+// advanced audit policy configuration of the computer configuration
+const AdvancedAuditExtensionNames = "[{F3CCC681-B74C-4060-9F26-CD84525DCA2A}{0F3F3735-573D-9804-99E4-AB2A69BA5FD4}]"
+
+func getAuditCSVPath(gpo *GPO) string {
+	return gpo.configurationPath(ComputerConfiguration, `Microsoft\Windows NT\Audit\audit.csv`)
+}
+
+// GetAuditCSVContents returns the contents of the GPO's audit.csv file. The second return value is
+// false if the file does not exist.
+func GetAuditCSVContents(conf *config.ProviderConf, gpo *GPO) ([]byte, bool, error) {
+	return GetSYSVOLFileContents(conf, getAuditCSVPath(gpo))
+}
+
+// UploadAuditCSV uploads the audit.csv file of a GPO and updates the GPO's gpt.ini by incrementing
+// the computer version by 1.
+func UploadAuditCSV(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, contents []byte) error {
+	err := UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(contents), getAuditCSVPath(gpo))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementComputerVersion(conf, cpClient)
+}
+
+// RemoveAuditCSV removes the audit.csv file of a GPO and updates the GPO's gpt.ini by incrementing
+// the computer version by 1.
+func RemoveAuditCSV(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO) error {
+	err := RemoveSYSVOLFile(conf, getAuditCSVPath(gpo))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementComputerVersion(conf, cpClient)
+}
@@ -0,0 +1,11 @@
+package winrmhelper
+
+import "testing"
+
+func TestGetAuditCSVPath(t *testing.T) {
+	gpo := &GPO{basePath: `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}`}
+	expected := `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}\Machine\Microsoft\Windows NT\Audit\audit.csv`
+	if path := getAuditCSVPath(gpo); path != expected {
+		t.Errorf("getAuditCSVPath() = %q, want %q", path, expected)
+	}
+}
@@ -0,0 +1,56 @@
+package winrmhelper
+
+import (
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/packer-community/winrmcp/winrmcp"
+)
+
+// Configurations of a GPO. Computer settings are stored in the Machine folder of the GPO and user
+// settings in the User folder, each configuration having its own version and extension names.
+const (
+	ComputerConfiguration = "computer"
+	UserConfiguration     = "user"
+)
+
+// GPOConfigurations lists the configurations of a GPO
+var GPOConfigurations = []string{ComputerConfiguration, UserConfiguration}
+
+// configurationPath returns the path of a file of the given configuration of the GPO, relPath
+// being relative to the Machine or User folder
+func (g *GPO) configurationPath(configuration, relPath string) string {
+	dir := "Machine"
+	if configuration == UserConfiguration {
+		dir = "User"
+	}
+	return fmt.Sprintf("%s\\%s\\%s", g.basePath, dir, relPath)
+}
+
+// IncrementConfigurationVersion increments the version of the given configuration of a GPO, see
+// IncrementGPOVersions.
+func (g *GPO) IncrementConfigurationVersion(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp, configuration string) error {
+	if configuration == UserConfiguration {
+		return g.IncrementGPOVersions(conf, cpConn, 1, 0)
+	}
+	return g.IncrementComputerVersion(conf, cpConn)
+}
+
+// AddExtensionNames merges the given [{CSE}{tool}] entries into the extension names of the given
+// configuration of a GPO: gPCMachineExtensionNames or gPCUserExtensionNames.
+func AddExtensionNames(conf *config.ProviderConf, gpoDN, configuration, value string) error {
+	if configuration == UserConfiguration {
+		return AddUserExtensionNames(conf, gpoDN, value)
+	}
+	return AddMachineExtensionNames(conf, gpoDN, value)
+}
+
+// RemoveExtensionNames removes the given [{CSE}{tool}] entries from the extension names of the
+// given configuration of a GPO: gPCMachineExtensionNames or gPCUserExtensionNames.
+func RemoveExtensionNames(conf *config.ProviderConf, gpoDN, configuration, value string) error {
+	if configuration == UserConfiguration {
+		return RemoveUserExtensionNames(conf, gpoDN, value)
+	}
+	return RemoveMachineExtensionNames(conf, gpoDN, value)
+}
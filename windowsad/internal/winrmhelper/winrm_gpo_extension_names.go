@@ -0,0 +1,260 @@
+package winrmhelper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+)
+
+// Attributes of a GPO listing the client-side extensions (CSE) and tool extensions of the computer
+// and user configurations
+const (
+	MachineExtensionNamesAttribute = "gPCMachineExtensionNames"
+	UserExtensionNamesAttribute    = "gPCUserExtensionNames"
+)
+
+// SecuritySettingsExtensionNames are the client-side extension and tool extension GUIDs of the
+// security settings of the computer configuration. They are defined here:
+// https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpsb/55bb803e-b35f-4ce8-b558-4c1e92ad77a4
+const SecuritySettingsExtensionNames = "[{827D319E-6EAC-11D2-A4EA-00C04F79F83A}{803E14A0-B4FB-11D0-A0D0-00A0C90F574B}]"
+
+// extensionName is an entry of an extension names attribute: a client-side extension and the
+// tool extensions that configure it
+type extensionName struct {
+	cse   string
+	tools []string
+}
+
+// parseExtensionNames parses an extension names attribute value made of [{CSE}{tool}...] entries
+func parseExtensionNames(value string) ([]extensionName, error) {
+	out := []extensionName{}
+	value = strings.TrimSpace(value)
+	for value != "" {
+		if value[0] != '[' {
+			return nil, fmt.Errorf("invalid extension names %q: expected '['", value)
+		}
+		end := strings.Index(value, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid extension names %q: missing ']'", value)
+		}
+		guids, err := parseGUIDList(value[1:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(guids) == 0 {
+			return nil, fmt.Errorf("invalid extension names %q: empty entry", value)
+		}
+		out = append(out, extensionName{cse: guids[0], tools: guids[1:]})
+		value = strings.TrimSpace(value[end+1:])
+	}
+	return out, nil
+}
+
+// parseGUIDList parses a list of GUIDs between braces, e.g. {GUID1}{GUID2}
+func parseGUIDList(value string) ([]string, error) {
+	out := []string{}
+	for value != "" {
+		if value[0] != '{' {
+			return nil, fmt.Errorf("invalid GUID list %q: expected '{'", value)
+		}
+		end := strings.Index(value, "}")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid GUID list %q: missing '}'", value)
+		}
+		out = append(out, value[:end+1])
+		value = value[end+1:]
+	}
+	return out, nil
+}
+
+// formatExtensionNames returns the attribute value of the given entries, sorted the way GPMC
+// writes them: by client-side extension GUID, with the tool extension GUIDs of each entry sorted.
+func formatExtensionNames(names []extensionName) string {
+	sort.SliceStable(names, func(i, j int) bool {
+		return strings.ToUpper(names[i].cse) < strings.ToUpper(names[j].cse)
+	})
+	var sb strings.Builder
+	for _, n := range names {
+		sort.SliceStable(n.tools, func(i, j int) bool {
+			return strings.ToUpper(n.tools[i]) < strings.ToUpper(n.tools[j])
+		})
+		sb.WriteString("[")
+		sb.WriteString(n.cse)
+		sb.WriteString(strings.Join(n.tools, ""))
+		sb.WriteString("]")
+	}
+	return sb.String()
+}
+
+func containsGUID(guids []string, guid string) bool {
+	for _, g := range guids {
+		if strings.EqualFold(g, guid) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeExtensionNames adds the entries of value to the current attribute value. Tool extensions of
+// a client-side extension that is already listed are merged into its entry.
+func mergeExtensionNames(current, value string) (string, error) {
+	names, err := parseExtensionNames(current)
+	if err != nil {
+		return "", err
+	}
+	added, err := parseExtensionNames(value)
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range added {
+		found := false
+		for idx := range names {
+			if !strings.EqualFold(names[idx].cse, a.cse) {
+				continue
+			}
+			found = true
+			for _, tool := range a.tools {
+				if !containsGUID(names[idx].tools, tool) {
+					names[idx].tools = append(names[idx].tools, tool)
+				}
+			}
+		}
+		if !found {
+			names = append(names, a)
+		}
+	}
+	return formatExtensionNames(names), nil
+}
+
+// removeExtensionNames removes the tool extensions of value from the current attribute value.
+// Client-side extensions are removed once none of their tool extensions are left, so that the
+// entries owned by other settings of the GPO are kept.
+func removeExtensionNames(current, value string) (string, error) {
+	names, err := parseExtensionNames(current)
+	if err != nil {
+		return "", err
+	}
+	removed, err := parseExtensionNames(value)
+	if err != nil {
+		return "", err
+	}
+
+	out := []extensionName{}
+	for _, n := range names {
+		for _, r := range removed {
+			if !strings.EqualFold(n.cse, r.cse) {
+				continue
+			}
+			tools := []string{}
+			for _, tool := range n.tools {
+				if !containsGUID(r.tools, tool) {
+					tools = append(tools, tool)
+				}
+			}
+			n.tools = tools
+			if len(n.tools) == 0 {
+				n.cse = ""
+			}
+		}
+		if n.cse != "" {
+			out = append(out, n)
+		}
+	}
+	return formatExtensionNames(out), nil
+}
+
+func extensionNamesPSOpts(conf *config.ProviderConf) CreatePSCommandOpts {
+	return CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+}
+
+// getExtensionNames returns the value of an extension names attribute of a GPO
+func getExtensionNames(conf *config.ProviderConf, gpoDN, attribute string) (string, error) {
+	cmd := fmt.Sprintf(`(Get-ADObject -Identity "%s" -Properties %s).%s`, gpoDN, attribute, attribute)
+	psCmd := NewPSCommand([]string{cmd}, extensionNamesPSOpts(conf))
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("error while getting %s for GPO %q: %s", attribute, gpoDN, err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("command to get %s for GPO %q failed, stderr: %s, stdout: %s", attribute, gpoDN, result.StdErr, result.Stdout)
+	}
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// setExtensionNames replaces the value of an extension names attribute of a GPO. The attribute is
+// cleared if the value is empty.
+func setExtensionNames(conf *config.ProviderConf, gpoDN, attribute, value string) error {
+	cmd := fmt.Sprintf(`Set-ADObject -Identity "%s" -Replace @{%s="%s"}`, gpoDN, attribute, value)
+	if value == "" {
+		cmd = fmt.Sprintf(`Set-ADObject -Identity "%s" -Clear %s`, gpoDN, attribute)
+	}
+	psCmd := NewPSCommand([]string{cmd}, extensionNamesPSOpts(conf))
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("error while setting %s for GPO %q: %s", attribute, gpoDN, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("command to set %s for GPO %q failed, stderr: %s, stdout: %s", attribute, gpoDN, result.StdErr, result.Stdout)
+	}
+	return nil
+}
+
+// updateExtensionNames applies fn to the current value of an extension names attribute and
+// writes the result back if it changed
+func updateExtensionNames(conf *config.ProviderConf, gpoDN, attribute string, fn func(current string) (string, error)) error {
+	current, err := getExtensionNames(conf, gpoDN, attribute)
+	if err != nil {
+		return err
+	}
+	value, err := fn(current)
+	if err != nil {
+		return fmt.Errorf("error while updating %s for GPO %q: %s", attribute, gpoDN, err)
+	}
+	if value == current {
+		return nil
+	}
+	return setExtensionNames(conf, gpoDN, attribute, value)
+}
+
+// AddMachineExtensionNames merges the given [{CSE}{tool}] entries into the GPO's
+// gPCMachineExtensionNames attribute, keeping the extensions registered by other settings of the GPO.
+func AddMachineExtensionNames(conf *config.ProviderConf, gpoDN, value string) error {
+	return updateExtensionNames(conf, gpoDN, MachineExtensionNamesAttribute, func(current string) (string, error) {
+		return mergeExtensionNames(current, value)
+	})
+}
+
+// RemoveMachineExtensionNames removes the given [{CSE}{tool}] entries from the GPO's
+// gPCMachineExtensionNames attribute, keeping the extensions registered by other settings of the GPO.
+func RemoveMachineExtensionNames(conf *config.ProviderConf, gpoDN, value string) error {
+	return updateExtensionNames(conf, gpoDN, MachineExtensionNamesAttribute, func(current string) (string, error) {
+		return removeExtensionNames(current, value)
+	})
+}
+
+// AddUserExtensionNames merges the given [{CSE}{tool}] entries into the GPO's
+// gPCUserExtensionNames attribute, keeping the extensions registered by other settings of the GPO.
+func AddUserExtensionNames(conf *config.ProviderConf, gpoDN, value string) error {
+	return updateExtensionNames(conf, gpoDN, UserExtensionNamesAttribute, func(current string) (string, error) {
+		return mergeExtensionNames(current, value)
+	})
+}
+
+// RemoveUserExtensionNames removes the given [{CSE}{tool}] entries from the GPO's
+// gPCUserExtensionNames attribute, keeping the extensions registered by other settings of the GPO.
+func RemoveUserExtensionNames(conf *config.ProviderConf, gpoDN, value string) error {
+	return updateExtensionNames(conf, gpoDN, UserExtensionNamesAttribute, func(current string) (string, error) {
+		return removeExtensionNames(current, value)
+	})
+}
@@ -0,0 +1,110 @@
+package winrmhelper
+
+import "testing"
+
+const (
+	testSecurityCSE = "[{827D319E-6EAC-11D2-A4EA-00C04F79F83A}{803E14A0-B4FB-11D0-A0D0-00A0C90F574B}]"
+	testRegistryCSE = "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{D02B1F72-3407-48AE-BA88-E8213C6761F1}]"
+	testAuditCSE    = "[{F3CCC681-B74C-4060-9F26-CD84525DCA2A}{0F3F3735-573D-9804-99E4-AB2A69BA5FD4}]"
+)
+
+func TestMergeExtensionNames(t *testing.T) {
+	cases := []struct {
+		name     string
+		current  string
+		value    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			current:  "",
+			value:    testSecurityCSE,
+			expected: testSecurityCSE,
+		},
+		{
+			name:     "sorted by CSE",
+			current:  testSecurityCSE + testAuditCSE,
+			value:    testRegistryCSE,
+			expected: testRegistryCSE + testSecurityCSE + testAuditCSE,
+		},
+		{
+			name:     "already present with another case",
+			current:  "[{827d319e-6eac-11d2-a4ea-00c04f79f83a}{803e14a0-b4fb-11d0-a0d0-00a0c90f574b}]",
+			value:    testSecurityCSE,
+			expected: "[{827d319e-6eac-11d2-a4ea-00c04f79f83a}{803e14a0-b4fb-11d0-a0d0-00a0c90f574b}]",
+		},
+		{
+			name:     "tools merged into existing CSE",
+			current:  "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{0F6B957E-509E-11D1-A7CC-0000F87571E3}]",
+			value:    testRegistryCSE,
+			expected: "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{0F6B957E-509E-11D1-A7CC-0000F87571E3}{D02B1F72-3407-48AE-BA88-E8213C6761F1}]",
+		},
+	}
+	for _, tc := range cases {
+		got, err := mergeExtensionNames(tc.current, tc.value)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%s: mergeExtensionNames() = %q, want %q", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestRemoveExtensionNames(t *testing.T) {
+	cases := []struct {
+		name     string
+		current  string
+		value    string
+		expected string
+	}{
+		{
+			name:     "only entry",
+			current:  testSecurityCSE,
+			value:    testSecurityCSE,
+			expected: "",
+		},
+		{
+			name:     "other entries kept",
+			current:  testRegistryCSE + testSecurityCSE + testAuditCSE,
+			value:    testSecurityCSE,
+			expected: testRegistryCSE + testAuditCSE,
+		},
+		{
+			name:     "other tools of the CSE kept",
+			current:  "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{0F6B957E-509E-11D1-A7CC-0000F87571E3}{D02B1F72-3407-48AE-BA88-E8213C6761F1}]",
+			value:    testRegistryCSE,
+			expected: "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{0F6B957E-509E-11D1-A7CC-0000F87571E3}]",
+		},
+		{
+			name:     "not present",
+			current:  testAuditCSE,
+			value:    testSecurityCSE,
+			expected: testAuditCSE,
+		},
+	}
+	for _, tc := range cases {
+		got, err := removeExtensionNames(tc.current, tc.value)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("%s: removeExtensionNames() = %q, want %q", tc.name, got, tc.expected)
+		}
+	}
+}
+
+func TestParseExtensionNamesErrors(t *testing.T) {
+	for _, value := range []string{
+		"{827D319E-6EAC-11D2-A4EA-00C04F79F83A}",
+		"[{827D319E-6EAC-11D2-A4EA-00C04F79F83A}",
+		"[{827D319E-6EAC-11D2-A4EA-00C04F79F83A]",
+		"[]",
+	} {
+		if _, err := parseExtensionNames(value); err == nil {
+			t.Errorf("parseExtensionNames(%q): expected an error", value)
+		}
+	}
+}
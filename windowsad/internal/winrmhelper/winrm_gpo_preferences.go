@@ -0,0 +1,66 @@
+package winrmhelper
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/packer-community/winrmcp/winrmcp"
+)
+
+// Kinds of Group Policy Preferences, named after their folder and XML file
+const (
+	PreferenceGroups         = "Groups"
+	PreferenceDrives         = "Drives"
+	PreferenceScheduledTasks = "ScheduledTasks"
+	PreferenceFiles          = "Files"
+	PreferenceRegistry       = "Registry"
+)
+
+// preferenceExtensionNames are the client-side extension and tool extension GUIDs of each kind of
+// preferences, the same for the computer and user configurations
+var preferenceExtensionNames = map[string]string{
+	PreferenceGroups:         "[{17D89FEC-5C44-4972-B12D-241CAEF74509}{79F92669-4224-476C-9C5C-6EFB4D87DF4A}]",
+	PreferenceDrives:         "[{5794DAFD-BE60-433F-88A2-1A31939AC01F}{2EA1A81B-48E5-45E9-8BB7-A6E3AC170006}]",
+	PreferenceScheduledTasks: "[{AADCED64-746C-4633-A97C-D61349046527}{CAB54552-DEEA-4691-817E-ED4A4D1AFC72}]",
+	PreferenceFiles:          "[{7150F9BF-48AD-4DA4-A49C-29EF4A8369BA}{3BAE7E51-E3F4-41D0-853D-9BB9FD47605F}]",
+	PreferenceRegistry:       "[{B087BE9D-ED37-454F-AF9C-04291E351182}{BEE07A6A-EC9F-4659-B8C9-0B1937907C83}]",
+}
+
+// GetPreferenceExtensionNames returns the extension names of a kind of preferences
+func GetPreferenceExtensionNames(kind string) string {
+	return preferenceExtensionNames[kind]
+}
+
+func getPreferencePath(gpo *GPO, configuration, kind string) string {
+	return gpo.configurationPath(configuration, fmt.Sprintf(`Preferences\%s\%s.xml`, kind, kind))
+}
+
+// GetPreferenceContents returns the contents of the XML file of a kind of preferences of the given
+// configuration of the GPO. The second return value is false if the file does not exist.
+func GetPreferenceContents(conf *config.ProviderConf, gpo *GPO, configuration, kind string) ([]byte, bool, error) {
+	return GetSYSVOLFileContents(conf, getPreferencePath(gpo, configuration, kind))
+}
+
+// UploadPreference uploads the XML file of a kind of preferences of the given configuration of a
+// GPO and updates the GPO's gpt.ini by incrementing the version of the configuration by 1.
+func UploadPreference(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, configuration, kind string, contents []byte) error {
+	err := UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(contents), getPreferencePath(gpo, configuration, kind))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
+
+// RemovePreference removes the XML file of a kind of preferences of the given configuration of a
+// GPO and updates the GPO's gpt.ini by incrementing the version of the configuration by 1.
+func RemovePreference(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, configuration, kind string) error {
+	err := RemoveSYSVOLFile(conf, getPreferencePath(gpo, configuration, kind))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
@@ -0,0 +1,16 @@
+package winrmhelper
+
+import "testing"
+
+func TestGetPreferencePath(t *testing.T) {
+	gpo := &GPO{basePath: `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}`}
+	expected := `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}\User\Preferences\ScheduledTasks\ScheduledTasks.xml`
+	if path := getPreferencePath(gpo, UserConfiguration, PreferenceScheduledTasks); path != expected {
+		t.Errorf("getPreferencePath() = %q, want %q", path, expected)
+	}
+	for _, kind := range []string{PreferenceGroups, PreferenceDrives, PreferenceScheduledTasks, PreferenceFiles, PreferenceRegistry} {
+		if GetPreferenceExtensionNames(kind) == "" {
+			t.Errorf("GetPreferenceExtensionNames(%q) is empty", kind)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package winrmhelper
+
+import (
+	"bytes"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/packer-community/winrmcp/winrmcp"
+)
+
+// RegistryPolicyExtensionNames are the client-side extension and tool extension GUIDs of the
+// administrative templates (registry settings) of the computer configuration
+const RegistryPolicyExtensionNames = "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{D02B1F72-3407-48AE-BA88-E8213C6761F1}]"
+
+// RegistryPolicyUserExtensionNames are the client-side extension and tool extension GUIDs of the
+// administrative templates (registry settings) of the user configuration
+const RegistryPolicyUserExtensionNames = "[{35378EAC-683F-11D2-A89A-00C04FBBCFA2}{D02B1F73-3407-48AE-BA88-E8213C6761F1}]"
+
+// GetRegistryPolicyExtensionNames returns the extension names of the administrative templates of
+// the given configuration
+func GetRegistryPolicyExtensionNames(configuration string) string {
+	if configuration == UserConfiguration {
+		return RegistryPolicyUserExtensionNames
+	}
+	return RegistryPolicyExtensionNames
+}
+
+func getRegistryPolPath(gpo *GPO, configuration string) string {
+	return gpo.configurationPath(configuration, "Registry.pol")
+}
+
+// GetRegistryPolContents returns the contents of the Registry.pol file of the given configuration
+// of the GPO. The second return value is false if the file does not exist.
+func GetRegistryPolContents(conf *config.ProviderConf, gpo *GPO, configuration string) ([]byte, bool, error) {
+	return GetSYSVOLFileContents(conf, getRegistryPolPath(gpo, configuration))
+}
+
+// UploadRegistryPol uploads the Registry.pol file of the given configuration of a GPO and updates
+// the GPO's gpt.ini by incrementing the version of the configuration by 1.
+func UploadRegistryPol(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, configuration string, contents []byte) error {
+	err := UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(contents), getRegistryPolPath(gpo, configuration))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
+
+// RemoveRegistryPol removes the Registry.pol file of the given configuration of a GPO and updates
+// the GPO's gpt.ini by incrementing the version of the configuration by 1.
+func RemoveRegistryPol(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, configuration string) error {
+	err := RemoveSYSVOLFile(conf, getRegistryPolPath(gpo, configuration))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
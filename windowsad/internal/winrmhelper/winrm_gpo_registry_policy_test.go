@@ -0,0 +1,21 @@
+package winrmhelper
+
+import "testing"
+
+func TestGetSYSVOLFileBase64Cmd(t *testing.T) {
+	gpo := &GPO{basePath: `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}`}
+	cmd := getSYSVOLFileBase64Cmd(getRegistryPolPath(gpo, ComputerConfiguration))
+	expected := `if (Test-Path -LiteralPath "\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}\Machine\Registry.pol") ` +
+		`{ [Convert]::ToBase64String([IO.File]::ReadAllBytes("\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}\Machine\Registry.pol")) }`
+	if cmd != expected {
+		t.Errorf("getSYSVOLFileBase64Cmd() = %q, want %q", cmd, expected)
+	}
+}
+
+func TestGetRegistryPolPathUser(t *testing.T) {
+	gpo := &GPO{basePath: `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}`}
+	expected := `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}\User\Registry.pol`
+	if path := getRegistryPolPath(gpo, UserConfiguration); path != expected {
+		t.Errorf("getRegistryPolPath() = %q, want %q", path, expected)
+	}
+}
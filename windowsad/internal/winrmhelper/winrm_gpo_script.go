@@ -0,0 +1,178 @@
+package winrmhelper
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpscripts"
+
+	"github.com/packer-community/winrmcp/winrmcp"
+)
+
+// ScriptsExtensionNames are the client-side extension and tool extension GUIDs of the startup and
+// shutdown scripts of the computer configuration
+const ScriptsExtensionNames = "[{42B5FAAE-6536-11D2-AE5A-0000F87571E3}{40B6664F-4972-11D1-A7CA-0000F87571E3}]"
+
+// ScriptsUserExtensionNames are the client-side extension and tool extension GUIDs of the logon and
+// logoff scripts of the user configuration
+const ScriptsUserExtensionNames = "[{42B5FAAE-6536-11D2-AE5A-0000F87571E3}{40B66650-4972-11D1-A7CA-0000F87571E3}]"
+
+// GPOScriptTypes lists the types of scripts a GPO can run
+var GPOScriptTypes = []string{"startup", "shutdown", "logon", "logoff"}
+
+// scriptSections maps the script types to the name of their folder and scripts.ini section
+var scriptSections = map[string]string{
+	"startup":  "Startup",
+	"shutdown": "Shutdown",
+	"logon":    "Logon",
+	"logoff":   "Logoff",
+}
+
+// GetScriptConfiguration returns the configuration a script type belongs to: startup and shutdown
+// scripts run on computers, logon and logoff scripts for users.
+func GetScriptConfiguration(scriptType string) string {
+	if scriptType == "logon" || scriptType == "logoff" {
+		return UserConfiguration
+	}
+	return ComputerConfiguration
+}
+
+// GetScriptsExtensionNames returns the extension names of the scripts of the given configuration
+func GetScriptsExtensionNames(configuration string) string {
+	if configuration == UserConfiguration {
+		return ScriptsUserExtensionNames
+	}
+	return ScriptsExtensionNames
+}
+
+// isPowerShellScript returns true for the scripts listed in psscripts.ini instead of scripts.ini
+func isPowerShellScript(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".ps1")
+}
+
+func getScriptPath(gpo *GPO, scriptType, name string) string {
+	return gpo.configurationPath(GetScriptConfiguration(scriptType), fmt.Sprintf(`Scripts\%s\%s`, scriptSections[scriptType], name))
+}
+
+func getScriptsIniPath(gpo *GPO, configuration string, powerShell bool) string {
+	if powerShell {
+		return gpo.configurationPath(configuration, `Scripts\psscripts.ini`)
+	}
+	return gpo.configurationPath(configuration, `Scripts\scripts.ini`)
+}
+
+// GetScriptContents returns the contents of a script of the GPO. The second return value is false
+// if the file does not exist.
+func GetScriptContents(conf *config.ProviderConf, gpo *GPO, scriptType, name string) ([]byte, bool, error) {
+	return GetSYSVOLFileContents(conf, getScriptPath(gpo, scriptType, name))
+}
+
+// GetScriptsIni returns the scripts.ini file of the given configuration of a GPO, or its
+// psscripts.ini file if powerShell is true. A missing file is returned as a file without scripts.
+func GetScriptsIni(conf *config.ProviderConf, gpo *GPO, configuration string, powerShell bool) (*gpscripts.File, error) {
+	path := getScriptsIniPath(gpo, configuration, powerShell)
+	contents, _, err := GetSYSVOLFileContents(conf, path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := gpscripts.Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing %q: %s", path, err)
+	}
+	return f, nil
+}
+
+// GetScript returns the entry of a script in the scripts.ini or psscripts.ini file of the GPO and
+// its position in the section of its type. The position is -1 if the script is not listed.
+func GetScript(conf *config.ProviderConf, gpo *GPO, scriptType, name string) (gpscripts.Script, int, error) {
+	f, err := GetScriptsIni(conf, gpo, GetScriptConfiguration(scriptType), isPowerShellScript(name))
+	if err != nil {
+		return gpscripts.Script{}, -1, err
+	}
+	position := f.Find(scriptSections[scriptType], name)
+	if position < 0 {
+		return gpscripts.Script{}, -1, nil
+	}
+	return f.Scripts(scriptSections[scriptType])[position], position, nil
+}
+
+// UploadScript uploads a script to the folder of its type, unless contents is nil, and lists it with
+// its parameters in scripts.ini or psscripts.ini at the given position, see gpscripts.File.SetScript.
+// The GPO's gpt.ini is updated by incrementing the version of the script's configuration by 1.
+func UploadScript(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, scriptType, name string, contents []byte, parameters string, position int) error {
+	if contents != nil {
+		err := UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(contents), getScriptPath(gpo, scriptType, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	configuration := GetScriptConfiguration(scriptType)
+	powerShell := isPowerShellScript(name)
+	f, err := GetScriptsIni(conf, gpo, configuration, powerShell)
+	if err != nil {
+		return err
+	}
+	f.SetScript(scriptSections[scriptType], gpscripts.Script{CmdLine: name, Parameters: parameters}, position)
+	ini, err := f.Encode()
+	if err != nil {
+		return err
+	}
+	err = UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(ini), getScriptsIniPath(gpo, configuration, powerShell))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
+
+// RemoveScript removes a script from the folder of its type and from scripts.ini or psscripts.ini,
+// the ini file being removed once it is blank. The GPO's gpt.ini is updated by incrementing the
+// version of the script's configuration by 1.
+func RemoveScript(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, scriptType, name string) error {
+	configuration := GetScriptConfiguration(scriptType)
+	powerShell := isPowerShellScript(name)
+	f, err := GetScriptsIni(conf, gpo, configuration, powerShell)
+	if err != nil {
+		return err
+	}
+	if f.RemoveScript(scriptSections[scriptType], name) {
+		iniPath := getScriptsIniPath(gpo, configuration, powerShell)
+		if f.IsBlank() {
+			err = RemoveSYSVOLFile(conf, iniPath)
+		} else {
+			var ini []byte
+			ini, err = f.Encode()
+			if err == nil {
+				err = UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(ini), iniPath)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err = RemoveSYSVOLFile(conf, getScriptPath(gpo, scriptType, name))
+	if err != nil {
+		return err
+	}
+
+	return gpo.IncrementConfigurationVersion(conf, cpClient, configuration)
+}
+
+// HasScripts returns true if scripts.ini or psscripts.ini of the given configuration of a GPO still
+// list a script.
+func HasScripts(conf *config.ProviderConf, gpo *GPO, configuration string) (bool, error) {
+	for _, powerShell := range []bool{false, true} {
+		f, err := GetScriptsIni(conf, gpo, configuration, powerShell)
+		if err != nil {
+			return false, err
+		}
+		if !f.IsEmpty() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,26 @@
+package winrmhelper
+
+import "testing"
+
+func TestGetScriptPaths(t *testing.T) {
+	gpo := &GPO{basePath: `\\contoso.com\SYSVOL\contoso.com\Policies\{9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02}`}
+	for _, tc := range []struct {
+		scriptType string
+		name       string
+		script     string
+		ini        string
+	}{
+		{"startup", "setup.cmd", `Machine\Scripts\Startup\setup.cmd`, `Machine\Scripts\scripts.ini`},
+		{"shutdown", "Cleanup.PS1", `Machine\Scripts\Shutdown\Cleanup.PS1`, `Machine\Scripts\psscripts.ini`},
+		{"logon", "drives.ps1", `User\Scripts\Logon\drives.ps1`, `User\Scripts\psscripts.ini`},
+		{"logoff", "logoff.vbs", `User\Scripts\Logoff\logoff.vbs`, `User\Scripts\scripts.ini`},
+	} {
+		if path := getScriptPath(gpo, tc.scriptType, tc.name); path != gpo.basePath+`\`+tc.script {
+			t.Errorf("getScriptPath(%q, %q) = %q, want %q", tc.scriptType, tc.name, path, tc.script)
+		}
+		iniPath := getScriptsIniPath(gpo, GetScriptConfiguration(tc.scriptType), isPowerShellScript(tc.name))
+		if iniPath != gpo.basePath+`\`+tc.ini {
+			t.Errorf("ini path of %q %q = %q, want %q", tc.scriptType, tc.name, iniPath, tc.ini)
+		}
+	}
+}
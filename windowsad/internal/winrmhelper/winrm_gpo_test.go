@@ -0,0 +1,34 @@
+package winrmhelper
+
+import "testing"
+
+func TestGPOVersions(t *testing.T) {
+	cases := []struct {
+		version         uint32
+		userVersion     uint16
+		computerVersion uint16
+	}{
+		{version: 0, userVersion: 0, computerVersion: 0},
+		{version: 1, userVersion: 0, computerVersion: 1},
+		{version: 65536, userVersion: 1, computerVersion: 0},
+		{version: 196613, userVersion: 3, computerVersion: 5},
+	}
+	for _, tc := range cases {
+		userVersion, computerVersion := splitGPOVersion(tc.version)
+		if userVersion != tc.userVersion || computerVersion != tc.computerVersion {
+			t.Errorf("splitGPOVersion(%d) = %d, %d, want %d, %d", tc.version, userVersion, computerVersion, tc.userVersion, tc.computerVersion)
+		}
+		if version := joinGPOVersion(tc.userVersion, tc.computerVersion); version != tc.version {
+			t.Errorf("joinGPOVersion(%d, %d) = %d, want %d", tc.userVersion, tc.computerVersion, version, tc.version)
+		}
+	}
+}
+
+func TestGetSetADGPOVersionCmd(t *testing.T) {
+	cmd := getSetADGPOVersionCmd("CN={9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02},CN=Policies,CN=System,DC=contoso,DC=com", 65537, 65538)
+	expected := `$o=Get-ADObject -Identity "CN={9CB8219C-31FF-4A85-A7A3-9BCBB6A41D02},CN=Policies,CN=System,DC=contoso,DC=com" -Properties versionNumber; ` +
+		`if ([uint32]$o.versionNumber -ne 65537) { Write-Output "conflict" } else { $o.versionNumber=65538; Set-ADObject -Instance $o }`
+	if cmd != expected {
+		t.Errorf("getSetADGPOVersionCmd() = %q, want %q", cmd, expected)
+	}
+}
@@ -23,7 +23,66 @@ type Group struct {
 	Category          string
 	Container         string
 	Description       string
-	SID               SID `json:"SID"`
+	DisplayName       string `json:"DisplayName"`
+	ManagedBy         string `json:"ManagedBy"`
+	Info              string `json:"info"`
+	Mail              string `json:"mail"`
+	Protected         bool   `json:"ProtectedFromAccidentalDeletion"`
+	SID               SID    `json:"SID"`
+	CustomAttributes  map[string]interface{}
+}
+
+// groupScopeTransitions returns the list of scopes a group has to be converted to, in order,
+// to go from one scope to another. AD does not allow converting a global group to a domain local
+// group (or the other way around) directly, the group has to be converted to a universal group first.
+func groupScopeTransitions(from, to string) []string {
+	from = strings.ToLower(from)
+	to = strings.ToLower(to)
+	if from == to {
+		return []string{}
+	}
+	if (from == "global" && to == "domainlocal") || (from == "domainlocal" && to == "global") {
+		return []string{"universal", to}
+	}
+	return []string{to}
+}
+
+// getOtherAttributes returns the -OtherAttributes hashtable used when creating the group, or an
+// empty string if no LDAP attributes need to be set this way.
+func (g *Group) getOtherAttributes() string {
+	attrs := getOtherAttributesList(g.CustomAttributes)
+	if g.Info != "" {
+		attrs = append(attrs, fmt.Sprintf(`'info'="%s"`, g.Info))
+	}
+	if g.Mail != "" {
+		attrs = append(attrs, fmt.Sprintf(`'mail'="%s"`, g.Mail))
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("@{%s}", strings.Join(attrs, ";"))
+}
+
+func (g *Group) runSetADGroup(conf *config.ProviderConf, cmds []string) error {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return fmt.Errorf("command Set-ADGroup exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+	return nil
 }
 
 // AddGroup creates a new group
@@ -38,6 +97,19 @@ func (g *Group) AddGroup(conf *config.ProviderConf) (string, error) {
 	if g.Description != "" {
 		cmds = append(cmds, fmt.Sprintf("-Description %q", g.Description))
 	}
+
+	if g.DisplayName != "" {
+		cmds = append(cmds, fmt.Sprintf("-DisplayName %q", g.DisplayName))
+	}
+
+	if g.ManagedBy != "" {
+		cmds = append(cmds, fmt.Sprintf("-ManagedBy %q", g.ManagedBy))
+	}
+
+	if attrs := g.getOtherAttributes(); attrs != "" {
+		cmds = append(cmds, fmt.Sprintf("-OtherAttributes %s", attrs))
+	}
+
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      true,
 		ForceArray:      false,
@@ -61,11 +133,18 @@ func (g *Group) AddGroup(conf *config.ProviderConf) (string, error) {
 		return "", fmt.Errorf("command New-ADGroup exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
 	}
 
-	group, err := unmarshallGroup([]byte(result.Stdout))
+	group, err := unmarshallGroup([]byte(result.Stdout), nil)
 	if err != nil {
 		return "", fmt.Errorf("error while unmarshalling group json document: %s", err)
 	}
 
+	if g.Protected {
+		err = setProtectedFromAccidentalDeletion(conf, group.GUID, true)
+		if err != nil {
+			return group.GUID, err
+		}
+	}
+
 	return group.GUID, nil
 }
 
@@ -73,9 +152,10 @@ func (g *Group) AddGroup(conf *config.ProviderConf) (string, error) {
 func (g *Group) ModifyGroup(d *schema.ResourceData, conf *config.ProviderConf) error {
 	KeyMap := map[string]string{
 		"sam_account_name": "SamAccountName",
-		"scope":            "GroupScope",
 		"category":         "GroupCategory",
 		"description":      "Description",
+		"display_name":     "DisplayName",
+		"managed_by":       "ManagedBy",
 	}
 
 	cmds := []string{fmt.Sprintf("Set-ADGroup -Identity %q", g.GUID)}
@@ -92,28 +172,82 @@ func (g *Group) ModifyGroup(d *schema.ResourceData, conf *config.ProviderConf) e
 		}
 	}
 
+	ldapKeyMap := map[string]string{
+		"info": "info",
+		"mail": "mail",
+	}
+	toClear := []string{}
+	toReplace := []string{}
+	for k, attr := range ldapKeyMap {
+		if d.HasChange(k) {
+			value := SanitiseTFInput(d, k)
+			if value == "" {
+				toClear = append(toClear, attr)
+			} else {
+				toReplace = append(toReplace, fmt.Sprintf(`'%s'="%s"`, attr, value))
+			}
+		}
+	}
+	if len(toClear) > 0 {
+		cmds = append(cmds, fmt.Sprintf("-Clear %s", strings.Join(toClear, ",")))
+	}
+	if len(toReplace) > 0 {
+		cmds = append(cmds, fmt.Sprintf("-Replace @{%s}", strings.Join(toReplace, ";")))
+	}
+
 	if len(cmds) > 1 {
-		psOpts := CreatePSCommandOpts{
-			JSONOutput:      false,
-			ForceArray:      false,
-			ExecLocally:     conf.IsConnectionTypeLocal(),
-			PassCredentials: conf.IsPassCredentialsEnabled(),
-			Username:        conf.Settings.WinRMUsername,
-			Password:        conf.Settings.WinRMPassword,
-			Server:          conf.IdentifyDomainController(),
+		err := g.runSetADGroup(conf, cmds)
+		if err != nil {
+			return err
 		}
-		psCmd := NewPSCommand(cmds, psOpts)
-		result, err := psCmd.Run(conf)
+	}
+
+	caCmds, err := getCustomAttributesChanges(d)
+	if err != nil {
+		return err
+	}
+	if len(caCmds) > 0 {
+		cmds = append([]string{fmt.Sprintf("Set-ADGroup -Identity %q", g.GUID)}, caCmds...)
+		err := g.runSetADGroup(conf, cmds)
 		if err != nil {
 			return err
 		}
-		if result.ExitCode != 0 {
-			log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
-			return fmt.Errorf("command Set-ADGroup exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	if d.HasChange("scope") {
+		oldScope, newScope := d.GetChange("scope")
+		if d.IsNewResource() {
+			// the scope of a group restored from the recycle bin is only known to AD
+			current, err := GetGroupFromHost(conf, g.GUID, nil)
+			if err != nil {
+				return err
+			}
+			oldScope = current.Scope
+		}
+		for _, scope := range groupScopeTransitions(oldScope.(string), newScope.(string)) {
+			cmd := fmt.Sprintf("Set-ADGroup -Identity %q -GroupScope %q", g.GUID, scope)
+			err := g.runSetADGroup(conf, []string{cmd})
+			if err != nil {
+				return fmt.Errorf("while converting group to scope %q: %s", scope, err)
+			}
 		}
 	}
 
-	if d.HasChange("name") {
+	// Groups restored from the recycle bin already have the configured name and container.
+	moved := !d.IsNewResource() && (d.HasChange("name") || d.HasChange("container"))
+
+	// Protected objects cannot be renamed or moved, so we temporarily lift the protection.
+	oldProtected, _ := d.GetChange("protected_from_accidental_deletion")
+	unprotected := false
+	if oldProtected.(bool) && moved {
+		err := setProtectedFromAccidentalDeletion(conf, g.GUID, false)
+		if err != nil {
+			return err
+		}
+		unprotected = true
+	}
+
+	if moved && d.HasChange("name") {
 		cmd := fmt.Sprintf("Rename-ADObject -Identity %q -NewName %q", g.GUID, d.Get("name").(string))
 		psOpts := CreatePSCommandOpts{
 			JSONOutput:      false,
@@ -135,7 +269,7 @@ func (g *Group) ModifyGroup(d *schema.ResourceData, conf *config.ProviderConf) e
 		}
 	}
 
-	if d.HasChange("container") {
+	if moved && d.HasChange("container") {
 		cmd := fmt.Sprintf("Move-ADObject -Identity %q -TargetPath %q", g.GUID, d.Get("container").(string))
 		psOpts := CreatePSCommandOpts{
 			JSONOutput:      false,
@@ -156,11 +290,25 @@ func (g *Group) ModifyGroup(d *schema.ResourceData, conf *config.ProviderConf) e
 		}
 	}
 
+	if unprotected || d.HasChange("protected_from_accidental_deletion") {
+		err := setProtectedFromAccidentalDeletion(conf, g.GUID, g.Protected)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // DeleteGroup removes a group
 func (g *Group) DeleteGroup(conf *config.ProviderConf) error {
+	if g.Protected {
+		err := setProtectedFromAccidentalDeletion(conf, g.GUID, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd := fmt.Sprintf("Remove-ADGroup -Identity %s -Confirm:$false", g.GUID)
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
@@ -186,7 +334,7 @@ func (g *Group) DeleteGroup(conf *config.ProviderConf) error {
 }
 
 // GetGroupFromResource returns a Group struct built from Resource data
-func GetGroupFromResource(d *schema.ResourceData) *Group {
+func GetGroupFromResource(d *schema.ResourceData) (*Group, error) {
 	g := Group{
 		Name:           SanitiseTFInput(d, "name"),
 		SAMAccountName: SanitiseTFInput(d, "sam_account_name"),
@@ -195,14 +343,25 @@ func GetGroupFromResource(d *schema.ResourceData) *Group {
 		Category:       SanitiseTFInput(d, "category"),
 		GUID:           SanitiseString(d.Id()),
 		Description:    SanitiseTFInput(d, "description"),
+		DisplayName:    SanitiseTFInput(d, "display_name"),
+		ManagedBy:      SanitiseTFInput(d, "managed_by"),
+		Info:           SanitiseTFInput(d, "info"),
+		Mail:           SanitiseTFInput(d, "mail"),
+		Protected:      d.Get("protected_from_accidental_deletion").(bool),
+	}
+
+	customAttributes, err := getCustomAttributesFromResource(d)
+	if err != nil {
+		return nil, err
 	}
+	g.CustomAttributes = customAttributes
 
-	return &g
+	return &g, nil
 }
 
 // GetGroupFromHost returns a Group struct based on data
 // retrieved from the AD Controller.
-func GetGroupFromHost(conf *config.ProviderConf, guid string) (*Group, error) {
+func GetGroupFromHost(conf *config.ProviderConf, guid string, customAttributes []string) (*Group, error) {
 	cmd := fmt.Sprintf("Get-ADGroup -identity %q -properties *", guid)
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      true,
@@ -225,7 +384,7 @@ func GetGroupFromHost(conf *config.ProviderConf, guid string) (*Group, error) {
 		return nil, fmt.Errorf("command Get-ADGroup exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
 	}
 
-	g, err := unmarshallGroup([]byte(result.Stdout))
+	g, err := unmarshallGroup([]byte(result.Stdout), customAttributes)
 	if err != nil {
 		return nil, fmt.Errorf("error while unmarshalling group json document: %s", err)
 	}
@@ -236,7 +395,7 @@ func GetGroupFromHost(conf *config.ProviderConf, guid string) (*Group, error) {
 // unmarshallGroup unmarshalls the incoming byte array containing JSON
 // into a Group structure and populates all fields based on the data
 // extracted.
-func unmarshallGroup(input []byte) (*Group, error) {
+func unmarshallGroup(input []byte, customAttributes []string) (*Group, error) {
 	var g Group
 	err := json.Unmarshal(input, &g)
 	if err != nil {
@@ -255,5 +414,10 @@ func unmarshallGroup(input []byte) (*Group, error) {
 	commaIdx := strings.Index(g.DistinguishedName, ",")
 	g.Container = g.DistinguishedName[commaIdx+1:]
 
+	g.CustomAttributes, err = unmarshallCustomAttributes(input, customAttributes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &g, nil
 }
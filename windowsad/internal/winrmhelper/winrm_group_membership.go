@@ -3,6 +3,7 @@ package winrmhelper
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
@@ -13,6 +14,9 @@ import (
 type GroupMembership struct {
 	GroupGUID    string
 	GroupMembers []*GroupMember
+	// TTL is the time to live, in seconds, applied to members added to the group.
+	// A value of 0 means members are added permanently.
+	TTL int
 }
 
 type GroupMember struct {
@@ -20,6 +24,14 @@ type GroupMember struct {
 	DN             string `json:"DistinguishedName"`
 	GUID           string `json:"ObjectGUID"`
 	Name           string `json:"Name"`
+	// TTL holds the remaining time to live of a time-bound membership, in seconds.
+	TTL int `json:"-"`
+}
+
+// groupMemberTTLs is used to unmarshal the member attribute of a group
+// retrieved with the -ShowMemberTimeToLive flag.
+type groupMemberTTLs struct {
+	Member []string `json:"member"`
 }
 
 func groupExistsInList(g *GroupMember, memberList []*GroupMember) bool {
@@ -69,6 +81,87 @@ func getMembershipList(g []*GroupMember) string {
 	return strings.Join(out, ",")
 }
 
+// parseMemberTTL parses a member value returned by Get-ADGroup -ShowMemberTimeToLive.
+// Time-bound members are returned in the form "<TTL=3600>,CN=user,DC=example,DC=com",
+// permanent members are returned as a plain DN and get a TTL of 0.
+func parseMemberTTL(value string) (string, int, error) {
+	if !strings.HasPrefix(value, "<TTL=") {
+		return value, 0, nil
+	}
+
+	endIdx := strings.Index(value, ">,")
+	if endIdx < 0 {
+		return "", 0, fmt.Errorf("invalid time-bound member value %q", value)
+	}
+
+	ttl, err := strconv.Atoi(value[len("<TTL="):endIdx])
+	if err != nil {
+		return "", 0, fmt.Errorf("while parsing TTL of member value %q: %s", value, err)
+	}
+
+	return value[endIdx+2:], ttl, nil
+}
+
+func (g *GroupMembership) getMemberTTLArg() string {
+	if g.TTL <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("-MemberTimeToLive (New-TimeSpan -Seconds %d)", g.TTL)
+}
+
+// getMemberTTLs returns a map of member DNs (lower case) to their remaining TTL in seconds.
+// This requires the Privileged Access Management optional feature (Windows Server 2016 forest functional level).
+func (g *GroupMembership) getMemberTTLs(conf *config.ProviderConf) (map[string]int, error) {
+	cmd := fmt.Sprintf("Get-ADGroup -Identity %q -Properties member -ShowMemberTimeToLive", g.GroupGUID)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, fmt.Errorf("while running Get-ADGroup: %s", err)
+	} else if result.ExitCode != 0 {
+		return nil, fmt.Errorf("command Get-ADGroup exited with a non-zero exit code(%d), stderr: %s, stdout: %s", result.ExitCode, result.StdErr, result.Stdout)
+	}
+
+	var members groupMemberTTLs
+	err = json.Unmarshal([]byte(result.Stdout), &members)
+	if err != nil {
+		return nil, fmt.Errorf("while unmarshalling group member TTL response: %s", err)
+	}
+
+	ttls := make(map[string]int, len(members.Member))
+	for _, m := range members.Member {
+		dn, ttl, err := parseMemberTTL(m)
+		if err != nil {
+			return nil, err
+		}
+		ttls[strings.ToLower(dn)] = ttl
+	}
+
+	return ttls, nil
+}
+
+// PopulateMemberTTLs retrieves the remaining time to live of each member and stores it in the member's TTL field.
+func (g *GroupMembership) PopulateMemberTTLs(conf *config.ProviderConf) error {
+	ttls, err := g.getMemberTTLs(conf)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range g.GroupMembers {
+		m.TTL = ttls[strings.ToLower(m.DN)]
+	}
+
+	return nil
+}
+
 func (g *GroupMembership) getGroupMembers(conf *config.ProviderConf) ([]*GroupMember, error) {
 	cmd := fmt.Sprintf("Get-ADGroupMember -Identity %q", g.GroupGUID)
 	psOpts := CreatePSCommandOpts{
@@ -100,13 +193,18 @@ func (g *GroupMembership) getGroupMembers(conf *config.ProviderConf) ([]*GroupMe
 	return gm, nil
 }
 
-func (g *GroupMembership) bulkGroupMembersOp(conf *config.ProviderConf, operation string, members []*GroupMember) error {
+func (g *GroupMembership) bulkGroupMembersOp(conf *config.ProviderConf, operation string, members []*GroupMember, extraArgs ...string) error {
 	if len(members) == 0 {
 		return nil
 	}
 
 	memberList := getMembershipList(members)
-	cmd := fmt.Sprintf("%s -Identity %q %s -Confirm:$false", operation, g.GroupGUID, memberList)
+	cmds := []string{fmt.Sprintf("%s -Identity %q %s -Confirm:$false", operation, g.GroupGUID, memberList)}
+	for _, arg := range extraArgs {
+		if arg != "" {
+			cmds = append(cmds, arg)
+		}
+	}
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
 		ForceArray:      false,
@@ -116,7 +214,7 @@ func (g *GroupMembership) bulkGroupMembersOp(conf *config.ProviderConf, operatio
 		Password:        conf.Settings.WinRMPassword,
 		Server:          conf.IdentifyDomainController(),
 	}
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	psCmd := NewPSCommand(cmds, psOpts)
 	result, err := psCmd.Run(conf)
 
 	if err != nil {
@@ -129,7 +227,7 @@ func (g *GroupMembership) bulkGroupMembersOp(conf *config.ProviderConf, operatio
 }
 
 func (g *GroupMembership) addGroupMembers(conf *config.ProviderConf, members []*GroupMember) error {
-	return g.bulkGroupMembersOp(conf, "Add-ADGroupMember", members)
+	return g.bulkGroupMembersOp(conf, "Add-ADGroupMember", members, g.getMemberTTLArg())
 }
 
 func (g *GroupMembership) removeGroupMembers(conf *config.ProviderConf, members []*GroupMember) error {
@@ -163,6 +261,9 @@ func (g *GroupMembership) Create(conf *config.ProviderConf) error {
 
 	memberList := getMembershipList(g.GroupMembers)
 	cmds := []string{fmt.Sprintf("Add-ADGroupMember -Identity %q -Members %s", g.GroupGUID, memberList)}
+	if ttlArg := g.getMemberTTLArg(); ttlArg != "" {
+		cmds = append(cmds, ttlArg)
+	}
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
 		ForceArray:      false,
@@ -236,6 +337,7 @@ func NewGroupMembershipFromState(d *schema.ResourceData) (*GroupMembership, erro
 	result := &GroupMembership{
 		GroupGUID:    groupID,
 		GroupMembers: []*GroupMember{},
+		TTL:          d.Get("ttl").(int),
 	}
 
 	for _, m := range members.List() {
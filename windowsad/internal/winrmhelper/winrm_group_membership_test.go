@@ -0,0 +1,52 @@
+package winrmhelper
+
+import "testing"
+
+func TestParseMemberTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expectedDN  string
+		expectedTTL int
+		expectErr   bool
+	}{
+		{"permanent member", "CN=jdoe,OU=Users,DC=example,DC=com", "CN=jdoe,OU=Users,DC=example,DC=com", 0, false},
+		{"time-bound member", "<TTL=3599>,CN=jdoe,OU=Users,DC=example,DC=com", "CN=jdoe,OU=Users,DC=example,DC=com", 3599, false},
+		{"missing separator", "<TTL=3599>CN=jdoe,DC=example,DC=com", "", 0, true},
+		{"invalid ttl", "<TTL=abc>,CN=jdoe,DC=example,DC=com", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dn, ttl, err := parseMemberTTL(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("parseMemberTTL(%q) expected an error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMemberTTL(%q) unexpected error: %s", tt.value, err)
+			}
+			if dn != tt.expectedDN {
+				t.Errorf("parseMemberTTL(%q) dn = %q, want %q", tt.value, dn, tt.expectedDN)
+			}
+			if ttl != tt.expectedTTL {
+				t.Errorf("parseMemberTTL(%q) ttl = %d, want %d", tt.value, ttl, tt.expectedTTL)
+			}
+		})
+	}
+}
+
+func TestGroupMembership_GetMemberTTLArg(t *testing.T) {
+	gm := &GroupMembership{GroupGUID: "group-guid"}
+	if arg := gm.getMemberTTLArg(); arg != "" {
+		t.Errorf("getMemberTTLArg() = %q, want empty string", arg)
+	}
+
+	gm.TTL = 3600
+	expected := "-MemberTimeToLive (New-TimeSpan -Seconds 3600)"
+	if arg := gm.getMemberTTLArg(); arg != expected {
+		t.Errorf("getMemberTTLArg() = %q, want %q", arg, expected)
+	}
+}
@@ -2,6 +2,8 @@ package winrmhelper
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/packer-community/winrmcp/winrmcp"
 )
 
@@ -95,30 +98,6 @@ func SanitiseString(key string) string {
 	return out
 }
 
-// SetMachineExtensionNames will add the necessary GUIDs to the GPO's gPCMachineExtensionNames attribute.
-// These are required for the security settings part of a GPO to work.
-func SetMachineExtensionNames(conf *config.ProviderConf, gpoDN, value string) error {
-	cmd := fmt.Sprintf(`Set-ADObject -Identity "%s" -Replace @{gPCMachineExtensionNames="%s"}`, gpoDN, value)
-	psOpts := CreatePSCommandOpts{
-		JSONOutput:      false,
-		ForceArray:      false,
-		ExecLocally:     conf.IsConnectionTypeLocal(),
-		PassCredentials: conf.IsPassCredentialsEnabled(),
-		Username:        conf.Settings.WinRMUsername,
-		Password:        conf.Settings.WinRMPassword,
-		Server:          conf.IdentifyDomainController(),
-	}
-	psCmd := NewPSCommand([]string{cmd}, psOpts)
-	result, err := psCmd.Run(conf)
-	if err != nil {
-		return fmt.Errorf("error while setting machine extension names for GPO %q: %s", gpoDN, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("command to set machine extension names for GPO %q failed, stderr: %s, stdout: %s", gpoDN, result.StdErr, result.Stdout)
-	}
-	return nil
-}
-
 func GetString(v interface{}) string {
 	var out string
 	kind := reflect.ValueOf(v).Kind()
@@ -156,6 +135,271 @@ func SortInnerSlice(m map[string]interface{}) map[string]interface{} {
 	return m
 }
 
+// getCustomAttributesChanges returns the -Clear, -Replace and -Add parameters required to bring the
+// custom attributes of an AD object in line with the custom_attributes field of the resource.
+func getCustomAttributesChanges(d *schema.ResourceData) ([]string, error) {
+	if !d.HasChange("custom_attributes") {
+		return []string{}, nil
+	}
+
+	oldValue, newValue := d.GetChange("custom_attributes")
+	return customAttributesChanges(oldValue.(string), newValue.(string))
+}
+
+// customAttributesChanges returns the -Clear, -Replace and -Add parameters required to go from
+// the old custom attributes JSON document to the new one.
+func customAttributesChanges(oldValue, newValue string) ([]string, error) {
+	cmds := []string{}
+	newMap := map[string]interface{}{}
+	if newValue != "" {
+		var err error
+		newMap, err = structure.ExpandJsonFromString(newValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newSortedMap := SortInnerSlice(newMap)
+	toClear := []string{}
+	toReplace := []string{}
+	toAdd := []string{}
+
+	var oldSortedMap map[string]interface{}
+	if oldValue != "" {
+		oldMap, err := structure.ExpandJsonFromString(oldValue)
+		if err != nil {
+			return nil, fmt.Errorf("while expanding CA json string %s: %s", oldValue, err)
+		}
+		oldSortedMap = SortInnerSlice(oldMap)
+	}
+
+	for k, v := range oldSortedMap {
+		if newVal, ok := newSortedMap[k]; ok {
+			if !reflect.DeepEqual(v, newVal) {
+				var out string
+				if reflect.ValueOf(newVal).Kind() == reflect.Slice {
+					quotedStrings := make([]string, len(newVal.([]string)))
+					for idx, s := range newVal.([]string) {
+						// Using %q here will cause double quotes inside the string to be escaped with \"
+						// which is not desirable in Powershell
+						quotedStrings[idx] = fmt.Sprintf(`"%s"`, s)
+					}
+					out = strings.Join(quotedStrings, ",")
+				} else {
+					out = fmt.Sprintf(`"%s"`, newVal.(string))
+				}
+				toReplace = append(toReplace, fmt.Sprintf(`'%s'=%s`, SanitiseString(k), out))
+			}
+		} else {
+			toClear = append(toClear, fmt.Sprintf(`'%s'`, SanitiseString(k)))
+		}
+	}
+
+	for k, newVal := range newSortedMap {
+		if _, ok := oldSortedMap[k]; !ok {
+			var out string
+			if reflect.ValueOf(newVal).Kind() == reflect.Slice {
+				quotedStrings := make([]string, len(newVal.([]string)))
+				for idx, s := range newVal.([]string) {
+					// Using %q here will cause double quotes inside the string to be escaped with \"
+					// which is not desirable in Powershell
+					quotedStrings[idx] = s
+				}
+				out = strings.Join(quotedStrings, ",")
+			} else {
+				out = newVal.(string)
+			}
+			toAdd = append(toAdd, fmt.Sprintf(`'%s'=%s`, SanitiseString(k), out))
+		}
+	}
+
+	if len(toClear) > 0 {
+		cmds = append(cmds, fmt.Sprintf(`-Clear %s`, strings.Join(toClear, ";")))
+	}
+
+	if len(toReplace) > 0 {
+		cmds = append(cmds, fmt.Sprintf(`-Replace @{%s}`, strings.Join(toReplace, ";")))
+	}
+
+	if len(toAdd) > 0 {
+		cmds = append(cmds, fmt.Sprintf(`-Add @{%s}`, strings.Join(toAdd, ";")))
+	}
+
+	return cmds, nil
+}
+
+// getOtherAttributesList returns a list of powershell hashtable entries built from a custom attributes map.
+func getOtherAttributesList(customAttributes map[string]interface{}) []string {
+	out := []string{}
+	for k, v := range customAttributes {
+		cleanKey := SanitiseString(k)
+		var cleanValue string
+		if reflect.ValueOf(v).Kind() == reflect.Slice {
+			quotedStrings := make([]string, len(v.([]interface{})))
+			for idx, s := range v.([]interface{}) {
+				// Using %q here will cause double quotes inside the string to be escaped with \"
+				// which is not desirable in Powershell
+				quotedStrings[idx] = GetString(s.(string))
+			}
+			cleanValue = strings.Join(quotedStrings, ",")
+		} else {
+			cleanValue = GetString(v)
+		}
+		out = append(out, fmt.Sprintf(`'%s'=%s`, cleanKey, cleanValue))
+	}
+	return out
+}
+
+// getOtherAttributes returns a powershell hashtable built from a custom attributes map,
+// suitable for use with the -OtherAttributes parameter of the New-AD* cmdlets.
+func getOtherAttributes(customAttributes map[string]interface{}) string {
+	return fmt.Sprintf("@{%s}", strings.Join(getOtherAttributesList(customAttributes), ";"))
+}
+
+// getPrincipalsList returns a powershell array of quoted principal identities
+func getPrincipalsList(principals []string) string {
+	quoted := make([]string, len(principals))
+	for idx, p := range principals {
+		quoted[idx] = fmt.Sprintf("%q", p)
+	}
+	return fmt.Sprintf("@(%s)", strings.Join(quoted, ","))
+}
+
+// setProtectedFromAccidentalDeletion sets the ProtectedFromAccidentalDeletion flag of an AD object.
+func setProtectedFromAccidentalDeletion(conf *config.ProviderConf, identity string, protected bool) error {
+	cmd := fmt.Sprintf("Set-ADObject -Identity %q -ProtectedFromAccidentalDeletion:$%t", identity, protected)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while updating protected status of object %q: %s", identity, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("Set-ADObject exited with a non-zero exit code (%d) while updating protected status of object %q, stderr: %s", result.ExitCode, identity, result.StdErr)
+	}
+	return nil
+}
+
+// getCustomAttributesFromResource returns the custom attributes map stored in the custom_attributes
+// field of a resource, or nil if the field is not set.
+func getCustomAttributesFromResource(d *schema.ResourceData) (map[string]interface{}, error) {
+	ca, ok := d.Get("custom_attributes").(string)
+	if !ok || len(ca) == 0 {
+		return nil, nil
+	}
+
+	customAttributes, err := structure.ExpandJsonFromString(ca)
+	if err != nil {
+		return nil, fmt.Errorf("while unmarshalling custom attributes JSON doc: %s", err)
+	}
+	return customAttributes, nil
+}
+
+// unmarshallCustomAttributes extracts the given custom attributes from the JSON document
+// returned by a Get-AD* cmdlet. It returns nil if no custom attributes were requested.
+func unmarshallCustomAttributes(input []byte, customAttributes []string) (map[string]interface{}, error) {
+	if customAttributes == nil {
+		return nil, nil
+	}
+
+	var objectMapIntf interface{}
+	err := json.Unmarshal(input, &objectMapIntf)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling json response: %s", err)
+	}
+
+	objectMap := objectMapIntf.(map[string]interface{})
+	result := make(map[string]interface{})
+	for _, property := range customAttributes {
+		if val, ok := objectMap[property]; ok {
+			result[property] = val
+		}
+	}
+
+	return result, nil
+}
+
+// getSYSVOLFileBase64Cmd returns the command printing the contents of a file in base64, so that
+// binary files survive the trip through stdout. Nothing is printed if the file does not exist.
+func getSYSVOLFileBase64Cmd(path string) string {
+	return fmt.Sprintf(`if (Test-Path -LiteralPath "%[1]s") { [Convert]::ToBase64String([IO.File]::ReadAllBytes("%[1]s")) }`, path)
+}
+
+// GetSYSVOLFileContents returns the exact contents of a file of SYSVOL. The second return value is
+// false if the file does not exist.
+func GetSYSVOLFileContents(conf *config.ProviderConf, path string) ([]byte, bool, error) {
+	log.Printf("[DEBUG] Getting contents of %s", path)
+	domainName := conf.Settings.DomainName
+	if conf.Settings.KrbRealm == domainName {
+		domainName = "$env:computername"
+	}
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          domainName,
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+	}
+	psCmd := NewPSCommand([]string{getSYSVOLFileBase64Cmd(path)}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, false, fmt.Errorf("error while retrieving contents of %q: %s", path, err)
+	}
+	if result.ExitCode != 0 {
+		return nil, false, fmt.Errorf("command to retrieve contents of %q failed, stderr: %s, stdout: %s", path, result.StdErr, result.Stdout)
+	}
+
+	encoded := strings.TrimSpace(result.Stdout)
+	if encoded == "" {
+		return nil, false, nil
+	}
+	contents, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, fmt.Errorf("error while decoding contents of %q: %s", path, err)
+	}
+	return contents, true, nil
+}
+
+// RemoveSYSVOLFile removes a file of SYSVOL. Files that don't exist are ignored.
+func RemoveSYSVOLFile(conf *config.ProviderConf, path string) error {
+	cmd := fmt.Sprintf(`Remove-Item -LiteralPath "%s"`, path)
+	domainName := conf.Settings.DomainName
+	if conf.Settings.KrbRealm == domainName {
+		domainName = "$env:computername"
+	}
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          domainName,
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("error while removing %q: %s", path, err)
+	}
+	if result.ExitCode != 0 && !strings.Contains(result.StdErr, "ItemNotFoundException") {
+		return fmt.Errorf("error while removing %q, stderr: %s", path, result.StdErr)
+	}
+	return nil
+}
+
 func UploadFiletoSYSVOL(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, buf io.Reader, destPath string) error {
 	tmpPathCmd := NewPSCommand([]string{"$randompath=[System.IO.Path]::GetRandomFileName(); echo $env:TMP\\$randompath"}, CreatePSCommandOpts{
 		ForceArray:      false,
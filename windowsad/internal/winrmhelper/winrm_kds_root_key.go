@@ -0,0 +1,105 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+)
+
+// KdsRootKey represents a Key Distribution Services root key, used to derive the passwords of group managed service accounts
+type KdsRootKey struct {
+	KeyID         string `json:"KeyId"`
+	EffectiveTime string `json:"EffectiveTime"`
+	CreationTime  string `json:"CreationTime"`
+}
+
+// kdsRootKeySelect formats the properties of Get-KdsRootKey so they can be consumed as plain strings
+const kdsRootKeySelect = "Select-Object @{Name='KeyId';Expression={$_.KeyId.ToString()}}, " +
+	"@{Name='EffectiveTime';Expression={$_.EffectiveTime.ToUniversalTime().ToString(\"yyyy-MM-dd'T'HH:mm:ss'Z'\")}}, " +
+	"@{Name='CreationTime';Expression={$_.CreationTime.ToUniversalTime().ToString(\"yyyy-MM-dd'T'HH:mm:ss'Z'\")}}"
+
+// getAddKdsRootKeyCmd returns the Add-KdsRootKey command. If effectiveTime is empty the key is
+// made effective immediately, which still means domain controllers wait 10 hours before using it.
+func getAddKdsRootKeyCmd(effectiveTime string) string {
+	if effectiveTime == "" {
+		return "(Add-KdsRootKey -EffectiveImmediately).ToString()"
+	}
+	return fmt.Sprintf("(Add-KdsRootKey -EffectiveTime ([DateTime]::Parse(%q, [Globalization.CultureInfo]::InvariantCulture))).ToString()", effectiveTime)
+}
+
+// kdsRootKeyPSOpts returns the options used for the KdsRootKey cmdlets. They don't accept
+// credentials or a server, so they always run on the WinRM host, which must be a domain controller.
+func kdsRootKeyPSOpts(conf *config.ProviderConf, jsonOutput bool) CreatePSCommandOpts {
+	return CreatePSCommandOpts{
+		JSONOutput:      jsonOutput,
+		ForceArray:      jsonOutput,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: false,
+		SkipCredPrefix:  true,
+		SkipCredSuffix:  true,
+	}
+}
+
+// GetKdsRootKeys returns all the KDS root keys of the forest
+func GetKdsRootKeys(conf *config.ProviderConf) ([]KdsRootKey, error) {
+	cmd := fmt.Sprintf("Get-KdsRootKey | %s", kdsRootKeySelect)
+	psCmd := NewPSCommand([]string{cmd}, kdsRootKeyPSOpts(conf, true))
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, fmt.Errorf("winrm execution failure while retrieving KDS root keys: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("Get-KdsRootKey exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	return unmarshallKdsRootKeys([]byte(result.Stdout))
+}
+
+// GetKdsRootKeyFromHost returns the KDS root key with the given ID, or nil if it does not exist
+func GetKdsRootKeyFromHost(conf *config.ProviderConf, keyID string) (*KdsRootKey, error) {
+	keys, err := GetKdsRootKeys(conf)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if strings.EqualFold(key.KeyID, keyID) {
+			return &key, nil
+		}
+	}
+	return nil, nil
+}
+
+// NewKdsRootKey adds a new KDS root key and returns its ID
+func NewKdsRootKey(conf *config.ProviderConf, effectiveTime string) (string, error) {
+	log.Printf("[DEBUG] Adding KDS root key with effective time %q", effectiveTime)
+	psCmd := NewPSCommand([]string{getAddKdsRootKeyCmd(effectiveTime)}, kdsRootKeyPSOpts(conf, false))
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("winrm execution failure while adding KDS root key: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("Add-KdsRootKey exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	keyID := strings.TrimSpace(result.Stdout)
+	if keyID == "" {
+		return "", fmt.Errorf("Add-KdsRootKey did not return a key ID")
+	}
+	return keyID, nil
+}
+
+func unmarshallKdsRootKeys(input []byte) ([]KdsRootKey, error) {
+	keys := []KdsRootKey{}
+	if strings.TrimSpace(string(input)) == "" {
+		return keys, nil
+	}
+	err := json.Unmarshal(input, &keys)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall KDS root keys json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling KDS root keys json document: %s", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,40 @@
+package winrmhelper
+
+import (
+	"testing"
+)
+
+func TestGetAddKdsRootKeyCmd(t *testing.T) {
+	tests := []struct {
+		effectiveTime string
+		expected      string
+	}{
+		{"", "(Add-KdsRootKey -EffectiveImmediately).ToString()"},
+		{"2020-01-01T00:00:00Z", `(Add-KdsRootKey -EffectiveTime ([DateTime]::Parse("2020-01-01T00:00:00Z", [Globalization.CultureInfo]::InvariantCulture))).ToString()`},
+	}
+
+	for _, tt := range tests {
+		if got := getAddKdsRootKeyCmd(tt.effectiveTime); got != tt.expected {
+			t.Errorf("getAddKdsRootKeyCmd(%q) = %q, want %q", tt.effectiveTime, got, tt.expected)
+		}
+	}
+}
+
+func TestUnmarshallKdsRootKeys(t *testing.T) {
+	keys, err := unmarshallKdsRootKeys([]byte(""))
+	if err != nil {
+		t.Fatalf("unmarshallKdsRootKeys() unexpected error: %s", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("unmarshallKdsRootKeys() returned %d keys, want 0", len(keys))
+	}
+
+	input := `[{"KeyId":"8ed3d1e9-60cb-4a3c-8c32-5bc8dd4e1a11","EffectiveTime":"2020-01-01T00:00:00Z","CreationTime":"2020-01-01T10:00:00Z"}]`
+	keys, err = unmarshallKdsRootKeys([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallKdsRootKeys() unexpected error: %s", err)
+	}
+	if len(keys) != 1 || keys[0].KeyID != "8ed3d1e9-60cb-4a3c-8c32-5bc8dd4e1a11" || keys[0].EffectiveTime != "2020-01-01T00:00:00Z" {
+		t.Errorf("unmarshallKdsRootKeys() = %+v", keys)
+	}
+}
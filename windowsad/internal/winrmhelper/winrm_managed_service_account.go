@@ -0,0 +1,340 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// kerberosEncryptionTypes maps the values accepted by the KerberosEncryptionType parameter
+// to their msDS-SupportedEncryptionTypes flags, in the order they are reported back.
+var kerberosEncryptionTypes = []struct {
+	Name string
+	Flag int
+}{
+	{"DES", 0x3},
+	{"RC4", 0x4},
+	{"AES128", 0x8},
+	{"AES256", 0x10},
+}
+
+// KerberosEncryptionTypeNames returns the values accepted for Kerberos encryption types
+func KerberosEncryptionTypeNames() []string {
+	names := make([]string, len(kerberosEncryptionTypes))
+	for idx, et := range kerberosEncryptionTypes {
+		names[idx] = et.Name
+	}
+	return names
+}
+
+// decodeKerberosEncryptionTypes returns the encryption types enabled in a msDS-SupportedEncryptionTypes value
+func decodeKerberosEncryptionTypes(value int) []string {
+	result := []string{}
+	for _, et := range kerberosEncryptionTypes {
+		if value&et.Flag != 0 {
+			result = append(result, et.Name)
+		}
+	}
+	return result
+}
+
+// getKerberosEncryptionTypeArg returns the value for the KerberosEncryptionType parameter
+func getKerberosEncryptionTypeArg(encTypes []string) string {
+	if len(encTypes) == 0 {
+		return `"None"`
+	}
+	return fmt.Sprintf(`"%s"`, strings.Join(encTypes, ","))
+}
+
+// ManagedServiceAccount represents a group managed service account (gMSA) or a standalone managed service account (MSA)
+type ManagedServiceAccount struct {
+	GUID                                       string `json:"ObjectGUID"`
+	Name                                       string `json:"Name"`
+	SAMAccountName                             string `json:"SamAccountName"`
+	DN                                         string `json:"DistinguishedName"`
+	SID                                        SID    `json:"SID"`
+	ObjectClass                                string `json:"ObjectClass"`
+	Description                                string
+	DisplayName                                string
+	DNSHostName                                string `json:"DNSHostName"`
+	Enabled                                    bool
+	ManagedPasswordIntervalInDays              int `json:"msDS-ManagedPasswordInterval"`
+	PrincipalsAllowedToRetrieveManagedPassword []string
+	ServicePrincipalNames                      []string
+	SupportedEncryptionTypes                   int `json:"msDS-SupportedEncryptionTypes"`
+	// Constrained and resource-based constrained delegation settings, see KerberosDelegation
+	AllowedToDelegateTo                  []string `json:"msDS-AllowedToDelegateTo"`
+	TrustedToAuthForDelegation           bool
+	PrincipalsAllowedToDelegateToAccount []string
+	KerberosEncryptionTypes              []string `json:"-"`
+	Standalone                           bool     `json:"-"`
+	Container                            string   `json:"-"`
+}
+
+// GetManagedServiceAccountFromResource returns a ManagedServiceAccount struct built from Resource data
+func GetManagedServiceAccountFromResource(d *schema.ResourceData) *ManagedServiceAccount {
+	msa := &ManagedServiceAccount{
+		GUID:                          d.Id(),
+		Name:                          SanitiseTFInput(d, "name"),
+		Container:                     SanitiseTFInput(d, "container"),
+		Description:                   SanitiseTFInput(d, "description"),
+		DisplayName:                   SanitiseTFInput(d, "display_name"),
+		DNSHostName:                   SanitiseTFInput(d, "dns_host_name"),
+		Enabled:                       d.Get("enabled").(bool),
+		ManagedPasswordIntervalInDays: d.Get("managed_password_interval_in_days").(int),
+		Standalone:                    d.Get("type").(string) == "standalone",
+	}
+
+	if principals, ok := d.GetOk("principals_allowed_to_retrieve_managed_password"); ok {
+		for _, p := range principals.(*schema.Set).List() {
+			msa.PrincipalsAllowedToRetrieveManagedPassword = append(msa.PrincipalsAllowedToRetrieveManagedPassword, SanitiseString(p.(string)))
+		}
+	}
+	if spns, ok := d.GetOk("service_principal_names"); ok {
+		for _, spn := range spns.(*schema.Set).List() {
+			msa.ServicePrincipalNames = append(msa.ServicePrincipalNames, SanitiseString(spn.(string)))
+		}
+	}
+	if encTypes, ok := d.GetOk("kerberos_encryption_type"); ok {
+		for _, et := range encTypes.(*schema.Set).List() {
+			msa.KerberosEncryptionTypes = append(msa.KerberosEncryptionTypes, et.(string))
+		}
+	}
+
+	return msa
+}
+
+// getNewCmd returns the New-ADServiceAccount command used to create the account
+func (m *ManagedServiceAccount) getNewCmd() []string {
+	cmds := []string{fmt.Sprintf("New-ADServiceAccount -PassThru -Name %q", m.Name)}
+	cmds = append(cmds, fmt.Sprintf("-Enabled $%t", m.Enabled))
+
+	if m.Standalone {
+		cmds = append(cmds, "-RestrictToSingleComputer")
+	} else {
+		cmds = append(cmds, fmt.Sprintf("-DNSHostName %q", m.DNSHostName))
+		if m.ManagedPasswordIntervalInDays > 0 {
+			cmds = append(cmds, fmt.Sprintf("-ManagedPasswordIntervalInDays %d", m.ManagedPasswordIntervalInDays))
+		}
+		if len(m.PrincipalsAllowedToRetrieveManagedPassword) > 0 {
+			cmds = append(cmds, fmt.Sprintf("-PrincipalsAllowedToRetrieveManagedPassword %s", getPrincipalsList(m.PrincipalsAllowedToRetrieveManagedPassword)))
+		}
+	}
+
+	if m.Container != "" {
+		cmds = append(cmds, fmt.Sprintf("-Path %q", m.Container))
+	}
+
+	if m.Description != "" {
+		cmds = append(cmds, fmt.Sprintf("-Description %q", m.Description))
+	}
+
+	if m.DisplayName != "" {
+		cmds = append(cmds, fmt.Sprintf("-DisplayName %q", m.DisplayName))
+	}
+
+	if len(m.ServicePrincipalNames) > 0 {
+		cmds = append(cmds, fmt.Sprintf("-ServicePrincipalNames @(%s)", getServicePrincipalNamesList(m.ServicePrincipalNames)))
+	}
+
+	if len(m.KerberosEncryptionTypes) > 0 {
+		cmds = append(cmds, fmt.Sprintf("-KerberosEncryptionType %s", getKerberosEncryptionTypeArg(m.KerberosEncryptionTypes)))
+	}
+
+	return cmds
+}
+
+// NewManagedServiceAccount creates the account by running the New-ADServiceAccount powershell command
+func (m *ManagedServiceAccount) NewManagedServiceAccount(conf *config.ProviderConf) (string, error) {
+	if m.Name == "" {
+		return "", fmt.Errorf("ManagedServiceAccount.NewManagedServiceAccount: missing name variable")
+	}
+	if !m.Standalone && m.DNSHostName == "" {
+		return "", fmt.Errorf("dns_host_name is required for group managed service accounts")
+	}
+
+	log.Printf("[DEBUG] Adding managed service account with name %q", m.Name)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(m.getNewCmd(), psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("winrm execution failure while creating managed service account: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		if strings.Contains(result.StdErr, "KdsRootKey") || strings.Contains(result.StdErr, "Key does not exist") {
+			return "", fmt.Errorf("New-ADServiceAccount failed, make sure a KDS root key exists in the forest, stderr: %s", result.StdErr)
+		}
+		return "", fmt.Errorf("command New-ADServiceAccount exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	msa, err := unmarshallManagedServiceAccount([]byte(result.Stdout))
+	if err != nil {
+		return "", fmt.Errorf("error while unmarshalling managed service account json document: %s", err)
+	}
+
+	return msa.GUID, nil
+}
+
+// ModifyManagedServiceAccount updates the account based on what's changed in the resource.
+func (m *ManagedServiceAccount) ModifyManagedServiceAccount(d *schema.ResourceData, conf *config.ProviderConf) error {
+	log.Printf("[DEBUG] Modifying managed service account %q", m.GUID)
+	strKeyMap := map[string]string{
+		"description":   "Description",
+		"display_name":  "DisplayName",
+		"dns_host_name": "DNSHostName",
+	}
+
+	cmds := []string{fmt.Sprintf("Set-ADServiceAccount -Identity %q", m.GUID)}
+
+	for k, param := range strKeyMap {
+		if d.HasChange(k) {
+			value := SanitiseTFInput(d, k)
+			if value == "" {
+				value = "$null"
+			} else {
+				value = fmt.Sprintf(`"%s"`, value)
+			}
+			cmds = append(cmds, fmt.Sprintf(`-%s %s`, param, value))
+		}
+	}
+
+	if d.HasChange("enabled") {
+		cmds = append(cmds, fmt.Sprintf("-Enabled $%t", m.Enabled))
+	}
+
+	if d.HasChange("principals_allowed_to_retrieve_managed_password") {
+		if len(m.PrincipalsAllowedToRetrieveManagedPassword) == 0 {
+			cmds = append(cmds, "-PrincipalsAllowedToRetrieveManagedPassword $null")
+		} else {
+			cmds = append(cmds, fmt.Sprintf("-PrincipalsAllowedToRetrieveManagedPassword %s", getPrincipalsList(m.PrincipalsAllowedToRetrieveManagedPassword)))
+		}
+	}
+
+	if d.HasChange("kerberos_encryption_type") {
+		cmds = append(cmds, fmt.Sprintf("-KerberosEncryptionType %s", getKerberosEncryptionTypeArg(m.KerberosEncryptionTypes)))
+	}
+
+	if d.HasChange("service_principal_names") {
+		cmds = append(cmds, getServicePrincipalNamesParam(m.ServicePrincipalNames))
+	}
+
+	if len(cmds) > 1 {
+		err := m.runCmd(conf, cmds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("container") {
+		cmd := fmt.Sprintf("Move-ADObject -Identity %q -TargetPath %q", m.GUID, m.Container)
+		err := m.runCmd(conf, []string{cmd})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteManagedServiceAccount deletes the account by calling Remove-ADServiceAccount
+func (m *ManagedServiceAccount) DeleteManagedServiceAccount(conf *config.ProviderConf) error {
+	cmd := fmt.Sprintf("Remove-ADServiceAccount -Identity %q -Confirm:$false", m.GUID)
+	err := m.runCmd(conf, []string{cmd})
+	if err != nil {
+		// Check if the resource is already deleted
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *ManagedServiceAccount) runCmd(conf *config.ProviderConf, cmds []string) error {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while modifying managed service account: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return fmt.Errorf("command %s exited with a non-zero exit code %d, stderr: %s", strings.Fields(cmds[0])[0], result.ExitCode, result.StdErr)
+	}
+	return nil
+}
+
+// GetManagedServiceAccountFromHost returns a ManagedServiceAccount struct based on data
+// retrieved from the AD Domain Controller.
+func GetManagedServiceAccountFromHost(conf *config.ProviderConf, guid string) (*ManagedServiceAccount, error) {
+	cmd := fmt.Sprintf("Get-ADServiceAccount -Identity %q -Properties *", guid)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return nil, fmt.Errorf("command Get-ADServiceAccount exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	msa, err := unmarshallManagedServiceAccount([]byte(result.Stdout))
+	if err != nil {
+		return nil, fmt.Errorf("error while unmarshalling managed service account json document: %s", err)
+	}
+	return msa, nil
+}
+
+// unmarshallManagedServiceAccount unmarshalls the incoming byte array containing JSON
+// into a ManagedServiceAccount structure and populates the derived fields.
+func unmarshallManagedServiceAccount(input []byte) (*ManagedServiceAccount, error) {
+	var msa ManagedServiceAccount
+	err := json.Unmarshal(input, &msa)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling json response: %s", err)
+	}
+	if msa.GUID == "" {
+		return nil, fmt.Errorf("invalid data while unmarshalling ManagedServiceAccount data, json doc was: %s", string(input))
+	}
+
+	msa.Standalone = strings.EqualFold(msa.ObjectClass, "msDS-ManagedServiceAccount")
+	msa.KerberosEncryptionTypes = decodeKerberosEncryptionTypes(msa.SupportedEncryptionTypes)
+
+	commaIdx := strings.Index(msa.DN, ",")
+	msa.Container = msa.DN[commaIdx+1:]
+
+	return &msa, nil
+}
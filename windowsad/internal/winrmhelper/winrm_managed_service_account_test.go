@@ -0,0 +1,118 @@
+package winrmhelper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeKerberosEncryptionTypes(t *testing.T) {
+	tests := []struct {
+		value    int
+		expected []string
+	}{
+		{0, []string{}},
+		{0x4, []string{"RC4"}},
+		{0x18, []string{"AES128", "AES256"}},
+		{0x1f, []string{"DES", "RC4", "AES128", "AES256"}},
+	}
+
+	for _, tt := range tests {
+		if got := decodeKerberosEncryptionTypes(tt.value); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("decodeKerberosEncryptionTypes(%d) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestManagedServiceAccount_GetNewCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		msa        ManagedServiceAccount
+		expected   string
+		notContain []string
+	}{
+		{
+			name: "group managed service account",
+			msa: ManagedServiceAccount{
+				Name:                          "gmsa-sql",
+				DNSHostName:                   "sql.example.com",
+				Enabled:                       true,
+				ManagedPasswordIntervalInDays: 30,
+				PrincipalsAllowedToRetrieveManagedPassword: []string{"CN=SQL Servers,OU=Groups,DC=example,DC=com"},
+				KerberosEncryptionTypes:                    []string{"AES128", "AES256"},
+			},
+			expected: `New-ADServiceAccount -PassThru -Name "gmsa-sql" -Enabled $true -DNSHostName "sql.example.com" -ManagedPasswordIntervalInDays 30 -PrincipalsAllowedToRetrieveManagedPassword @("CN=SQL Servers,OU=Groups,DC=example,DC=com") -KerberosEncryptionType "AES128,AES256"`,
+		},
+		{
+			name: "standalone managed service account",
+			msa: ManagedServiceAccount{
+				Name:        "msa-web",
+				Standalone:  true,
+				Enabled:     true,
+				DNSHostName: "ignored.example.com",
+				Container:   "OU=Service Accounts,DC=example,DC=com",
+			},
+			expected:   `New-ADServiceAccount -PassThru -Name "msa-web" -Enabled $true -RestrictToSingleComputer -Path "OU=Service Accounts,DC=example,DC=com"`,
+			notContain: []string{"-DNSHostName", "-PrincipalsAllowedToRetrieveManagedPassword"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := strings.Join(tt.msa.getNewCmd(), " ")
+			if cmd != tt.expected {
+				t.Errorf("getNewCmd() = %q, want %q", cmd, tt.expected)
+			}
+			for _, s := range tt.notContain {
+				if strings.Contains(cmd, s) {
+					t.Errorf("getNewCmd() = %q, expected it not to contain %q", cmd, s)
+				}
+			}
+		})
+	}
+}
+
+func TestUnmarshallManagedServiceAccount(t *testing.T) {
+	input := `{
+		"ObjectGUID": "11111111-1111-1111-1111-111111111111",
+		"Name": "gmsa-sql",
+		"SamAccountName": "gmsa-sql$",
+		"DistinguishedName": "CN=gmsa-sql,CN=Managed Service Accounts,DC=example,DC=com",
+		"ObjectClass": "msDS-GroupManagedServiceAccount",
+		"DNSHostName": "sql.example.com",
+		"Enabled": true,
+		"msDS-ManagedPasswordInterval": 30,
+		"msDS-SupportedEncryptionTypes": 24,
+		"PrincipalsAllowedToRetrieveManagedPassword": ["CN=SQL Servers,OU=Groups,DC=example,DC=com"],
+		"ServicePrincipalNames": ["MSSQLSvc/sql.example.com:1433"]
+	}`
+
+	msa, err := unmarshallManagedServiceAccount([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallManagedServiceAccount() unexpected error: %s", err)
+	}
+	if msa.Standalone {
+		t.Errorf("Standalone = true, want false")
+	}
+	if msa.Container != "CN=Managed Service Accounts,DC=example,DC=com" {
+		t.Errorf("Container = %q", msa.Container)
+	}
+	if msa.ManagedPasswordIntervalInDays != 30 {
+		t.Errorf("ManagedPasswordIntervalInDays = %d, want 30", msa.ManagedPasswordIntervalInDays)
+	}
+	if !reflect.DeepEqual(msa.KerberosEncryptionTypes, []string{"AES128", "AES256"}) {
+		t.Errorf("KerberosEncryptionTypes = %v", msa.KerberosEncryptionTypes)
+	}
+	if len(msa.PrincipalsAllowedToRetrieveManagedPassword) != 1 || len(msa.ServicePrincipalNames) != 1 {
+		t.Errorf("unexpected principals %v or SPNs %v", msa.PrincipalsAllowedToRetrieveManagedPassword, msa.ServicePrincipalNames)
+	}
+
+	standalone := strings.Replace(input, "msDS-GroupManagedServiceAccount", "msDS-ManagedServiceAccount", 1)
+	msa, err = unmarshallManagedServiceAccount([]byte(standalone))
+	if err != nil {
+		t.Fatalf("unmarshallManagedServiceAccount() unexpected error: %s", err)
+	}
+	if !msa.Standalone {
+		t.Errorf("Standalone = false, want true")
+	}
+}
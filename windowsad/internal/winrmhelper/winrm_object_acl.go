@@ -0,0 +1,373 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/sddl"
+)
+
+// ObjectACE describes an access control entry on a directory object the way it is
+// configured, with principals and schema objects referred to by name
+type ObjectACE struct {
+	Principal           string
+	AccessType          string
+	Rights              []string
+	ObjectType          string
+	InheritedObjectType string
+	Inheritance         string
+}
+
+// objectSecurity is the security information returned by the host for an object
+type objectSecurity struct {
+	SDDL          string `json:"Sddl"`
+	DomainSID     string `json:"DomainSID"`
+	RootDomainSID string `json:"RootDomainSID"`
+}
+
+// objectACLPSOpts returns the options used for the ACL scripts. The scripts have several
+// statements, so they are wrapped in Invoke-Command when credentials are passed.
+func objectACLPSOpts(conf *config.ProviderConf, jsonOutput bool) CreatePSCommandOpts {
+	return CreatePSCommandOpts{
+		JSONOutput:      jsonOutput,
+		ForceArray:      jsonOutput,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+}
+
+func runObjectACLScript(conf *config.ProviderConf, script []string, jsonOutput bool) (string, error) {
+	cmds := append([]string{"$ErrorActionPreference = 'Stop';"}, script...)
+	psCmd := NewPSCommand([]string{strings.Join(cmds, " ")}, objectACLPSOpts(conf, jsonOutput))
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("winrm execution failure while managing object ACL: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return "", fmt.Errorf("object ACL script exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+	return result.Stdout, nil
+}
+
+// getObjectSecurityScript returns the script that reads the owner and DACL of an object together
+// with the SIDs needed to resolve the domain relative aliases of the SDDL string
+func getObjectSecurityScript(dn string) []string {
+	return []string{
+		fmt.Sprintf(`$o = Get-ADObject -Identity "%s" -Properties nTSecurityDescriptor;`, SanitiseString(dn)),
+		"$d = Get-ADDomain;",
+		"$f = Get-ADForest;",
+		"$r = $d.DomainSID.Value;",
+		"if ($f.RootDomain -ne $d.DNSRoot) { $r = (Get-ADDomain -Identity $f.RootDomain).DomainSID.Value };",
+		"[PSCustomObject]@{Sddl = $o.nTSecurityDescriptor.GetSecurityDescriptorSddlForm('Owner, Access'); DomainSID = $d.DomainSID.Value; RootDomainSID = $r}",
+	}
+}
+
+// GetObjectSecurityDescriptor returns the owner and DACL of a directory object. SID aliases are
+// replaced by the SIDs they stand for so ACEs can be compared.
+func GetObjectSecurityDescriptor(conf *config.ProviderConf, dn string) (*sddl.SecurityDescriptor, error) {
+	stdout, err := runObjectACLScript(conf, getObjectSecurityScript(dn), true)
+	if err != nil {
+		return nil, err
+	}
+
+	security := []objectSecurity{}
+	err = json.Unmarshal([]byte(stdout), &security)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, stdout)
+		return nil, fmt.Errorf("failed while unmarshalling object security json document: %s", err)
+	}
+	if len(security) != 1 {
+		return nil, fmt.Errorf("expected the security information of a single object for %q, got %d", dn, len(security))
+	}
+
+	sd, err := sddl.Parse(security[0].SDDL)
+	if err != nil {
+		return nil, err
+	}
+	sd.NormalizeTrustees(security[0].DomainSID, security[0].RootDomainSID)
+	return sd, nil
+}
+
+// SetObjectDACL replaces the DACL of a directory object. The owner, group and SACL are left untouched.
+func SetObjectDACL(conf *config.ProviderConf, dn string, sd *sddl.SecurityDescriptor) error {
+	dacl := &sddl.SecurityDescriptor{HasDACL: true, DACLFlags: sd.DACLFlags, DACL: sd.DACL}
+	script := []string{
+		fmt.Sprintf(`$o = Get-ADObject -Identity "%s" -Properties nTSecurityDescriptor;`, SanitiseString(dn)),
+		"$s = $o.nTSecurityDescriptor;",
+		fmt.Sprintf(`$s.SetSecurityDescriptorSddlForm("%s", 'Access');`, SanitiseString(dacl.String())),
+		fmt.Sprintf(`Set-ADObject -Identity "%s" -Replace @{nTSecurityDescriptor = $s}`, SanitiseString(dn)),
+	}
+	_, err := runObjectACLScript(conf, script, false)
+	return err
+}
+
+// ModifyObjectACEs removes and adds explicit ACEs on a directory object. The DACL is only
+// written back if it changed.
+func ModifyObjectACEs(conf *config.ProviderConf, dn string, add, remove []sddl.ACE) error {
+	sd, err := GetObjectSecurityDescriptor(conf, dn)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, ace := range remove {
+		if sd.RemoveACE(ace) > 0 {
+			changed = true
+		}
+	}
+	for _, ace := range add {
+		if !sd.ContainsExplicitACE(ace) {
+			sd.AddACE(ace)
+			changed = true
+		}
+	}
+	if !changed {
+		log.Printf("[DEBUG] DACL of %q already up to date", dn)
+		return nil
+	}
+
+	return SetObjectDACL(conf, dn, sd)
+}
+
+// getResolvePrincipalsScript returns a script that outputs the SID of every principal, in order.
+// Principals can be distinguished names or account names, e.g. CONTOSO\helpdesk.
+func getResolvePrincipalsScript(principals []string) []string {
+	exprs := []string{}
+	for _, p := range principals {
+		if strings.Contains(p, "=") {
+			exprs = append(exprs, fmt.Sprintf(`(Get-ADObject -Identity "%s" -Properties objectSid).objectSid.Value`, SanitiseString(p)))
+		} else {
+			exprs = append(exprs, fmt.Sprintf(`(New-Object System.Security.Principal.NTAccount("%s")).Translate([System.Security.Principal.SecurityIdentifier]).Value`, SanitiseString(p)))
+		}
+	}
+	return []string{fmt.Sprintf("@(%s)", strings.Join(exprs, ", "))}
+}
+
+// ResolvePrincipalSIDs returns a map of principal to SID. SIDs are returned as is and
+// don't need a round trip to the host.
+func ResolvePrincipalSIDs(conf *config.ProviderConf, principals []string) (map[string]string, error) {
+	sids := map[string]string{}
+	toResolve := []string{}
+	for _, p := range principals {
+		if sddl.IsSID(p) {
+			sids[p] = strings.ToUpper(p)
+		} else if _, ok := sids[p]; !ok {
+			sids[p] = ""
+			toResolve = append(toResolve, p)
+		}
+	}
+	if len(toResolve) == 0 {
+		return sids, nil
+	}
+
+	stdout, err := runObjectACLScript(conf, getResolvePrincipalsScript(toResolve), true)
+	if err != nil {
+		return nil, fmt.Errorf("while resolving principals %v: %s", toResolve, err)
+	}
+	resolved, err := unmarshallResolvedValues(stdout, toResolve)
+	if err != nil {
+		return nil, err
+	}
+	for idx, p := range toResolve {
+		if !sddl.IsSID(resolved[idx]) {
+			return nil, fmt.Errorf("principal %q could not be resolved to a SID", p)
+		}
+		sids[p] = strings.ToUpper(resolved[idx])
+	}
+	return sids, nil
+}
+
+// getResolveSIDNamesScript returns a script that outputs the account name of every SID, in order.
+// SIDs that can't be translated, e.g. the ones of deleted accounts, are returned as empty strings.
+func getResolveSIDNamesScript(sids []string) []string {
+	script := []string{
+		"function Resolve-SIDName($sid) {",
+		"try { return ([System.Security.Principal.SecurityIdentifier]$sid).Translate([System.Security.Principal.NTAccount]).Value }",
+		"catch { return '' } };",
+	}
+	exprs := []string{}
+	for _, sid := range sids {
+		exprs = append(exprs, fmt.Sprintf("(Resolve-SIDName '%s')", strings.ReplaceAll(sid, "'", "''")))
+	}
+	return append(script, fmt.Sprintf("@(%s)", strings.Join(exprs, ", ")))
+}
+
+// ResolveSIDNames returns a map of SID to account name. SIDs that can't be translated are
+// mapped to themselves.
+func ResolveSIDNames(conf *config.ProviderConf, sids []string) (map[string]string, error) {
+	names := map[string]string{}
+	toResolve := []string{}
+	for _, sid := range sids {
+		if _, ok := names[sid]; !ok {
+			names[sid] = sid
+			toResolve = append(toResolve, sid)
+		}
+	}
+	if len(toResolve) == 0 {
+		return names, nil
+	}
+
+	stdout, err := runObjectACLScript(conf, getResolveSIDNamesScript(toResolve), true)
+	if err != nil {
+		return nil, fmt.Errorf("while resolving SIDs %v: %s", toResolve, err)
+	}
+	resolved, err := unmarshallResolvedValues(stdout, toResolve)
+	if err != nil {
+		return nil, err
+	}
+	for idx, sid := range toResolve {
+		if resolved[idx] != "" {
+			names[sid] = resolved[idx]
+		}
+	}
+	return names, nil
+}
+
+// getResolveSchemaGUIDsScript returns a script that outputs the GUID of every schema object
+// or extended right name, in order. Names that are not found are returned as empty strings.
+func getResolveSchemaGUIDsScript(names []string) []string {
+	script := []string{
+		"$rootDSE = Get-ADRootDSE;",
+		"function Resolve-SchemaGUID($filter) {",
+		"$s = Get-ADObject -SearchBase $rootDSE.schemaNamingContext -LDAPFilter $filter -Properties schemaIDGUID | Select-Object -First 1;",
+		"if ($s) { return ([guid]$s.schemaIDGUID).ToString() };",
+		`$e = Get-ADObject -SearchBase "CN=Extended-Rights,$($rootDSE.configurationNamingContext)" -LDAPFilter $filter -Properties rightsGuid | Select-Object -First 1;`,
+		"if ($e) { return $e.rightsGuid };",
+		"return '' };",
+	}
+	exprs := []string{}
+	for _, n := range names {
+		value := strings.ReplaceAll(escapeLDAPFilterValue(n), "'", "''")
+		exprs = append(exprs, fmt.Sprintf("(Resolve-SchemaGUID '(|(lDAPDisplayName=%[1]s)(cn=%[1]s)(name=%[1]s)(displayName=%[1]s))')", value))
+	}
+	return append(script, fmt.Sprintf("@(%s)", strings.Join(exprs, ", ")))
+}
+
+// ResolveSchemaGUIDs returns a map of schema object or extended right name to GUID. Names of
+// the base schema and GUIDs are resolved without a round trip to the host.
+func ResolveSchemaGUIDs(conf *config.ProviderConf, names []string) (map[string]string, error) {
+	guids := map[string]string{}
+	toResolve := []string{}
+	for _, n := range names {
+		if guid, ok := sddl.LookupSchemaGUID(n); ok {
+			guids[n] = guid
+		} else if _, ok := guids[n]; !ok {
+			guids[n] = ""
+			toResolve = append(toResolve, n)
+		}
+	}
+	if len(toResolve) == 0 {
+		return guids, nil
+	}
+
+	stdout, err := runObjectACLScript(conf, getResolveSchemaGUIDsScript(toResolve), true)
+	if err != nil {
+		return nil, fmt.Errorf("while resolving schema names %v: %s", toResolve, err)
+	}
+	resolved, err := unmarshallResolvedValues(stdout, toResolve)
+	if err != nil {
+		return nil, err
+	}
+	for idx, n := range toResolve {
+		if !sddl.IsGUID(resolved[idx]) {
+			return nil, fmt.Errorf("%q is not a schema class, attribute, property set or extended right", n)
+		}
+		guids[n] = strings.ToLower(resolved[idx])
+	}
+	return guids, nil
+}
+
+func unmarshallResolvedValues(stdout string, expected []string) ([]string, error) {
+	values := []string{}
+	err := json.Unmarshal([]byte(stdout), &values)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, stdout)
+		return nil, fmt.Errorf("failed while unmarshalling resolved values json document: %s", err)
+	}
+	if len(values) != len(expected) {
+		return nil, fmt.Errorf("expected %d resolved values for %v, got %d", len(expected), expected, len(values))
+	}
+	return values, nil
+}
+
+// ResolveObjectACEs converts configured ACEs to SDDL ACEs, resolving all principals and
+// schema names with at most one round trip to the host for each.
+func ResolveObjectACEs(conf *config.ProviderConf, aces []ObjectACE) ([]sddl.ACE, error) {
+	principals := []string{}
+	names := []string{}
+	for _, a := range aces {
+		principals = append(principals, a.Principal)
+		names = append(names, a.ObjectType, a.InheritedObjectType)
+	}
+
+	sids, err := ResolvePrincipalSIDs(conf, principals)
+	if err != nil {
+		return nil, err
+	}
+	guids, err := ResolveSchemaGUIDs(conf, names)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []sddl.ACE{}
+	for _, a := range aces {
+		ace, err := a.toSDDL(sids[a.Principal], guids[a.ObjectType], guids[a.InheritedObjectType])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *ace)
+	}
+	return result, nil
+}
+
+func (a ObjectACE) toSDDL(sid, objectTypeGUID, inheritedObjectTypeGUID string) (*sddl.ACE, error) {
+	mask, err := sddl.RightsToMask(a.Rights)
+	if err != nil {
+		return nil, err
+	}
+	if mask == 0 {
+		return nil, fmt.Errorf("the ACE for %q does not grant or deny any right", a.Principal)
+	}
+	ace, err := sddl.NewObjectACE(strings.EqualFold(a.AccessType, "Deny"), mask, objectTypeGUID, inheritedObjectTypeGUID, sid, a.Inheritance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACE for %q: %s", a.Principal, err)
+	}
+	return ace, nil
+}
+
+// NewObjectACEFromSDDL describes an SDDL ACE as an ObjectACE, with the principal as a SID
+// and the object types as GUIDs
+func NewObjectACEFromSDDL(ace sddl.ACE) (*ObjectACE, error) {
+	if ace.Raw != "" {
+		return nil, fmt.Errorf("ACE %s is not supported", ace.Raw)
+	}
+	if ace.Type != sddl.ACETypeAccessAllowed && ace.Type != sddl.ACETypeAccessDenied &&
+		ace.Type != sddl.ACETypeObjectAccessAllowed && ace.Type != sddl.ACETypeObjectAccessDenied {
+		return nil, fmt.Errorf("ACE %s is not an access allowed or access denied ACE", ace.String())
+	}
+	inheritance, err := sddl.FlagsToInheritance(ace.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	accessType := "Allow"
+	if ace.IsDeny() {
+		accessType = "Deny"
+	}
+	return &ObjectACE{
+		Principal:           ace.Trustee,
+		AccessType:          accessType,
+		Rights:              sddl.MaskToRights(ace.Mask),
+		ObjectType:          ace.ObjectType,
+		InheritedObjectType: ace.InheritedObjectType,
+		Inheritance:         inheritance,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package winrmhelper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/sddl"
+)
+
+func TestObjectACEToSDDL(t *testing.T) {
+	tests := []struct {
+		ace      ObjectACE
+		sid      string
+		objType  string
+		inhType  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			ace:      ObjectACE{Principal: "CONTOSO\\helpdesk", AccessType: "Allow", Rights: []string{"ExtendedRight"}, Inheritance: "Descendents"},
+			sid:      "S-1-5-21-1-2-3-1105",
+			objType:  "00299570-246d-11d0-a768-00aa006e0529",
+			inhType:  "bf967aba-0de6-11d0-a285-00aa003049e2",
+			expected: "(OA;CIIO;CR;00299570-246d-11d0-a768-00aa006e0529;bf967aba-0de6-11d0-a285-00aa003049e2;S-1-5-21-1-2-3-1105)",
+		},
+		{
+			ace:      ObjectACE{Principal: "S-1-5-11", AccessType: "Deny", Rights: []string{"WriteProperty", "ReadProperty"}, Inheritance: "None"},
+			sid:      "S-1-5-11",
+			expected: "(D;;RPWP;;;S-1-5-11)",
+		},
+		{
+			ace:     ObjectACE{Principal: "S-1-5-11", AccessType: "Allow", Rights: []string{}, Inheritance: "None"},
+			sid:     "S-1-5-11",
+			wantErr: true,
+		},
+		{
+			ace:     ObjectACE{Principal: "S-1-5-11", AccessType: "Allow", Rights: []string{"FullControl"}, Inheritance: "None"},
+			sid:     "S-1-5-11",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		ace, err := tt.ace.toSDDL(tt.sid, tt.objType, tt.inhType)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toSDDL(%+v) error = %v, wantErr %t", tt.ace, err, tt.wantErr)
+			continue
+		}
+		if err == nil && ace.String() != tt.expected {
+			t.Errorf("toSDDL(%+v) = %q, want %q", tt.ace, ace.String(), tt.expected)
+		}
+	}
+}
+
+func TestNewObjectACEFromSDDL(t *testing.T) {
+	ace, err := sddl.ParseACE("(OA;CIIO;RPWP;bf9679c0-0de6-11d0-a285-00aa003049e2;bf967a9c-0de6-11d0-a285-00aa003049e2;S-1-5-21-1-2-3-1105)")
+	if err != nil {
+		t.Fatalf("ParseACE() unexpected error: %s", err)
+	}
+
+	objectACE, err := NewObjectACEFromSDDL(*ace)
+	if err != nil {
+		t.Fatalf("NewObjectACEFromSDDL() unexpected error: %s", err)
+	}
+	expected := &ObjectACE{
+		Principal:           "S-1-5-21-1-2-3-1105",
+		AccessType:          "Allow",
+		Rights:              []string{"ReadProperty", "WriteProperty"},
+		ObjectType:          "bf9679c0-0de6-11d0-a285-00aa003049e2",
+		InheritedObjectType: "bf967a9c-0de6-11d0-a285-00aa003049e2",
+		Inheritance:         "Descendents",
+	}
+	if !reflect.DeepEqual(objectACE, expected) {
+		t.Errorf("NewObjectACEFromSDDL() = %+v, want %+v", objectACE, expected)
+	}
+
+	audit, _ := sddl.ParseACE("(AU;SA;WP;;;WD)")
+	if _, err := NewObjectACEFromSDDL(*audit); err == nil {
+		t.Error("NewObjectACEFromSDDL() expected an error for an audit ACE")
+	}
+}
+
+func TestGetResolvePrincipalsScript(t *testing.T) {
+	script := strings.Join(getResolvePrincipalsScript([]string{"CN=helpdesk,OU=Groups,DC=contoso,DC=com", `CONTOSO\svc$`}), " ")
+	expected := `@((Get-ADObject -Identity "CN=helpdesk,OU=Groups,DC=contoso,DC=com" -Properties objectSid).objectSid.Value, ` +
+		"(New-Object System.Security.Principal.NTAccount(\"CONTOSO\\svc`$\")).Translate([System.Security.Principal.SecurityIdentifier]).Value)"
+	if script != expected {
+		t.Errorf("getResolvePrincipalsScript() = %q, want %q", script, expected)
+	}
+}
+
+func TestGetResolveSIDNamesScript(t *testing.T) {
+	script := strings.Join(getResolveSIDNamesScript([]string{"S-1-5-32-544", "S-1-5-21-1-2-3-1105"}), " ")
+	expected := "@((Resolve-SIDName 'S-1-5-32-544'), (Resolve-SIDName 'S-1-5-21-1-2-3-1105'))"
+	if !strings.HasSuffix(script, expected) {
+		t.Errorf("getResolveSIDNamesScript() = %q, expected it to end with %q", script, expected)
+	}
+}
+
+func TestGetResolveSchemaGUIDsScript(t *testing.T) {
+	script := strings.Join(getResolveSchemaGUIDsScript([]string{"ms-Mcs-AdmPwd", "O'Brien*"}), " ")
+	for _, expected := range []string{
+		"(Resolve-SchemaGUID '(|(lDAPDisplayName=ms-Mcs-AdmPwd)(cn=ms-Mcs-AdmPwd)(name=ms-Mcs-AdmPwd)(displayName=ms-Mcs-AdmPwd))')",
+		`(lDAPDisplayName=O''Brien\2a)`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("getResolveSchemaGUIDsScript() = %q, expected it to contain %q", script, expected)
+		}
+	}
+}
+
+func TestUnmarshallResolvedValues(t *testing.T) {
+	values, err := unmarshallResolvedValues(`["S-1-5-21-1-2-3-1105"]`, []string{"helpdesk"})
+	if err != nil {
+		t.Fatalf("unmarshallResolvedValues() unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(values, []string{"S-1-5-21-1-2-3-1105"}) {
+		t.Errorf("unmarshallResolvedValues() = %v", values)
+	}
+
+	if _, err := unmarshallResolvedValues(`["a"]`, []string{"a", "b"}); err == nil {
+		t.Error("unmarshallResolvedValues() expected an error when values are missing")
+	}
+}
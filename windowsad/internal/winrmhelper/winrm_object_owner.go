@@ -0,0 +1,40 @@
+package winrmhelper
+
+import (
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/sddl"
+)
+
+// getSetObjectOwnerScript returns the script that changes the owner of a directory object
+func getSetObjectOwnerScript(dn, ownerSID string) []string {
+	return []string{
+		fmt.Sprintf(`$o = Get-ADObject -Identity "%s" -Properties nTSecurityDescriptor;`, SanitiseString(dn)),
+		"$s = $o.nTSecurityDescriptor;",
+		fmt.Sprintf(`$s.SetOwner([System.Security.Principal.SecurityIdentifier]"%s");`, SanitiseString(ownerSID)),
+		fmt.Sprintf(`Set-ADObject -Identity "%s" -Replace @{nTSecurityDescriptor = $s}`, SanitiseString(dn)),
+	}
+}
+
+// GetObjectOwner returns the SID of the owner of a directory object
+func GetObjectOwner(conf *config.ProviderConf, dn string) (string, error) {
+	sd, err := GetObjectSecurityDescriptor(conf, dn)
+	if err != nil {
+		return "", err
+	}
+	if !sddl.IsSID(sd.Owner) {
+		return "", fmt.Errorf("the owner %q of %q could not be resolved to a SID", sd.Owner, dn)
+	}
+	return sd.Owner, nil
+}
+
+// SetObjectOwner changes the owner of a directory object. Setting an owner other than the
+// account terraform connects with requires the restore privilege, which domain admins have.
+func SetObjectOwner(conf *config.ProviderConf, dn, ownerSID string) error {
+	if !sddl.IsSID(ownerSID) {
+		return fmt.Errorf("%q is not a SID", ownerSID)
+	}
+	_, err := runObjectACLScript(conf, getSetObjectOwnerScript(dn, ownerSID), false)
+	return err
+}
@@ -0,0 +1,17 @@
+package winrmhelper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetSetObjectOwnerScript(t *testing.T) {
+	script := strings.Join(getSetObjectOwnerScript("CN=PC01,OU=Computers,DC=contoso,DC=com", "S-1-5-21-1-2-3-512"), " ")
+	expected := `$o = Get-ADObject -Identity "CN=PC01,OU=Computers,DC=contoso,DC=com" -Properties nTSecurityDescriptor; ` +
+		`$s = $o.nTSecurityDescriptor; ` +
+		`$s.SetOwner([System.Security.Principal.SecurityIdentifier]"S-1-5-21-1-2-3-512"); ` +
+		`Set-ADObject -Identity "CN=PC01,OU=Computers,DC=contoso,DC=com" -Replace @{nTSecurityDescriptor = $s}`
+	if script != expected {
+		t.Errorf("getSetObjectOwnerScript() = %q, want %q", script, expected)
+	}
+}
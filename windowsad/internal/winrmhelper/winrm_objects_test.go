@@ -2,6 +2,7 @@ package winrmhelper
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -99,6 +100,83 @@ func TestGroup_CategoryNumbers(t *testing.T) {
 	}
 }
 
+func TestGroupScopeTransitions(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected []string
+	}{
+		{"global", "global", []string{}},
+		{"global", "universal", []string{"universal"}},
+		{"universal", "global", []string{"global"}},
+		{"universal", "domainlocal", []string{"domainlocal"}},
+		{"domainlocal", "universal", []string{"universal"}},
+		{"global", "domainlocal", []string{"universal", "domainlocal"}},
+		{"domainlocal", "global", []string{"universal", "global"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			got := groupScopeTransitions(tt.from, tt.to)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("groupScopeTransitions(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGroup_GetOtherAttributes(t *testing.T) {
+	g := &Group{}
+	if attrs := g.getOtherAttributes(); attrs != "" {
+		t.Errorf("getOtherAttributes() = %q, want empty string", attrs)
+	}
+
+	g.Info = "some notes"
+	g.Mail = "group@example.com"
+	expected := `@{'info'="some notes";'mail'="group@example.com"}`
+	if attrs := g.getOtherAttributes(); attrs != expected {
+		t.Errorf("getOtherAttributes() = %q, want %q", attrs, expected)
+	}
+}
+
+func TestGroup_JSONUnmarshal_ExtendedAttributes(t *testing.T) {
+	jsonData := `{
+		"ObjectGUID": "group-guid-123",
+		"DistinguishedName": "CN=Test Group,OU=Groups,DC=example,DC=com",
+		"DisplayName": "Test Group Display",
+		"ManagedBy": "CN=jdoe,OU=Users,DC=example,DC=com",
+		"info": "Some notes",
+		"mail": "group@example.com",
+		"ProtectedFromAccidentalDeletion": true,
+		"extensionAttribute1": "custom1"
+	}`
+
+	group, err := unmarshallGroup([]byte(jsonData), []string{"extensionAttribute1"})
+	if err != nil {
+		t.Fatalf("unmarshallGroup error: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		got      interface{}
+		expected interface{}
+	}{
+		{"DisplayName", group.DisplayName, "Test Group Display"},
+		{"ManagedBy", group.ManagedBy, "CN=jdoe,OU=Users,DC=example,DC=com"},
+		{"Info", group.Info, "Some notes"},
+		{"Mail", group.Mail, "group@example.com"},
+		{"Protected", group.Protected, true},
+		{"Container", group.Container, "OU=Groups,DC=example,DC=com"},
+		{"CustomAttributes[extensionAttribute1]", group.CustomAttributes["extensionAttribute1"], "custom1"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("Group.%s = %v, want %v", tt.field, tt.got, tt.expected)
+		}
+	}
+}
+
 // OrgUnit struct tests
 
 func TestOrgUnit_JSONUnmarshal(t *testing.T) {
@@ -196,6 +274,66 @@ func TestComputer_JSONUnmarshal(t *testing.T) {
 	}
 }
 
+func TestComputer_JSONUnmarshal_ExtendedAttributes(t *testing.T) {
+	jsonData := `{
+		"ObjectGuid": "computer-guid-789",
+		"Name": "SERVER01",
+		"DistinguishedName": "CN=SERVER01,OU=Servers,DC=example,DC=com",
+		"Enabled": true,
+		"DNSHostName": "server01.example.com",
+		"Location": "Building 1",
+		"ManagedBy": "CN=Server Admins,OU=Groups,DC=example,DC=com",
+		"ServicePrincipalNames": ["HOST/server01", "HOST/server01.example.com"],
+		"OperatingSystem": "Windows Server 2022 Standard",
+		"OperatingSystemVersion": "10.0 (20348)",
+		"TrustedForDelegation": true,
+		"ProtectedFromAccidentalDeletion": true,
+		"extensionAttribute1": "custom1"
+	}`
+
+	computer, err := unmarshallComputer([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("unmarshallComputer error: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		got      interface{}
+		expected interface{}
+	}{
+		{"Enabled", computer.Enabled, true},
+		{"DNSHostName", computer.DNSHostName, "server01.example.com"},
+		{"Location", computer.Location, "Building 1"},
+		{"ManagedBy", computer.ManagedBy, "CN=Server Admins,OU=Groups,DC=example,DC=com"},
+		{"OperatingSystem", computer.OperatingSystem, "Windows Server 2022 Standard"},
+		{"OperatingSystemVersion", computer.OperatingSystemVersion, "10.0 (20348)"},
+		{"TrustedForDelegation", computer.TrustedForDelegation, true},
+		{"Protected", computer.Protected, true},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("Computer.%s = %v, want %v", tt.field, tt.got, tt.expected)
+		}
+	}
+
+	expectedSPNs := []string{"HOST/server01", "HOST/server01.example.com"}
+	if !reflect.DeepEqual(computer.ServicePrincipalNames, expectedSPNs) {
+		t.Errorf("Computer.ServicePrincipalNames = %v, want %v", computer.ServicePrincipalNames, expectedSPNs)
+	}
+	if computer.CustomAttributes != nil {
+		t.Errorf("Computer.CustomAttributes should only be populated on request, got %v", computer.CustomAttributes)
+	}
+}
+
+func TestGetServicePrincipalNamesList(t *testing.T) {
+	expected := `"HOST/server01","HOST/server01.example.com"`
+	got := getServicePrincipalNamesList([]string{"HOST/server01", "HOST/server01.example.com"})
+	if got != expected {
+		t.Errorf("getServicePrincipalNamesList() = %s, want %s", got, expected)
+	}
+}
+
 func TestComputer_EmptyJSON(t *testing.T) {
 	var computer Computer
 	err := json.Unmarshal([]byte("{}"), &computer)
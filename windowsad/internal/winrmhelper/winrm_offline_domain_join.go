@@ -0,0 +1,80 @@
+package winrmhelper
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+)
+
+// OfflineDomainJoin holds the parameters used to provision an offline domain join blob with djoin.exe
+type OfflineDomainJoin struct {
+	Domain      string
+	MachineName string
+	MachineOU   string
+	DCName      string
+}
+
+// getProvisionCmd returns the powershell script that runs djoin /provision and prints the resulting blob.
+// djoin.exe writes the blob to a file, so we use a random temporary file and remove it once it's been read.
+func (o *OfflineDomainJoin) getProvisionCmd() string {
+	cmds := []string{"$odjFile = Join-Path $env:TEMP ([System.IO.Path]::GetRandomFileName())"}
+	if o.Domain == "" {
+		cmds = append(cmds, "$odjDomain = (Get-ADDomain).DNSRoot")
+	} else {
+		cmds = append(cmds, fmt.Sprintf(`$odjDomain = "%s"`, o.Domain))
+	}
+
+	djoinCmd := fmt.Sprintf(`$djoinOutput = djoin.exe /provision /domain $odjDomain /machine "%s" /reuse`, o.MachineName)
+	if o.MachineOU != "" {
+		djoinCmd = fmt.Sprintf(`%s /machineou "%s"`, djoinCmd, o.MachineOU)
+	}
+	if o.DCName != "" {
+		djoinCmd = fmt.Sprintf(`%s /dcname "%s"`, djoinCmd, o.DCName)
+	}
+	djoinCmd = fmt.Sprintf("%s /savefile $odjFile", djoinCmd)
+	cmds = append(cmds, djoinCmd)
+
+	cmds = append(cmds,
+		"if ($LASTEXITCODE -ne 0) { Write-Error ($djoinOutput -join [Environment]::NewLine); exit $LASTEXITCODE }",
+		"$odjBlob = [System.IO.File]::ReadAllText($odjFile).TrimEnd([char]0)",
+		"Remove-Item $odjFile -Force",
+		"$odjBlob",
+	)
+	return strings.Join(cmds, "; ")
+}
+
+// Provision runs djoin.exe on the WinRM host and returns the offline domain join blob.
+// The computer account is reused if it exists already, in which case its password is reset.
+func (o *OfflineDomainJoin) Provision(conf *config.ProviderConf) (string, error) {
+	if o.MachineName == "" {
+		return "", fmt.Errorf("OfflineDomainJoin.Provision: missing machine name")
+	}
+
+	log.Printf("[DEBUG] Provisioning offline domain join blob for machine %q", o.MachineName)
+	// djoin.exe does not accept a credential object, so the command always runs as the WinRM user.
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: false,
+		SkipCredPrefix:  true,
+		SkipCredSuffix:  true,
+	}
+	psCmd := NewPSCommand([]string{o.getProvisionCmd()}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("winrm execution failure while provisioning offline domain join blob: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("djoin.exe exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	blob := strings.TrimSpace(result.Stdout)
+	if blob == "" {
+		return "", fmt.Errorf("djoin.exe did not return an offline domain join blob for machine %q", o.MachineName)
+	}
+
+	return blob, nil
+}
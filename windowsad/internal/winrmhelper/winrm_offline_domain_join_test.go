@@ -0,0 +1,53 @@
+package winrmhelper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOfflineDomainJoin_GetProvisionCmd(t *testing.T) {
+	tests := []struct {
+		name       string
+		odj        OfflineDomainJoin
+		contains   []string
+		notContain []string
+	}{
+		{
+			name:       "defaults",
+			odj:        OfflineDomainJoin{MachineName: "web01"},
+			contains:   []string{"$odjDomain = (Get-ADDomain).DNSRoot", `/machine "web01" /reuse`, "/savefile $odjFile"},
+			notContain: []string{"/machineou", "/dcname"},
+		},
+		{
+			name: "all options",
+			odj: OfflineDomainJoin{
+				Domain:      "example.com",
+				MachineName: "web01",
+				MachineOU:   "OU=Servers,DC=example,DC=com",
+				DCName:      "dc1.example.com",
+			},
+			contains: []string{
+				`$odjDomain = "example.com"`,
+				`/machineou "OU=Servers,DC=example,DC=com"`,
+				`/dcname "dc1.example.com"`,
+			},
+			notContain: []string{"Get-ADDomain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := tt.odj.getProvisionCmd()
+			for _, s := range tt.contains {
+				if !strings.Contains(cmd, s) {
+					t.Errorf("getProvisionCmd() = %q, expected it to contain %q", cmd, s)
+				}
+			}
+			for _, s := range tt.notContain {
+				if strings.Contains(cmd, s) {
+					t.Errorf("getProvisionCmd() = %q, expected it not to contain %q", cmd, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var timeSpanRegexp = regexp.MustCompile(`^(?:(\d+)\.)?(\d{1,2}):(\d{2}):(\d{2})$`)
+
+// ParseTimeSpan parses a string using the .NET TimeSpan format ([d.]hh:mm:ss) into a time.Duration
+func ParseTimeSpan(value string) (time.Duration, error) {
+	matches := timeSpanRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, fmt.Errorf("%q is not a valid time span, expected [d.]hh:mm:ss", value)
+	}
+
+	parts := make([]int, 4)
+	for idx, m := range matches[1:] {
+		if m == "" {
+			continue
+		}
+		v, err := strconv.Atoi(m)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a valid time span: %s", value, err)
+		}
+		parts[idx] = v
+	}
+	if parts[1] > 23 || parts[2] > 59 || parts[3] > 59 {
+		return 0, fmt.Errorf("%q is not a valid time span, hours, minutes or seconds are out of range", value)
+	}
+
+	return time.Duration(parts[0])*24*time.Hour +
+		time.Duration(parts[1])*time.Hour +
+		time.Duration(parts[2])*time.Minute +
+		time.Duration(parts[3])*time.Second, nil
+}
+
+// PasswordSettingsObject represents a fine-grained password policy
+type PasswordSettingsObject struct {
+	GUID                        string `json:"ObjectGUID"`
+	Name                        string `json:"Name"`
+	DN                          string `json:"DistinguishedName"`
+	DisplayName                 string
+	Description                 string
+	Precedence                  int
+	ComplexityEnabled           bool
+	MinPasswordLength           int
+	PasswordHistoryCount        int
+	MinPasswordAge              string
+	MaxPasswordAge              string
+	LockoutThreshold            int
+	LockoutDuration             string
+	LockoutObservationWindow    string
+	ReversibleEncryptionEnabled bool
+	ProtectedFromDeletion       bool     `json:"ProtectedFromAccidentalDeletion"`
+	AppliesTo                   []string `json:"AppliesTo"`
+}
+
+// passwordPolicyTimeSpanSelect formats the TimeSpan properties of a password policy as strings
+const passwordPolicyTimeSpanSelect = "@{Name='MinPasswordAge';Expression={$_.MinPasswordAge.ToString()}}, " +
+	"@{Name='MaxPasswordAge';Expression={$_.MaxPasswordAge.ToString()}}, " +
+	"@{Name='LockoutDuration';Expression={$_.LockoutDuration.ToString()}}, " +
+	"@{Name='LockoutObservationWindow';Expression={$_.LockoutObservationWindow.ToString()}}"
+
+// psoSelect selects the properties of a fine-grained password policy
+const psoSelect = "Select-Object ObjectGUID, Name, DistinguishedName, DisplayName, Description, Precedence, " +
+	"ComplexityEnabled, MinPasswordLength, PasswordHistoryCount, LockoutThreshold, ReversibleEncryptionEnabled, " +
+	"ProtectedFromAccidentalDeletion, @{Name='AppliesTo';Expression={@($_.AppliesTo)}}, " + passwordPolicyTimeSpanSelect
+
+// psoStrKeyMap maps resource attributes to the string parameters of the *-ADFineGrainedPasswordPolicy cmdlets
+var psoStrKeyMap = map[string]string{
+	"display_name": "DisplayName",
+	"description":  "Description",
+}
+
+// psoTimeSpanKeyMap maps resource attributes to the TimeSpan parameters of the *-ADFineGrainedPasswordPolicy cmdlets
+var psoTimeSpanKeyMap = map[string]string{
+	"min_password_age":           "MinPasswordAge",
+	"max_password_age":           "MaxPasswordAge",
+	"lockout_duration":           "LockoutDuration",
+	"lockout_observation_window": "LockoutObservationWindow",
+}
+
+// psoIntKeyMap maps resource attributes to the integer parameters of the *-ADFineGrainedPasswordPolicy cmdlets
+var psoIntKeyMap = map[string]string{
+	"precedence":             "Precedence",
+	"min_password_length":    "MinPasswordLength",
+	"password_history_count": "PasswordHistoryCount",
+	"lockout_threshold":      "LockoutThreshold",
+}
+
+// psoBoolKeyMap maps resource attributes to the boolean parameters of the *-ADFineGrainedPasswordPolicy cmdlets
+var psoBoolKeyMap = map[string]string{
+	"complexity_enabled":                 "ComplexityEnabled",
+	"reversible_encryption_enabled":      "ReversibleEncryptionEnabled",
+	"protected_from_accidental_deletion": "ProtectedFromAccidentalDeletion",
+}
+
+// getPSOParams returns the parameters for the given attributes of the resource. It is also used for
+// Set-ADDefaultDomainPasswordPolicy, which shares the parameter names of the fine-grained policy cmdlets.
+func getPSOParams(d *schema.ResourceData, keys []string) []string {
+	params := []string{}
+	for _, k := range keys {
+		if param, ok := psoStrKeyMap[k]; ok {
+			value := SanitiseTFInput(d, k)
+			if value == "" {
+				params = append(params, fmt.Sprintf("-%s $null", param))
+			} else {
+				params = append(params, fmt.Sprintf(`-%s "%s"`, param, value))
+			}
+		} else if param, ok := psoTimeSpanKeyMap[k]; ok {
+			params = append(params, fmt.Sprintf(`-%s "%s"`, param, SanitiseTFInput(d, k)))
+		} else if param, ok := psoIntKeyMap[k]; ok {
+			params = append(params, fmt.Sprintf("-%s %d", param, d.Get(k).(int)))
+		} else if param, ok := psoBoolKeyMap[k]; ok {
+			params = append(params, fmt.Sprintf("-%s $%t", param, d.Get(k).(bool)))
+		}
+	}
+	return params
+}
+
+// PasswordSettingsObjectKeys returns the resource attributes that map to a *-ADFineGrainedPasswordPolicy parameter
+func PasswordSettingsObjectKeys() []string {
+	keys := []string{}
+	for _, m := range []map[string]string{psoStrKeyMap, psoTimeSpanKeyMap, psoIntKeyMap, psoBoolKeyMap} {
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// NewPasswordSettingsObject creates a fine-grained password policy from resource data and returns its GUID
+func NewPasswordSettingsObject(conf *config.ProviderConf, d *schema.ResourceData) (string, error) {
+	name := SanitiseTFInput(d, "name")
+	keys := []string{}
+	for _, k := range PasswordSettingsObjectKeys() {
+		// Empty strings are left to the cmdlet defaults
+		if _, ok := psoStrKeyMap[k]; ok && SanitiseTFInput(d, k) == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	cmds := []string{fmt.Sprintf("New-ADFineGrainedPasswordPolicy -PassThru -Name %q", name)}
+	cmds = append(cmds, getPSOParams(d, keys)...)
+
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return "", fmt.Errorf("winrm execution failure while creating password settings object: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return "", fmt.Errorf("command New-ADFineGrainedPasswordPolicy exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	pso, err := unmarshallPasswordSettingsObject([]byte(result.Stdout))
+	if err != nil {
+		return "", err
+	}
+	return pso.GUID, nil
+}
+
+// UpdatePasswordSettingsObject applies the changed attributes of the resource to the policy
+func UpdatePasswordSettingsObject(conf *config.ProviderConf, d *schema.ResourceData) error {
+	keys := []string{}
+	for _, k := range PasswordSettingsObjectKeys() {
+		if d.HasChange(k) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cmds := []string{fmt.Sprintf("Set-ADFineGrainedPasswordPolicy -Identity %q", d.Id())}
+	cmds = append(cmds, getPSOParams(d, keys)...)
+	return runPSOCmd(conf, cmds)
+}
+
+// DeletePasswordSettingsObject removes the policy, clearing its protection first
+func DeletePasswordSettingsObject(conf *config.ProviderConf, guid string) error {
+	err := setProtectedFromAccidentalDeletion(conf, guid, false)
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("Remove-ADFineGrainedPasswordPolicy -Identity %q -Confirm:$false", guid)
+	return runPSOCmd(conf, []string{cmd})
+}
+
+// getSubjectChanges returns the subjects that have to be added and removed to go from current to expected.
+// Subjects are compared case insensitively.
+func getSubjectChanges(current, expected []string) ([]string, []string) {
+	toAdd := []string{}
+	toRemove := []string{}
+
+	currentMap := map[string]bool{}
+	for _, s := range current {
+		currentMap[strings.ToLower(s)] = true
+	}
+	expectedMap := map[string]bool{}
+	for _, s := range expected {
+		expectedMap[strings.ToLower(s)] = true
+		if !currentMap[strings.ToLower(s)] {
+			toAdd = append(toAdd, s)
+		}
+	}
+	for _, s := range current {
+		if !expectedMap[strings.ToLower(s)] {
+			toRemove = append(toRemove, s)
+		}
+	}
+	return toAdd, toRemove
+}
+
+// SetPasswordSettingsObjectSubjects makes sure the policy applies to exactly the given subjects
+func SetPasswordSettingsObjectSubjects(conf *config.ProviderConf, guid string, current, expected []string) error {
+	toAdd, toRemove := getSubjectChanges(current, expected)
+	if len(toAdd) > 0 {
+		cmd := fmt.Sprintf("Add-ADFineGrainedPasswordPolicySubject -Identity %q -Subjects %s", guid, getPrincipalsList(toAdd))
+		err := runPSOCmd(conf, []string{cmd})
+		if err != nil {
+			return err
+		}
+	}
+	if len(toRemove) > 0 {
+		cmd := fmt.Sprintf("Remove-ADFineGrainedPasswordPolicySubject -Identity %q -Subjects %s -Confirm:$false", guid, getPrincipalsList(toRemove))
+		err := runPSOCmd(conf, []string{cmd})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runPSOCmd(conf *config.ProviderConf, cmds []string) error {
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand(cmds, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while modifying password settings object: %s", err)
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return fmt.Errorf("command %s exited with a non-zero exit code %d, stderr: %s", strings.Fields(cmds[0])[0], result.ExitCode, result.StdErr)
+	}
+	return nil
+}
+
+// GetPasswordSettingsObjectFromHost returns a PasswordSettingsObject struct based on data
+// retrieved from the AD Domain Controller.
+func GetPasswordSettingsObjectFromHost(conf *config.ProviderConf, guid string) (*PasswordSettingsObject, error) {
+	cmd := fmt.Sprintf("Get-ADFineGrainedPasswordPolicy -Identity %q -Properties * | %s", guid, psoSelect)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+		// the pipeline to Select-Object means -Credential and -Server can't be appended at the end
+		InvokeCommand: conf.IsPassCredentialsEnabled(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return nil, fmt.Errorf("command Get-ADFineGrainedPasswordPolicy exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	return unmarshallPasswordSettingsObject([]byte(result.Stdout))
+}
+
+func unmarshallPasswordSettingsObject(input []byte) (*PasswordSettingsObject, error) {
+	var pso PasswordSettingsObject
+	err := json.Unmarshal(input, &pso)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, string(input))
+		return nil, fmt.Errorf("failed while unmarshalling password settings object json document: %s", err)
+	}
+	if pso.GUID == "" {
+		return nil, fmt.Errorf("invalid data while unmarshalling PasswordSettingsObject data, json doc was: %s", string(input))
+	}
+	return &pso, nil
+}
+
+// GetUserResultantPasswordSettingsObject returns the fine-grained password policy that applies to the
+// given user, or nil if the user falls under the default domain password policy.
+func GetUserResultantPasswordSettingsObject(conf *config.ProviderConf, identity string) (*PasswordSettingsObject, error) {
+	cmd := fmt.Sprintf("Get-ADUserResultantPasswordPolicy -Identity %q | %s", identity, psoSelect)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+		InvokeCommand:   conf.IsPassCredentialsEnabled(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, err
+	}
+	if result.ExitCode != 0 {
+		log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+		return nil, fmt.Errorf("command Get-ADUserResultantPasswordPolicy exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	// the cmdlet returns nothing when no fine-grained password policy applies to the user
+	if strings.TrimSpace(result.Stdout) == "" {
+		return nil, nil
+	}
+	return unmarshallPasswordSettingsObject([]byte(result.Stdout))
+}
+
+// GetDomainDNFromDN returns the domain component of a distinguished name, e.g. DC=contoso,DC=com
+// for CN=user,OU=Users,DC=contoso,DC=com
+func GetDomainDNFromDN(dn string) string {
+	upperDN := strings.ToUpper(dn)
+	if strings.HasPrefix(upperDN, "DC=") {
+		return dn
+	}
+	idx := strings.Index(upperDN, ",DC=")
+	if idx < 0 {
+		return ""
+	}
+	return dn[idx+1:]
+}
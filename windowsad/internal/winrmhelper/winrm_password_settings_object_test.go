@@ -0,0 +1,93 @@
+package winrmhelper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpan(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"00:30:00", 30 * time.Minute, false},
+		{"1.00:00:00", 24 * time.Hour, false},
+		{"42.00:00:00", 42 * 24 * time.Hour, false},
+		{"0:00:00", 0, false},
+		{"365.02:48:05", 365*24*time.Hour + 2*time.Hour + 48*time.Minute + 5*time.Second, false},
+		{"24:00:00", 0, true},
+		{"00:60:00", 0, true},
+		{"30m", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimeSpan(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeSpan(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseTimeSpan(%q) = %s, want %s", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGetSubjectChanges(t *testing.T) {
+	current := []string{"CN=a,DC=test", "CN=B,DC=test"}
+	expected := []string{"cn=b,dc=test", "CN=c,DC=test"}
+
+	toAdd, toRemove := getSubjectChanges(current, expected)
+	if !reflect.DeepEqual(toAdd, []string{"CN=c,DC=test"}) {
+		t.Errorf("getSubjectChanges() toAdd = %v", toAdd)
+	}
+	if !reflect.DeepEqual(toRemove, []string{"CN=a,DC=test"}) {
+		t.Errorf("getSubjectChanges() toRemove = %v", toRemove)
+	}
+
+	toAdd, toRemove = getSubjectChanges(nil, nil)
+	if len(toAdd) != 0 || len(toRemove) != 0 {
+		t.Errorf("getSubjectChanges(nil, nil) = %v, %v", toAdd, toRemove)
+	}
+}
+
+func TestUnmarshallPasswordSettingsObject(t *testing.T) {
+	input := `{"ObjectGUID":"2a6f0b3c-8b9e-4cde-9c0a-0f3c1f6c1234","Name":"pso","DistinguishedName":"CN=pso,CN=Password Settings Container,CN=System,DC=test",` +
+		`"Precedence":10,"ComplexityEnabled":true,"MinPasswordLength":12,"AppliesTo":["CN=admins,DC=test"],"MaxPasswordAge":"42.00:00:00"}`
+
+	pso, err := unmarshallPasswordSettingsObject([]byte(input))
+	if err != nil {
+		t.Fatalf("unmarshallPasswordSettingsObject() unexpected error: %s", err)
+	}
+	if pso.Precedence != 10 || pso.MinPasswordLength != 12 || !pso.ComplexityEnabled || pso.MaxPasswordAge != "42.00:00:00" {
+		t.Errorf("unmarshallPasswordSettingsObject() = %+v", pso)
+	}
+	if !reflect.DeepEqual(pso.AppliesTo, []string{"CN=admins,DC=test"}) {
+		t.Errorf("unmarshallPasswordSettingsObject() AppliesTo = %v", pso.AppliesTo)
+	}
+
+	if _, err := unmarshallPasswordSettingsObject([]byte(`{"Name":"pso"}`)); err == nil {
+		t.Error("unmarshallPasswordSettingsObject() expected an error for a document without GUID")
+	}
+}
+
+func TestGetDomainDNFromDN(t *testing.T) {
+	tests := []struct {
+		dn       string
+		expected string
+	}{
+		{"CN=user,OU=Users,DC=contoso,DC=com", "DC=contoso,DC=com"},
+		{"cn=user,dc=contoso,dc=com", "dc=contoso,dc=com"},
+		{"DC=contoso,DC=com", "DC=contoso,DC=com"},
+		{"CN=user", ""},
+		{"CN=ADC,OU=Servers,DC=contoso,DC=com", "DC=contoso,DC=com"},
+	}
+
+	for _, tt := range tests {
+		if got := GetDomainDNFromDN(tt.dn); got != tt.expected {
+			t.Errorf("GetDomainDNFromDN(%q) = %q, want %q", tt.dn, got, tt.expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
@@ -15,8 +16,30 @@ import (
 )
 
 // GetSecIniFromResource buiilds the contents of the security settings ini file based on the data of the
-// resource.
-func GetSecIniFromResource(d *schema.ResourceData, schemaKeys map[string]*schema.Schema) (*ini.File, error) {
+// resource. The principals of the user rights assignments are resolved to SIDs on the host.
+func GetSecIniFromResource(conf *config.ProviderConf, d *schema.ResourceData, schemaKeys map[string]*schema.Schema) (*ini.File, error) {
+	iniFile, cfg, err := newSecIni()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.PopulateSecuritySettings(d, iniFile)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolvePrivilegeRightsSIDs(conf, iniFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return iniFile, nil
+
+}
+
+// newSecIni returns a security settings ini file holding only the required headers, and the
+// settings struct it was reflected from
+func newSecIni() (*ini.File, *gposec.SecuritySettings, error) {
 	loadOpts := ini.LoadOptions{
 		AllowBooleanKeys:         true,
 		KeyValueDelimiterOnWrite: "=",
@@ -28,22 +51,126 @@ func GetSecIniFromResource(d *schema.ResourceData, schemaKeys map[string]*schema
 
 	err := iniFile.ReflectFrom(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	return iniFile, cfg, nil
+}
 
-	err = cfg.PopulateSecuritySettings(d, iniFile)
+// NewSecIni returns a security settings ini file holding only the required headers
+func NewSecIni() (*ini.File, error) {
+	iniFile, _, err := newSecIni()
+	return iniFile, err
+}
+
+// splitPrivilegeRightPrincipals returns the principals of a Privilege Rights key. SIDs lose the *
+// prefix used in the INF file.
+func splitPrivilegeRightPrincipals(value string) []string {
+	principals := []string{}
+	for _, p := range strings.Split(value, ",") {
+		p = strings.TrimPrefix(strings.TrimSpace(p), "*")
+		if p != "" {
+			principals = append(principals, p)
+		}
+	}
+	return principals
+}
+
+// resolvePrivilegeRightsSIDs replaces the account names of the Privilege Rights section with SIDs
+// prefixed with a *, the way the Group Policy editor writes them.
+func resolvePrivilegeRightsSIDs(conf *config.ProviderConf, iniFile *ini.File) error {
+	section, err := iniFile.GetSection(gposec.PrivilegeRightsSection)
 	if err != nil {
-		return nil, err
+		return nil
 	}
 
-	return iniFile, nil
+	principals := []string{}
+	for _, key := range section.Keys() {
+		principals = append(principals, splitPrivilegeRightPrincipals(key.Value())...)
+	}
+	sids, err := ResolvePrincipalSIDs(conf, principals)
+	if err != nil {
+		return fmt.Errorf("error while resolving user rights assignments: %s", err)
+	}
 
+	for _, key := range section.Keys() {
+		values := []string{}
+		seen := map[string]bool{}
+		for _, p := range splitPrivilegeRightPrincipals(key.Value()) {
+			sid := sids[p]
+			if !seen[sid] {
+				seen[sid] = true
+				values = append(values, fmt.Sprintf("*%s", sid))
+			}
+		}
+		sort.Strings(values)
+		key.SetValue(strings.Join(values, ","))
+	}
+	return nil
+}
+
+// ResolvePrivilegeRightsNames replaces the SIDs of the user rights assignments read from a GPO
+// with the principals of the configuration that resolve to them, or with their account names.
+func ResolvePrivilegeRightsNames(conf *config.ProviderConf, rights *gposec.PrivilegeRights, configured []string) error {
+	toStrip := []string{}
+	for _, p := range configured {
+		toStrip = append(toStrip, strings.TrimPrefix(p, "*"))
+	}
+	configuredSIDs, err := ResolvePrincipalSIDs(conf, toStrip)
+	if err != nil {
+		return fmt.Errorf("error while resolving user rights assignments: %s", err)
+	}
+	names := map[string]string{}
+	for _, p := range configured {
+		sid := configuredSIDs[strings.TrimPrefix(p, "*")]
+		if _, ok := names[sid]; !ok {
+			names[sid] = p
+		}
+	}
+
+	unknown := []string{}
+	for _, right := range rights.Rights {
+		for _, p := range right.Principals {
+			sid := strings.ToUpper(strings.TrimPrefix(p, "*"))
+			if _, ok := names[sid]; !ok && strings.HasPrefix(p, "*") {
+				unknown = append(unknown, sid)
+			}
+		}
+	}
+	accountNames, err := ResolveSIDNames(conf, unknown)
+	if err != nil {
+		return err
+	}
+	for sid, name := range accountNames {
+		names[sid] = name
+	}
+
+	for idx, right := range rights.Rights {
+		principals := []string{}
+		for _, p := range right.Principals {
+			if name, ok := names[strings.ToUpper(strings.TrimPrefix(p, "*"))]; ok && strings.HasPrefix(p, "*") {
+				p = name
+			}
+			principals = append(principals, p)
+		}
+		rights.Rights[idx].Principals = principals
+	}
+	return nil
+}
+
+func getSecIniPath(gpo *GPO) string {
+	return gpo.configurationPath(ComputerConfiguration, `Microsoft\Windows NT\SecEdit\GptTmpl.inf`)
+}
+
+// GetSecIniRawContents returns the exact contents of the INF file, in the encoding it was written
+// with. The second return value is false if the file does not exist.
+func GetSecIniRawContents(conf *config.ProviderConf, gpo *GPO) ([]byte, bool, error) {
+	return GetSYSVOLFileContents(conf, getSecIniPath(gpo))
 }
 
 // GetSecIniContents returns a byte array with the contents of the INF file
 // encoded in UTF-8 (since we get the ouput via stdout).
 func GetSecIniContents(conf *config.ProviderConf, gpo *GPO) ([]byte, error) {
-	gptPath := fmt.Sprintf("%s\\Machine\\Microsoft\\Windows NT\\SecEdit\\GptTmpl.inf", gpo.basePath)
+	gptPath := getSecIniPath(gpo)
 	log.Printf("[DEBUG] Getting security settings inf from %s", gptPath)
 
 	cmd := fmt.Sprintf(`Get-Content "%s"`, gptPath)
@@ -87,23 +214,15 @@ func GetSecIniFromHost(conf *config.ProviderConf, gpo *GPO) (*gposec.SecuritySet
 	return iniFile, nil
 }
 
-// UploadSecIni uploads the security settings ini to the correct folder of a GPO and updates
-// the GPO's gpt.ini by incrementing the computer version by 1.
-func UploadSecIni(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, iniFile *ini.File) error {
-	ini.LineBreak = "\r\n"
-	buf := bytes.NewBuffer([]byte{})
-	iniLocation := fmt.Sprintf("%s\\Machine\\Microsoft\\Windows NT\\SecEdit\\GptTmpl.inf", gpo.basePath)
-	_, err := iniFile.WriteTo(buf)
-	if err != nil {
-		return fmt.Errorf("error while loading security INF file to buffer, error: %s ", err)
-	}
-	err = UploadFiletoSYSVOL(conf, cpClient, buf, iniLocation)
+// UploadSecIni uploads the contents of the security settings ini to the correct folder of a GPO and
+// updates the GPO's gpt.ini by incrementing the computer version by 1.
+func UploadSecIni(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO, contents []byte) error {
+	err := UploadFiletoSYSVOL(conf, cpClient, bytes.NewReader(contents), getSecIniPath(gpo))
 	if err != nil {
 		return err
 	}
 
-	cVer := gpo.computerVersion + 1
-	err = gpo.SetGPOVersions(conf, cpClient, gpo.userVersion, cVer)
+	err = gpo.IncrementComputerVersion(conf, cpClient)
 	if err != nil {
 		return err
 	}
@@ -113,7 +232,7 @@ func UploadSecIni(conf *config.ProviderConf, cpClient *winrmcp.Winrmcp, gpo *GPO
 // RemoveSecIni removes the ini file from the host and updates the GPO's  gpt.ini by incrementing the
 // computer version by 1.
 func RemoveSecIni(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp, gpo *GPO) error {
-	gptPath := fmt.Sprintf("%s\\Machine\\Microsoft\\Windows NT\\SecEdit\\GptTmpl.inf", gpo.basePath)
+	gptPath := getSecIniPath(gpo)
 	log.Printf("[DEBUG] Getting security settings inf from %s", gptPath)
 
 	cmd := fmt.Sprintf(`Remove-Item "%s"`, gptPath)
@@ -143,8 +262,7 @@ func RemoveSecIni(conf *config.ProviderConf, cpConn *winrmcp.Winrmcp, gpo *GPO)
 		}
 	}
 
-	cVer := gpo.computerVersion + 1
-	err = gpo.SetGPOVersions(conf, cpConn, gpo.userVersion, cVer)
+	err = gpo.IncrementComputerVersion(conf, cpConn)
 	if err != nil {
 		return err
 	}
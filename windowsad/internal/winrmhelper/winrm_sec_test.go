@@ -0,0 +1,56 @@
+package winrmhelper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gposec"
+	"gopkg.in/ini.v1"
+)
+
+func TestSplitPrivilegeRightPrincipals(t *testing.T) {
+	principals := splitPrivilegeRightPrincipals(`*S-1-5-32-544, CONTOSO\svc_sql,,*S-1-5-20`)
+	expected := []string{"S-1-5-32-544", `CONTOSO\svc_sql`, "S-1-5-20"}
+	if !reflect.DeepEqual(principals, expected) {
+		t.Errorf("splitPrivilegeRightPrincipals() = %v, want %v", principals, expected)
+	}
+	if len(splitPrivilegeRightPrincipals("")) != 0 {
+		t.Errorf("splitPrivilegeRightPrincipals() of an empty value should be empty")
+	}
+}
+
+func TestResolvePrivilegeRightsSIDs(t *testing.T) {
+	iniFile := ini.Empty()
+	section, _ := iniFile.NewSection(gposec.PrivilegeRightsSection)
+	_, _ = section.NewKey("SeServiceLogonRight", "S-1-5-20,*s-1-5-19,*S-1-5-20")
+	_, _ = section.NewKey("SeTcbPrivilege", "")
+
+	// SIDs are resolved without a round trip to the host
+	err := resolvePrivilegeRightsSIDs(nil, iniFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := section.Key("SeServiceLogonRight").Value(); v != "*S-1-5-19,*S-1-5-20" {
+		t.Errorf("unexpected value of SeServiceLogonRight: %q", v)
+	}
+	if v := section.Key("SeTcbPrivilege").Value(); v != "" {
+		t.Errorf("unexpected value of SeTcbPrivilege: %q", v)
+	}
+}
+
+func TestResolvePrivilegeRightsNames(t *testing.T) {
+	rights := &gposec.PrivilegeRights{
+		Rights: []gposec.PrivilegeRight{
+			{Privilege: "SeServiceLogonRight", Principals: []string{"*S-1-5-20", "*S-1-5-19", `CONTOSO\legacy`}},
+		},
+	}
+	// every SID is configured, so nothing has to be resolved on the host
+	err := ResolvePrivilegeRightsNames(nil, rights, []string{"s-1-5-20", "*S-1-5-19"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"s-1-5-20", "*S-1-5-19", `CONTOSO\legacy`}
+	if !reflect.DeepEqual(rights.Rights[0].Principals, expected) {
+		t.Errorf("unexpected principals %v, want %v", rights.Rights[0].Principals, expected)
+	}
+}
@@ -0,0 +1,244 @@
+package winrmhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+)
+
+// ServicePrincipalName represents a single SPN registered on a user or computer account
+type ServicePrincipalName struct {
+	AccountGUID string
+	SPN         string
+}
+
+// ServicePrincipalNameOwner represents an AD object and the SPNs registered on it
+type ServicePrincipalNameOwner struct {
+	GUID                  string   `json:"ObjectGUID"`
+	DN                    string   `json:"DistinguishedName"`
+	ObjectClass           string   `json:"ObjectClass"`
+	ServicePrincipalNames []string `json:"servicePrincipalName"`
+}
+
+// HasServicePrincipalName returns true if the SPN is registered on the object. SPNs are compared
+// case insensitively, like AD does.
+func (o *ServicePrincipalNameOwner) HasServicePrincipalName(spn string) bool {
+	for _, s := range o.ServicePrincipalNames {
+		if strings.EqualFold(s, spn) {
+			return true
+		}
+	}
+	return false
+}
+
+// getServicePrincipalNamesList returns a comma separated list of quoted SPNs that can be used as a powershell array.
+func getServicePrincipalNamesList(spns []string) string {
+	quoted := make([]string, len(spns))
+	for idx, spn := range spns {
+		quoted[idx] = fmt.Sprintf(`"%s"`, spn)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// getServicePrincipalNamesParam returns the Set-AD* parameter that replaces the SPNs of an account
+// with the given ones, or clears them if there are none.
+func getServicePrincipalNamesParam(spns []string) string {
+	if len(spns) == 0 {
+		return "-Clear servicePrincipalName"
+	}
+	return fmt.Sprintf("-ServicePrincipalNames @{Replace=%s}", getServicePrincipalNamesList(spns))
+}
+
+// escapeLDAPFilterValue escapes the characters that have a special meaning in LDAP search filters (RFC 4515)
+func escapeLDAPFilterValue(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\5c`,
+		`*`, `\2a`,
+		`(`, `\28`,
+		`)`, `\29`,
+		"\x00", `\00`,
+	)
+	return replacer.Replace(value)
+}
+
+// getSPNSearchFilter returns an LDAP filter that matches every object holding at least one of the given SPNs
+func getSPNSearchFilter(spns []string) string {
+	clauses := make([]string, len(spns))
+	for idx, spn := range spns {
+		clauses[idx] = fmt.Sprintf("(servicePrincipalName=%s)", escapeLDAPFilterValue(spn))
+	}
+	if len(clauses) == 1 {
+		return clauses[0]
+	}
+	return fmt.Sprintf("(|%s)", strings.Join(clauses, ""))
+}
+
+// findDuplicateServicePrincipalNames returns a map of SPN to the DNs of the objects, other than
+// the one identified by accountGUID, that already hold it.
+func findDuplicateServicePrincipalNames(owners []ServicePrincipalNameOwner, accountGUID string, spns []string) map[string][]string {
+	duplicates := map[string][]string{}
+	for _, spn := range spns {
+		for _, owner := range owners {
+			if accountGUID != "" && strings.EqualFold(owner.GUID, accountGUID) {
+				continue
+			}
+			if owner.HasServicePrincipalName(spn) {
+				duplicates[spn] = append(duplicates[spn], owner.DN)
+			}
+		}
+	}
+	return duplicates
+}
+
+// getGlobalCatalogServerCmd returns a powershell statement that sets $gcServer to the host name of
+// a global catalog server. The configured domain controller is used if it hosts the global catalog,
+// otherwise one is discovered. NewPSCommand only adds the credentials to the last cmdlet, so they
+// are passed to the domain controller lookup here. Discovery goes through the DC locator and takes
+// no credentials.
+func getGlobalCatalogServerCmd(dc string, passCredentials bool) string {
+	discover := "$gcServer = (Get-ADDomainController -Discover -Service GlobalCatalog).HostName[0]"
+	if dc == "" {
+		return discover
+	}
+	credential := ""
+	if passCredentials {
+		credential = " -Credential $Credential"
+	}
+	return fmt.Sprintf(`$gcServer = %q; if (-not (Get-ADDomainController -Identity %q -Server %q%s).IsGlobalCatalog) { %s }`, dc, dc, dc, credential, discover)
+}
+
+// FindServicePrincipalNameOwners searches the global catalog for objects holding any of the given SPNs.
+// Searching the global catalog makes the check forest wide, which is what `setspn -X` does.
+func FindServicePrincipalNameOwners(conf *config.ProviderConf, spns []string) ([]ServicePrincipalNameOwner, error) {
+	if len(spns) == 0 {
+		return []ServicePrincipalNameOwner{}, nil
+	}
+
+	filter := strings.ReplaceAll(getSPNSearchFilter(spns), "'", "''")
+	cmd := fmt.Sprintf(`%s; Get-ADObject -LDAPFilter '%s' -SearchBase "" -Server "${gcServer}:3268" -Properties servicePrincipalName`,
+		getGlobalCatalogServerCmd(conf.IdentifyDomainController(), conf.IsPassCredentialsEnabled()), filter)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      true,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, fmt.Errorf("winrm execution failure while searching for SPNs: %s", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("Get-ADObject exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	if strings.TrimSpace(result.Stdout) == "" {
+		return []ServicePrincipalNameOwner{}, nil
+	}
+
+	var owners []ServicePrincipalNameOwner
+	err = json.Unmarshal([]byte(result.Stdout), &owners)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall SPN search results with error %q, document was %s", err, result.Stdout)
+		return nil, fmt.Errorf("failed while unmarshalling SPN search results: %s", err)
+	}
+	return owners, nil
+}
+
+// CheckServicePrincipalNameDuplicates returns an error if any of the SPNs is already registered
+// on an object other than the account identified by accountGUID. accountGUID can be empty when
+// the account does not exist yet.
+func CheckServicePrincipalNameDuplicates(conf *config.ProviderConf, accountGUID string, spns []string) error {
+	owners, err := FindServicePrincipalNameOwners(conf, spns)
+	if err != nil {
+		return err
+	}
+
+	duplicates := findDuplicateServicePrincipalNames(owners, accountGUID, spns)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	msgs := []string{}
+	for spn, dns := range duplicates {
+		msgs = append(msgs, fmt.Sprintf("%q is already registered on %s", spn, strings.Join(dns, ", ")))
+	}
+	sort.Strings(msgs)
+	return fmt.Errorf("duplicate SPNs found: %s", strings.Join(msgs, "; "))
+}
+
+// GetServicePrincipalNameOwnerFromHost returns the user or computer account identified by a GUID or a DN
+// along with the SPNs registered on it.
+func GetServicePrincipalNameOwnerFromHost(conf *config.ProviderConf, identity string) (*ServicePrincipalNameOwner, error) {
+	cmd := fmt.Sprintf("Get-ADObject -Identity %q -Properties servicePrincipalName", identity)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      true,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return nil, fmt.Errorf("winrm execution failure while retrieving account %q: %s", identity, err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("Get-ADObject exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+
+	var owner ServicePrincipalNameOwner
+	err = json.Unmarshal([]byte(result.Stdout), &owner)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to unmarshall an ADObject json document with error %q, document was %s", err, result.Stdout)
+		return nil, fmt.Errorf("failed while unmarshalling ADObject json document: %s", err)
+	}
+	if owner.GUID == "" {
+		return nil, fmt.Errorf("invalid data while unmarshalling ADObject data, json doc was: %s", result.Stdout)
+	}
+	return &owner, nil
+}
+
+// Add registers the SPN on the account, after making sure it's not used anywhere else in the forest
+func (s *ServicePrincipalName) Add(conf *config.ProviderConf) error {
+	err := CheckServicePrincipalNameDuplicates(conf, s.AccountGUID, []string{s.SPN})
+	if err != nil {
+		return err
+	}
+	return s.runSetADObject(conf, "Add")
+}
+
+// Remove unregisters the SPN from the account
+func (s *ServicePrincipalName) Remove(conf *config.ProviderConf) error {
+	return s.runSetADObject(conf, "Remove")
+}
+
+func (s *ServicePrincipalName) runSetADObject(conf *config.ProviderConf, op string) error {
+	cmd := fmt.Sprintf("Set-ADObject -Identity %q -%s @{servicePrincipalName=%q}", s.AccountGUID, op, s.SPN)
+	psOpts := CreatePSCommandOpts{
+		JSONOutput:      false,
+		ForceArray:      false,
+		ExecLocally:     conf.IsConnectionTypeLocal(),
+		PassCredentials: conf.IsPassCredentialsEnabled(),
+		Username:        conf.Settings.WinRMUsername,
+		Password:        conf.Settings.WinRMPassword,
+		Server:          conf.IdentifyDomainController(),
+	}
+	psCmd := NewPSCommand([]string{cmd}, psOpts)
+	result, err := psCmd.Run(conf)
+	if err != nil {
+		return fmt.Errorf("winrm execution failure while modifying SPNs of account %q: %s", s.AccountGUID, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("Set-ADObject exited with a non zero exit code (%d), stderr: %s", result.ExitCode, result.StdErr)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package winrmhelper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscapeLDAPFilterValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"HTTP/web.example.com", "HTTP/web.example.com"},
+		{"HTTP/*", `HTTP/\2a`},
+		{`MSSQLSvc/db(1)\x`, `MSSQLSvc/db\281\29\5cx`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLDAPFilterValue(tt.input); got != tt.expected {
+			t.Errorf("escapeLDAPFilterValue(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGetSPNSearchFilter(t *testing.T) {
+	tests := []struct {
+		spns     []string
+		expected string
+	}{
+		{[]string{"HTTP/web"}, "(servicePrincipalName=HTTP/web)"},
+		{[]string{"HTTP/web", "HTTP/web.example.com"}, "(|(servicePrincipalName=HTTP/web)(servicePrincipalName=HTTP/web.example.com))"},
+	}
+
+	for _, tt := range tests {
+		if got := getSPNSearchFilter(tt.spns); got != tt.expected {
+			t.Errorf("getSPNSearchFilter(%v) = %q, want %q", tt.spns, got, tt.expected)
+		}
+	}
+}
+
+func TestGetServicePrincipalNamesParam(t *testing.T) {
+	tests := []struct {
+		spns     []string
+		expected string
+	}{
+		{nil, "-Clear servicePrincipalName"},
+		{[]string{"HTTP/web", "HTTP/web.example.com"}, `-ServicePrincipalNames @{Replace="HTTP/web","HTTP/web.example.com"}`},
+	}
+
+	for _, tt := range tests {
+		if got := getServicePrincipalNamesParam(tt.spns); got != tt.expected {
+			t.Errorf("getServicePrincipalNamesParam(%v) = %q, want %q", tt.spns, got, tt.expected)
+		}
+	}
+}
+
+func TestGetGlobalCatalogServerCmd(t *testing.T) {
+	discover := "$gcServer = (Get-ADDomainController -Discover -Service GlobalCatalog).HostName[0]"
+	tests := []struct {
+		dc              string
+		passCredentials bool
+		expected        string
+	}{
+		{"", false, discover},
+		{"", true, discover},
+		{"dc1.example.com", false, `$gcServer = "dc1.example.com"; if (-not (Get-ADDomainController -Identity "dc1.example.com" -Server "dc1.example.com").IsGlobalCatalog) { ` + discover + " }"},
+		{"dc1.example.com", true, `$gcServer = "dc1.example.com"; if (-not (Get-ADDomainController -Identity "dc1.example.com" -Server "dc1.example.com" -Credential $Credential).IsGlobalCatalog) { ` + discover + " }"},
+	}
+
+	for _, tt := range tests {
+		if got := getGlobalCatalogServerCmd(tt.dc, tt.passCredentials); got != tt.expected {
+			t.Errorf("getGlobalCatalogServerCmd(%q, %t) = %q, want %q", tt.dc, tt.passCredentials, got, tt.expected)
+		}
+	}
+}
+
+func TestFindDuplicateServicePrincipalNames(t *testing.T) {
+	owners := []ServicePrincipalNameOwner{
+		{
+			GUID:                  "11111111-1111-1111-1111-111111111111",
+			DN:                    "CN=svc-web,OU=Users,DC=example,DC=com",
+			ServicePrincipalNames: []string{"HTTP/web.example.com", "HTTP/web"},
+		},
+		{
+			GUID:                  "22222222-2222-2222-2222-222222222222",
+			DN:                    "CN=WEB01,OU=Servers,DC=example,DC=com",
+			ServicePrincipalNames: []string{"HOST/web01.example.com"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		accountGUID string
+		spns        []string
+		expected    map[string][]string
+	}{
+		{
+			name:        "spn held by the account itself",
+			accountGUID: "11111111-1111-1111-1111-111111111111",
+			spns:        []string{"HTTP/web.example.com"},
+			expected:    map[string][]string{},
+		},
+		{
+			name:        "spn held by another account, case insensitive",
+			accountGUID: "22222222-2222-2222-2222-222222222222",
+			spns:        []string{"http/WEB.example.com", "HOST/web01.example.com"},
+			expected:    map[string][]string{"http/WEB.example.com": {"CN=svc-web,OU=Users,DC=example,DC=com"}},
+		},
+		{
+			name:        "new account",
+			accountGUID: "",
+			spns:        []string{"HOST/web01.example.com", "HTTP/unused"},
+			expected:    map[string][]string{"HOST/web01.example.com": {"CN=WEB01,OU=Servers,DC=example,DC=com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findDuplicateServicePrincipalNames(owners, tt.accountGUID, tt.spns)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("findDuplicateServicePrincipalNames() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
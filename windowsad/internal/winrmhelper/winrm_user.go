@@ -4,13 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"reflect"
 	"strings"
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -60,7 +57,13 @@ type User struct {
 	Username               string
 	PasswordNeverExpires   bool
 	CannotChangePassword   bool
-	CustomAttributes       map[string]interface{}
+	Protected              bool `json:"ProtectedFromAccidentalDeletion"`
+	ServicePrincipalNames  []string
+	// Constrained and resource-based constrained delegation settings, see KerberosDelegation
+	AllowedToDelegateTo                  []string `json:"msDS-AllowedToDelegateTo"`
+	TrustedToAuthForDelegation           bool
+	PrincipalsAllowedToDelegateToAccount []string
+	CustomAttributes                     map[string]interface{}
 }
 
 // NewUser creates the user by running the New-ADUser powershell command
@@ -212,12 +215,12 @@ func (u *User) NewUser(conf *config.ProviderConf) (string, error) {
 		cmds = append(cmds, fmt.Sprintf("-Title %q", u.Title))
 	}
 
+	if len(u.ServicePrincipalNames) > 0 {
+		cmds = append(cmds, fmt.Sprintf("-ServicePrincipalNames @(%s)", getServicePrincipalNamesList(u.ServicePrincipalNames)))
+	}
+
 	if u.CustomAttributes != nil {
-		attrs, err := u.getOtherAttributes()
-		if err != nil {
-			return "", err
-		}
-		cmds = append(cmds, fmt.Sprintf("-OtherAttributes %s", attrs))
+		cmds = append(cmds, fmt.Sprintf("-OtherAttributes %s", getOtherAttributes(u.CustomAttributes)))
 	}
 
 	psOpts := CreatePSCommandOpts{
@@ -247,6 +250,13 @@ func (u *User) NewUser(conf *config.ProviderConf) (string, error) {
 		return "", fmt.Errorf("error while unmarshalling user json document: %s", err)
 	}
 
+	if u.Protected {
+		err = setProtectedFromAccidentalDeletion(conf, user.GUID, true)
+		if err != nil {
+			return user.GUID, err
+		}
+	}
+
 	return user.GUID, nil
 }
 
@@ -315,82 +325,38 @@ func (u *User) ModifyUser(d *schema.ResourceData, conf *config.ProviderConf) err
 		}
 	}
 
-	if d.HasChange("custom_attributes") {
-		oldValue, newValue := d.GetChange("custom_attributes")
-		newMap, err := structure.ExpandJsonFromString(newValue.(string))
-		if err != nil {
-			return err
-		}
-
-		newSortedMap := SortInnerSlice(newMap)
-		toClear := []string{}
-		toReplace := []string{}
-		toAdd := []string{}
-
-		var oldSortedMap map[string]interface{}
-		if oldValue.(string) != "" {
-			oldMap, err := structure.ExpandJsonFromString(oldValue.(string))
-			if err != nil {
-				return fmt.Errorf("while expanding CA json string %s: %s", oldValue.(string), err)
-			}
-			oldSortedMap = SortInnerSlice(oldMap)
-		}
-
-		for k, v := range oldSortedMap {
-			if newVal, ok := newSortedMap[k]; ok {
-				if !reflect.DeepEqual(v, newVal) {
-					var out string
-					if reflect.ValueOf(newVal).Kind() == reflect.Slice {
-						quotedStrings := make([]string, len(newVal.([]string)))
-						for idx, s := range newVal.([]string) {
-							// Using %q here will cause double quotes inside the string to be escaped with \"
-							// which is not desirable in Powershell
-							quotedStrings[idx] = fmt.Sprintf(`"%s"`, s)
-						}
-						out = strings.Join(quotedStrings, ",")
-					} else {
-						out = fmt.Sprintf(`"%s"`, newVal.(string))
-					}
-					toReplace = append(toReplace, fmt.Sprintf(`'%s'=%s`, SanitiseString(k), out))
-				}
-			} else {
-				toClear = append(toClear, fmt.Sprintf(`'%s'`, SanitiseString(k)))
-			}
-		}
-
-		for k, newVal := range newSortedMap {
-			if _, ok := oldSortedMap[k]; !ok {
-				var out string
-				if reflect.ValueOf(newVal).Kind() == reflect.Slice {
-					quotedStrings := make([]string, len(newVal.([]string)))
-					for idx, s := range newVal.([]string) {
-						// Using %q here will cause double quotes inside the string to be escaped with \"
-						// which is not desirable in Powershell
-						quotedStrings[idx] = s
-					}
-					out = strings.Join(quotedStrings, ",")
-				} else {
-					out = newVal.(string)
-				}
-				toAdd = append(toAdd, fmt.Sprintf(`'%s'=%s`, SanitiseString(k), out))
-			}
-		}
+	caCmds, err := getCustomAttributesChanges(d)
+	if err != nil {
+		return err
+	}
+	cmds = append(cmds, caCmds...)
 
-		if len(toClear) > 0 {
-			cmds = append(cmds, fmt.Sprintf(`-Clear %s`, strings.Join(toClear, ";")))
+	if len(cmds) > 1 {
+		psOpts := CreatePSCommandOpts{
+			JSONOutput:      false,
+			ForceArray:      false,
+			ExecLocally:     conf.IsConnectionTypeLocal(),
+			PassCredentials: conf.IsPassCredentialsEnabled(),
+			Username:        conf.Settings.WinRMUsername,
+			Password:        conf.Settings.WinRMPassword,
+			Server:          conf.IdentifyDomainController(),
 		}
+		psCmd := NewPSCommand(cmds, psOpts)
+		result, err := psCmd.Run(conf)
 
-		if len(toReplace) > 0 {
-			cmds = append(cmds, fmt.Sprintf(`-Replace @{%s}`, strings.Join(toReplace, ";")))
+		if err != nil {
+			return err
 		}
-
-		if len(toAdd) > 0 {
-			cmds = append(cmds, fmt.Sprintf(`-Add @{%s}`, strings.Join(toAdd, ";")))
+		if result.ExitCode != 0 {
+			log.Printf("[DEBUG] stderr: %s\nstdout: %s", result.StdErr, result.Stdout)
+			return fmt.Errorf("command Set-ADUser exited with a non-zero exit code %d, stderr: %s", result.ExitCode, result.StdErr)
 		}
-
 	}
 
-	if len(cmds) > 1 {
+	if d.HasChange("service_principal_names") {
+		// The SPNs are changed with a command of their own, as clearing them can't be combined
+		// with the -Clear parameter used for the custom attributes.
+		cmd := fmt.Sprintf("Set-ADUser -Identity %q %s", u.GUID, getServicePrincipalNamesParam(u.ServicePrincipalNames))
 		psOpts := CreatePSCommandOpts{
 			JSONOutput:      false,
 			ForceArray:      false,
@@ -400,9 +366,8 @@ func (u *User) ModifyUser(d *schema.ResourceData, conf *config.ProviderConf) err
 			Password:        conf.Settings.WinRMPassword,
 			Server:          conf.IdentifyDomainController(),
 		}
-		psCmd := NewPSCommand(cmds, psOpts)
+		psCmd := NewPSCommand([]string{cmd}, psOpts)
 		result, err := psCmd.Run(conf)
-
 		if err != nil {
 			return err
 		}
@@ -434,7 +399,21 @@ func (u *User) ModifyUser(d *schema.ResourceData, conf *config.ProviderConf) err
 		}
 	}
 
-	if d.HasChange("container") {
+	// Users restored from the recycle bin already have the configured name and container.
+	moved := !d.IsNewResource() && (d.HasChange("name") || d.HasChange("container"))
+
+	// Protected objects cannot be renamed or moved, so we temporarily lift the protection.
+	oldProtected, _ := d.GetChange("protected_from_accidental_deletion")
+	unprotected := false
+	if oldProtected.(bool) && moved {
+		err := setProtectedFromAccidentalDeletion(conf, u.GUID, false)
+		if err != nil {
+			return err
+		}
+		unprotected = true
+	}
+
+	if moved && d.HasChange("container") {
 		path := d.Get("container").(string)
 		cmd := fmt.Sprintf("Move-AdObject -Identity %q -TargetPath %q", u.GUID, path)
 		psOpts := CreatePSCommandOpts{
@@ -456,7 +435,7 @@ func (u *User) ModifyUser(d *schema.ResourceData, conf *config.ProviderConf) err
 		}
 	}
 
-	if d.HasChange("name") {
+	if moved && d.HasChange("name") {
 		newName := d.Get("name").(string)
 		cmd := fmt.Sprintf("Rename-ADObject -Identity %q -NewName %q", u.GUID, newName)
 		psOpts := CreatePSCommandOpts{
@@ -478,11 +457,25 @@ func (u *User) ModifyUser(d *schema.ResourceData, conf *config.ProviderConf) err
 		}
 	}
 
+	if unprotected || d.HasChange("protected_from_accidental_deletion") {
+		err := setProtectedFromAccidentalDeletion(conf, u.GUID, u.Protected)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // DeleteUser deletes an AD user by calling Remove-ADUser
 func (u *User) DeleteUser(conf *config.ProviderConf) error {
+	if u.Protected {
+		err := setProtectedFromAccidentalDeletion(conf, u.GUID, false)
+		if err != nil {
+			return err
+		}
+	}
+
 	cmd := fmt.Sprintf("Remove-ADUser -Identity %s -Confirm:$false", u.GUID)
 	psOpts := CreatePSCommandOpts{
 		JSONOutput:      false,
@@ -505,28 +498,6 @@ func (u *User) DeleteUser(conf *config.ProviderConf) error {
 	return nil
 }
 
-func (u *User) getOtherAttributes() (string, error) {
-	out := []string{}
-	for k, v := range u.CustomAttributes {
-		cleanKey := SanitiseString(k)
-		var cleanValue string
-		if reflect.ValueOf(v).Kind() == reflect.Slice {
-			quotedStrings := make([]string, len(v.([]interface{})))
-			for idx, s := range v.([]interface{}) {
-				// Using %q here will cause double quotes inside the string to be escaped with \"
-				// which is not desirable in Powershell
-				quotedStrings[idx] = GetString(s.(string))
-			}
-			cleanValue = strings.Join(quotedStrings, ",")
-		} else {
-			cleanValue = GetString(v)
-		}
-		out = append(out, fmt.Sprintf(`'%s'=%s`, cleanKey, cleanValue))
-	}
-	finalAttrString := strings.Join(out, ";")
-	return fmt.Sprintf("@{%s}", finalAttrString), nil
-}
-
 // GetUserFromResource returns a user struct built from Resource data
 func GetUserFromResource(d *schema.ResourceData) (*User, error) {
 	user := User{
@@ -569,6 +540,7 @@ func GetUserFromResource(d *schema.ResourceData) (*User, error) {
 		Surname:                SanitiseTFInput(d, "surname"),
 		Title:                  SanitiseTFInput(d, "title"),
 		TrustedForDelegation:   d.Get("trusted_for_delegation").(bool),
+		Protected:              d.Get("protected_from_accidental_deletion").(bool),
 	}
 	if user.PrincipalName != "" {
 		tokens := strings.Split(user.PrincipalName, "@")
@@ -578,16 +550,18 @@ func GetUserFromResource(d *schema.ResourceData) (*User, error) {
 		}
 	}
 
-	ca, ok := d.Get("custom_attributes").(string)
-	if ok && len(ca) > 0 {
-		user.CustomAttributes = make(map[string]interface{})
-		customAttributes, err := structure.ExpandJsonFromString(ca)
-		if err != nil {
-			return nil, fmt.Errorf("while unmarshalling custom attributes JSON doc: %s", err)
+	if spns, ok := d.GetOk("service_principal_names"); ok {
+		for _, spn := range spns.(*schema.Set).List() {
+			user.ServicePrincipalNames = append(user.ServicePrincipalNames, SanitiseString(spn.(string)))
 		}
-		user.CustomAttributes = customAttributes
 	}
 
+	customAttributes, err := getCustomAttributesFromResource(d)
+	if err != nil {
+		return nil, err
+	}
+	user.CustomAttributes = customAttributes
+
 	return &user, nil
 }
 
@@ -654,23 +628,9 @@ func unmarshallUser(input []byte, customAttributes []string) (*User, error) {
 	user.Enabled = !(user.UserAccountControl&accountControlMap["disabled"] != 0)
 	user.PasswordNeverExpires = user.UserAccountControl&accountControlMap["password_never_expires"] != 0
 
-	if customAttributes == nil {
-		return &user, nil
-	}
-
-	var userMapIntf interface{}
-	err = json.Unmarshal(input, &userMapIntf)
+	user.CustomAttributes, err = unmarshallCustomAttributes(input, customAttributes)
 	if err != nil {
-		log.Printf("[DEBUG] Failed to unmarshall json document with error %q, document was: %s", err, string(input))
-		return nil, fmt.Errorf("failed while unmarshalling json response: %s", err)
-	}
-
-	userMap := userMapIntf.(map[string]interface{})
-	user.CustomAttributes = make(map[string]interface{})
-	for _, property := range customAttributes {
-		if val, ok := userMap[property]; ok {
-			user.CustomAttributes[property] = val
-		}
+		return nil, err
 	}
 
 	return &user, nil
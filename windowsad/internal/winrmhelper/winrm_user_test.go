@@ -234,19 +234,21 @@ func TestUser_BooleanFields(t *testing.T) {
 		expectedTrusted        bool
 		expectedPwdNeverExpire bool
 		expectedCannotChange   bool
+		expectedProtected      bool
 	}{
 		{
 			name:                   "all true",
-			json:                   `{"Enabled": true, "SmartcardLogonRequired": true, "TrustedForDelegation": true, "PasswordNeverExpires": true, "CannotChangePassword": true}`,
+			json:                   `{"Enabled": true, "SmartcardLogonRequired": true, "TrustedForDelegation": true, "PasswordNeverExpires": true, "CannotChangePassword": true, "ProtectedFromAccidentalDeletion": true}`,
 			expectedEnabled:        true,
 			expectedSmartcard:      true,
 			expectedTrusted:        true,
 			expectedPwdNeverExpire: true,
 			expectedCannotChange:   true,
+			expectedProtected:      true,
 		},
 		{
 			name:                   "all false",
-			json:                   `{"Enabled": false, "SmartcardLogonRequired": false, "TrustedForDelegation": false, "PasswordNeverExpires": false, "CannotChangePassword": false}`,
+			json:                   `{"Enabled": false, "SmartcardLogonRequired": false, "TrustedForDelegation": false, "PasswordNeverExpires": false, "CannotChangePassword": false, "ProtectedFromAccidentalDeletion": false}`,
 			expectedEnabled:        false,
 			expectedSmartcard:      false,
 			expectedTrusted:        false,
@@ -287,6 +289,9 @@ func TestUser_BooleanFields(t *testing.T) {
 			if user.CannotChangePassword != tt.expectedCannotChange {
 				t.Errorf("CannotChangePassword = %v, want %v", user.CannotChangePassword, tt.expectedCannotChange)
 			}
+			if user.Protected != tt.expectedProtected {
+				t.Errorf("Protected = %v, want %v", user.Protected, tt.expectedProtected)
+			}
 		})
 	}
 }
@@ -108,11 +108,13 @@ func Provider() *schema.Provider {
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			// Primary names (recommended)
-			"windowsad_user":     dataSourceADUser(),
-			"windowsad_group":    dataSourceADGroup(),
-			"windowsad_gpo":      dataSourceADGPO(),
-			"windowsad_computer": dataSourceADComputer(),
-			"windowsad_ou":       dataSourceADOU(),
+			"windowsad_user":                           dataSourceADUser(),
+			"windowsad_group":                          dataSourceADGroup(),
+			"windowsad_gpo":                            dataSourceADGPO(),
+			"windowsad_computer":                       dataSourceADComputer(),
+			"windowsad_deleted_objects":                dataSourceADDeletedObjects(),
+			"windowsad_ou":                             dataSourceADOU(),
+			"windowsad_user_resultant_password_policy": dataSourceADUserResultantPasswordPolicy(),
 			// Legacy aliases for migration from hashicorp/ad provider
 			// Deprecated: Use windowsad_* names for new configurations
 			"ad_user":     dataSourceADUser(),
@@ -123,14 +125,31 @@ func Provider() *schema.Provider {
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			// Primary names (recommended)
-			"windowsad_user":             resourceADUser(),
-			"windowsad_group":            resourceADGroup(),
-			"windowsad_group_membership": resourceADGroupMembership(),
-			"windowsad_gpo":              resourceADGPO(),
-			"windowsad_gpo_security":     resourceADGPOSecurity(),
-			"windowsad_computer":         resourceADComputer(),
-			"windowsad_ou":               resourceADOU(),
-			"windowsad_gplink":           resourceADGPLink(),
+			"windowsad_user":                           resourceADUser(),
+			"windowsad_group":                          resourceADGroup(),
+			"windowsad_group_membership":               resourceADGroupMembership(),
+			"windowsad_gpo":                            resourceADGPO(),
+			"windowsad_gpo_security":                   resourceADGPOSecurity(),
+			"windowsad_gpo_registry_policy":            resourceADGPORegistryPolicy(),
+			"windowsad_gpo_advanced_audit_policy":      resourceADGPOAdvancedAuditPolicy(),
+			"windowsad_gpo_script":                     resourceADGPOScript(),
+			"windowsad_gpo_preference_groups":          resourceADGPOPreferenceGroups(),
+			"windowsad_gpo_preference_drives":          resourceADGPOPreferenceDrives(),
+			"windowsad_gpo_preference_scheduled_tasks": resourceADGPOPreferenceScheduledTasks(),
+			"windowsad_gpo_preference_files":           resourceADGPOPreferenceFiles(),
+			"windowsad_gpo_preference_registry":        resourceADGPOPreferenceRegistry(),
+			"windowsad_computer":                       resourceADComputer(),
+			"windowsad_ou":                             resourceADOU(),
+			"windowsad_gplink":                         resourceADGPLink(),
+			"windowsad_offline_domain_join":            resourceADOfflineDomainJoin(),
+			"windowsad_service_principal_name":         resourceADServicePrincipalName(),
+			"windowsad_managed_service_account":        resourceADManagedServiceAccount(),
+			"windowsad_kds_root_key":                   resourceADKdsRootKey(),
+			"windowsad_password_settings_object":       resourceADPasswordSettingsObject(),
+			"windowsad_default_domain_password_policy": resourceADDefaultDomainPasswordPolicy(),
+			"windowsad_object_ace":                     resourceADObjectACE(),
+			"windowsad_object_acl":                     resourceADObjectACL(),
+			"windowsad_object_owner":                   resourceADObjectOwner(),
 			// Legacy aliases for migration from hashicorp/ad provider
 			// Deprecated: Use windowsad_* names for new configurations
 			"ad_user":             resourceADUser(),
@@ -174,3 +193,14 @@ func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
 	// signature in order to match the one defined for DiffSuppressFunc
 	return strings.EqualFold(old, new)
 }
+
+// suppressUnconfiguredDiff suppresses the diff of an optional attribute that is absent from the
+// configuration, so that the current value of the object is left alone. Unlike an optional and
+// computed attribute, an empty set in the configuration still produces a diff.
+func suppressUnconfiguredDiff(k, old, new string, d *schema.ResourceData) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return false
+	}
+	return rawConfig.GetAttr(strings.SplitN(k, ".", 2)[0]).IsNull()
+}
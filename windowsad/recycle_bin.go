@@ -0,0 +1,41 @@
+package windowsad
+
+import (
+	"fmt"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// restoreFromRecycleBinSchema returns the restore_from_recycle_bin attribute of the user, group and
+// computer resources
+func restoreFromRecycleBinSchema(objectType string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: fmt.Sprintf("If set to true, a deleted %[1]s with the same SAM account name is restored from the AD Recycle Bin instead of creating a new %[1]s, so it keeps its GUID, SID, group memberships and permissions. The configured attributes are then applied to the restored %[1]s.", objectType),
+	}
+}
+
+// restoreFromRecycleBin restores the most recently deleted object of the given class with the given
+// SAM account name, if restore_from_recycle_bin is set. It sets the ID of the resource and returns
+// true if an object was restored, in which case the caller applies the configuration with its
+// update function.
+func restoreFromRecycleBin(d *schema.ResourceData, meta interface{}, objectClass, samAccountName, container, name string) (bool, error) {
+	if !d.Get("restore_from_recycle_bin").(bool) {
+		return false, nil
+	}
+	guid, err := winrmhelper.RestoreMatchingDeletedObject(meta.(*config.ProviderConf), objectClass, samAccountName, container, name)
+	if err != nil {
+		return false, fmt.Errorf("while restoring deleted %s %q from the recycle bin: %s", objectClass, samAccountName, err)
+	}
+	if guid == "" {
+		return false, nil
+	}
+	d.SetId(guid)
+	return true, nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceADComputer() *schema.Resource {
@@ -59,6 +61,96 @@ func resourceADComputer() *schema.Resource {
 				Computed:    true,
 				Description: "The SID of the computer object.",
 			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "If set to false, the computer account will be disabled. If omitted, new computer accounts are enabled and the setting of existing ones is not managed by terraform.",
+			},
+			"dns_host_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "Specifies the fully qualified domain name (FQDN) of the computer. This parameter sets the DNSHostName property for the computer object.",
+			},
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies the location of the computer, such as an office number. This parameter sets the Location property for the computer object.",
+			},
+			"managed_by": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The DN of the user or group that manages the computer object.",
+			},
+			"service_principal_names": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The service principal names (SPNs) of the computer account. SPNs that are already registered on another object in the forest are rejected. If omitted, the SPNs of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"trusted_for_delegation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "If set to true, the computer account is trusted for Kerberos delegation. This parameter sets the TrustedForDelegation property of the computer object. If omitted, the setting is not managed by terraform.",
+			},
+			"protected_from_accidental_deletion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If set to true, the computer account will be protected from accidental deletion. The protection is lifted when the computer account is moved or destroyed by terraform.",
+			},
+			"restore_from_recycle_bin": restoreFromRecycleBinSchema("computer account"),
+			"allowed_to_delegate_to": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The SPNs of the services this computer account can present delegated credentials to (constrained delegation). This parameter sets the msDS-AllowedToDelegateTo attribute. If omitted, the constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"trusted_to_auth_for_delegation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "If set to true, the computer account can use protocol transition for constrained delegation, i.e. delegate without the client authenticating with Kerberos. This parameter sets the TrustedToAuthForDelegation property of an account object. If omitted, the setting is not managed by terraform.",
+			},
+			"principals_allowed_to_delegate_to_account": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The distinguished names of the principals that are allowed to delegate to this computer account (resource-based constrained delegation). This parameter sets the PrincipalsAllowedToDelegateToAccount property, which is stored in the msDS-AllowedToActOnBehalfOfOtherIdentity security descriptor. If omitted, the resource-based constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"custom_attributes": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "JSON encoded map that represents key/value pairs for custom attributes. Please note that `terraform import` will not import these attributes.",
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressJsonDiff,
+			},
+			"operating_system": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system reported by the computer.",
+			},
+			"operating_system_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system version reported by the computer.",
+			},
+			"operating_system_service_pack": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system service pack reported by the computer.",
+			},
+			"operating_system_hotfix": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The operating system hotfix reported by the computer.",
+			},
 		},
 	}
 }
@@ -68,7 +160,12 @@ func resourceADComputerRead(d *schema.ResourceData, meta interface{}) error {
 		return nil
 	}
 
-	computer, err := winrmhelper.NewComputerFromHost(meta.(*config.ProviderConf), d.Id())
+	caKeys, err := extractCustAttrKeys(d)
+	if err != nil {
+		return err
+	}
+
+	computer, err := winrmhelper.NewComputerFromHost(meta.(*config.ProviderConf), d.Id(), caKeys)
 	if err != nil {
 		if strings.Contains(err.Error(), "ObjectNotFound") {
 			// Resource no longer exists
@@ -85,34 +182,122 @@ func resourceADComputerRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set("pre2kname", strings.TrimSuffix(computer.SAMAccountName, "$"))
 	_ = d.Set("container", computer.Path)
 	_ = d.Set("sid", computer.SID.Value)
+	_ = d.Set("enabled", computer.Enabled)
+	_ = d.Set("dns_host_name", computer.DNSHostName)
+	_ = d.Set("location", computer.Location)
+	_ = d.Set("managed_by", computer.ManagedBy)
+	_ = d.Set("service_principal_names", computer.ServicePrincipalNames)
+	_ = d.Set("trusted_for_delegation", computer.TrustedForDelegation)
+	_ = d.Set("protected_from_accidental_deletion", computer.Protected)
+	_ = d.Set("allowed_to_delegate_to", computer.AllowedToDelegateTo)
+	_ = d.Set("trusted_to_auth_for_delegation", computer.TrustedToAuthForDelegation)
+	_ = d.Set("principals_allowed_to_delegate_to_account", computer.PrincipalsAllowedToDelegateToAccount)
+	_ = d.Set("operating_system", computer.OperatingSystem)
+	_ = d.Set("operating_system_version", computer.OperatingSystemVersion)
+	_ = d.Set("operating_system_service_pack", computer.OperatingSystemServicePack)
+	_ = d.Set("operating_system_hotfix", computer.OperatingSystemHotfix)
+
+	if computer.CustomAttributes != nil {
+		ca, err := structure.FlattenJsonToString(computer.CustomAttributes)
+		if err != nil {
+			return err
+		}
+		_ = d.Set("custom_attributes", ca)
+	}
 
 	return nil
 }
 
 func resourceADComputerCreate(d *schema.ResourceData, meta interface{}) error {
-	computer := winrmhelper.NewComputerFromResource(d)
+	computer, err := winrmhelper.NewComputerFromResource(d)
+	if err != nil {
+		return fmt.Errorf("while building a Computer struct from resource data: %s", err)
+	}
+	// enabled is optional and computed, computer accounts are created enabled unless configured otherwise
+	if d.GetRawConfig().GetAttr("enabled").IsNull() {
+		computer.Enabled = true
+	}
+	if len(computer.ServicePrincipalNames) > 0 {
+		err = winrmhelper.CheckServicePrincipalNameDuplicates(meta.(*config.ProviderConf), "", computer.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+	// computer accounts get a trailing $ in their SAM account name, New-ADComputer adds it if needed
+	sam := d.Get("pre2kname").(string)
+	if sam == "" {
+		sam = d.Get("name").(string)
+	}
+	if !strings.HasSuffix(sam, "$") {
+		sam += "$"
+	}
+	restored, err := restoreFromRecycleBin(d, meta, "computer", sam, computer.Path, computer.Name)
+	if err != nil {
+		return err
+	}
+	if restored {
+		// the computer is updated by GUID, which is only set by Read otherwise
+		_ = d.Set("guid", d.Id())
+		return resourceADComputerUpdate(d, meta)
+	}
+
 	guid, err := computer.Create(meta.(*config.ProviderConf))
 	if err != nil {
 		return fmt.Errorf("error while creating new computer object: %s", err)
 	}
 	d.SetId(guid)
+
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(meta.(*config.ProviderConf), "Set-ADComputer", guid, delegation.ConfiguredKeys())
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for computer %q: %s", guid, err)
+	}
 	return resourceADComputerRead(d, meta)
 }
 
 func resourceADComputerUpdate(d *schema.ResourceData, meta interface{}) error {
-	computer := winrmhelper.NewComputerFromResource(d)
-	keys := []string{"container", "description"}
+	computer, err := winrmhelper.NewComputerFromResource(d)
+	if err != nil {
+		return err
+	}
+	if d.HasChange("service_principal_names") && len(computer.ServicePrincipalNames) > 0 {
+		err = winrmhelper.CheckServicePrincipalNameDuplicates(meta.(*config.ProviderConf), d.Id(), computer.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+	keys := []string{"container", "description", "enabled", "dns_host_name", "location", "managed_by", "service_principal_names", "trusted_for_delegation", "protected_from_accidental_deletion"}
 	changes := make(map[string]interface{})
 	for _, key := range keys {
 		if d.HasChange(key) {
 			changes[key] = d.Get(key)
 		}
 	}
+	// computers restored from the recycle bin are already in the configured container
+	if d.IsNewResource() {
+		delete(changes, "container")
+	}
+	if d.HasChange("custom_attributes") {
+		oldValue, newValue := d.GetChange("custom_attributes")
+		changes["custom_attributes"] = []string{oldValue.(string), newValue.(string)}
+	}
 
-	err := computer.Update(meta.(*config.ProviderConf), changes)
+	err = computer.Update(meta.(*config.ProviderConf), changes)
 	if err != nil {
 		return fmt.Errorf("error while updating computer with id %q: %s", d.Id(), err)
 	}
+
+	changedKeys := []string{}
+	for _, key := range winrmhelper.KerberosDelegationKeys {
+		if d.HasChange(key) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(meta.(*config.ProviderConf), "Set-ADComputer", d.Id(), changedKeys)
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for computer %q: %s", d.Id(), err)
+	}
 	return resourceADComputerRead(d, meta)
 }
 
@@ -120,8 +305,11 @@ func resourceADComputerDelete(d *schema.ResourceData, meta interface{}) error {
 	if d.Id() == "" {
 		return nil
 	}
-	computer := winrmhelper.NewComputerFromResource(d)
-	err := computer.Delete(meta.(*config.ProviderConf))
+	computer, err := winrmhelper.NewComputerFromResource(d)
+	if err != nil {
+		return err
+	}
+	err = computer.Delete(meta.(*config.ProviderConf))
 	if err != nil {
 		return fmt.Errorf("error while deleting a computer object with id %q: %s", d.Id(), err)
 	}
@@ -116,6 +116,190 @@ func TestAccResourceADComputer_move(t *testing.T) {
 	})
 }
 
+func TestAccResourceADComputer_protected(t *testing.T) {
+
+	envVars := []string{"TF_VAR_ad_computer_container"}
+
+	container := os.Getenv("TF_VAR_ad_computer_container")
+	computerName := testAccShortRandomName("pc")
+	sam := testAccRandomSAM()
+	ouName := testAccRandomName("tfacc-ou")
+	resourceName := "windowsad_computer.c"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADComputerExists(resourceName, computerName, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADComputerConfigProtected(computerName, sam, container, ouName, "var.container"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "true"),
+				),
+			},
+			{
+				// a protected computer can only be moved if the protection is lifted first
+				Config: testAccResourceADComputerConfigProtected(computerName, sam, container, ouName, "windowsad_ou.o.dn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "container", fmt.Sprintf("OU=%s,%s", ouName, container)),
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceADComputer_attributes(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_computer_container",
+		"TF_VAR_ad_domain_name",
+	}
+
+	container := os.Getenv("TF_VAR_ad_computer_container")
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	computerName := testAccShortRandomName("pc")
+	sam := testAccRandomSAM()
+	dnsHostName := fmt.Sprintf("%s.%s", computerName, domain)
+	resourceName := "windowsad_computer.c"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADComputerExists(resourceName, computerName, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADComputerConfigAttributes(computerName, sam, container, dnsHostName, "Room 101", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "dns_host_name", dnsHostName),
+					resource.TestCheckResourceAttr(resourceName, "location", "Room 101"),
+					resource.TestCheckResourceAttr(resourceName, "service_principal_names.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADComputerConfigAttributes(computerName, sam, container, dnsHostName, "Room 102", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "location", "Room 102"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"custom_attributes"},
+			},
+		},
+	})
+}
+
+func TestAccResourceADComputer_delegation(t *testing.T) {
+
+	envVars := []string{"TF_VAR_ad_computer_container"}
+
+	container := os.Getenv("TF_VAR_ad_computer_container")
+	computerName := testAccShortRandomName("pc")
+	frontendName := testAccShortRandomName("pc")
+	resourceName := "windowsad_computer.c"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADComputerExists(resourceName, computerName, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADComputerConfigDelegation(computerName, frontendName, container, testAccResourceADComputerDelegation(computerName, 1433, true)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "allowed_to_delegate_to.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "trusted_to_auth_for_delegation", "true"),
+					resource.TestCheckResourceAttr(resourceName, "principals_allowed_to_delegate_to_account.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADComputerConfigDelegation(computerName, frontendName, container, testAccResourceADComputerDelegation(computerName, 1434, false)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckTypeSetElemAttr(resourceName, "allowed_to_delegate_to.*", fmt.Sprintf("MSSQLSvc/%s.tfacc.local:1434", computerName)),
+					resource.TestCheckResourceAttr(resourceName, "trusted_to_auth_for_delegation", "false"),
+					resource.TestCheckResourceAttr(resourceName, "principals_allowed_to_delegate_to_account.#", "1"),
+				),
+			},
+			{
+				// delegation settings that are no longer configured are left alone
+				Config: testAccResourceADComputerConfigDelegation(computerName, frontendName, container, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "allowed_to_delegate_to.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "trusted_to_auth_for_delegation", "false"),
+					resource.TestCheckResourceAttr(resourceName, "principals_allowed_to_delegate_to_account.#", "1"),
+				),
+			},
+			{
+				// empty sets remove the delegation settings
+				Config: testAccResourceADComputerConfigDelegation(computerName, frontendName, container, `
+  allowed_to_delegate_to                    = []
+  principals_allowed_to_delegate_to_account = []
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttr(resourceName, "allowed_to_delegate_to.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "principals_allowed_to_delegate_to_account.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceADComputerDelegation(name string, port int, protocolTransition bool) string {
+	return fmt.Sprintf(`
+  allowed_to_delegate_to                    = ["MSSQLSvc/%s.tfacc.local:%d"]
+  trusted_to_auth_for_delegation            = %t
+  principals_allowed_to_delegate_to_account = [windowsad_computer.frontend.dn]
+`, name, port, protocolTransition)
+}
+
+func testAccResourceADComputerConfigDelegation(name, frontendName, container, delegation string) string {
+	return fmt.Sprintf(`
+resource "windowsad_computer" "frontend" {
+  name      = %[2]q
+  container = %[3]q
+}
+
+resource "windowsad_computer" "c" {
+  name      = %[1]q
+  container = %[3]q
+%[4]s}
+`, name, frontendName, container, delegation)
+}
+
+func testAccResourceADComputerConfigAttributes(name, sam, container, dnsHostName, location string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "windowsad_computer" "c" {
+  name                    = %[1]q
+  pre2kname               = %[2]q
+  container               = %[3]q
+  dns_host_name           = %[4]q
+  location                = %[5]q
+  enabled                 = %[6]t
+  service_principal_names = ["HOST/%[4]s"]
+  custom_attributes = jsonencode({
+    "extensionAttribute1" : "tfacc"
+  })
+}
+`, name, sam, container, dnsHostName, location, enabled)
+}
+
 func testAccResourceADComputerConfigRandom(name, sam, container string) string {
 	return fmt.Sprintf(`
 resource "windowsad_computer" "c" {
@@ -153,6 +337,25 @@ resource "windowsad_computer" "c" {
 `, name, sam, parentContainer, ouName)
 }
 
+func testAccResourceADComputerConfigProtected(name, sam, parentContainer, ouName, container string) string {
+	return fmt.Sprintf(`
+variable "container" { default = %[3]q }
+
+resource "windowsad_ou" "o" {
+  name      = %[4]q
+  path      = var.container
+  protected = false
+}
+
+resource "windowsad_computer" "c" {
+  name                               = %[1]q
+  pre2kname                          = %[2]q
+  container                          = %[5]s
+  protected_from_accidental_deletion = true
+}
+`, name, sam, parentContainer, ouName, container)
+}
+
 func testAccResourceADComputerExists(resource, name string, expected bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resource]
@@ -161,7 +364,7 @@ func testAccResourceADComputerExists(resource, name string, expected bool) resou
 		}
 
 		guid := rs.Primary.ID
-		computer, err := winrmhelper.NewComputerFromHost(testAccProvider.Meta().(*config.ProviderConf), guid)
+		computer, err := winrmhelper.NewComputerFromHost(testAccProvider.Meta().(*config.ProviderConf), guid, nil)
 		if err != nil {
 			if strings.Contains(err.Error(), "ObjectNotFound") && !expected {
 				return nil
@@ -184,7 +387,7 @@ func testAccResourceADComputerDescriptionExists(resource, description string, ex
 		}
 
 		guid := rs.Primary.ID
-		computer, err := winrmhelper.NewComputerFromHost(testAccProvider.Meta().(*config.ProviderConf), guid)
+		computer, err := winrmhelper.NewComputerFromHost(testAccProvider.Meta().(*config.ProviderConf), guid, nil)
 		if err != nil {
 			if strings.Contains(err.Error(), "ObjectNotFound") && !expected {
 				return nil
@@ -0,0 +1,177 @@
+package windowsad
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADDefaultDomainPasswordPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_default_domain_password_policy` manages the password and lockout policy stored on the domain object. Only the attributes set in the configuration are managed.",
+		Create:      resourceADDefaultDomainPasswordPolicyCreate,
+		Read:        resourceADDefaultDomainPasswordPolicyRead,
+		Update:      resourceADDefaultDomainPasswordPolicyUpdate,
+		Delete:      resourceADDefaultDomainPasswordPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The DNS name or distinguished name of the domain.",
+			},
+			"complexity_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether passwords must meet complexity requirements.",
+			},
+			"min_password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 255),
+				Description:  "The minimum password length.",
+			},
+			"password_history_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 1024),
+				Description:  "The number of previous passwords that can't be reused.",
+			},
+			"min_password_age": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The minimum password age, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"max_password_age": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The maximum password age, as a time span in the `[d.]hh:mm:ss` format. Use `00:00:00` for passwords that never expire.",
+			},
+			"lockout_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 65535),
+				Description:  "The number of failed logon attempts that cause the account to be locked out. `0` disables lockout.",
+			},
+			"lockout_duration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "How long an account stays locked out, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"lockout_observation_window": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The time after which the failed logon attempts counter is reset, as a time span in the `[d.]hh:mm:ss` format. It must not be longer than `lockout_duration`.",
+			},
+			"reversible_encryption_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether passwords are stored using reversible encryption.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the domain.",
+			},
+		},
+	}
+}
+
+func resourceADDefaultDomainPasswordPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	domain := d.Get("domain").(string)
+
+	// The attributes are optional and computed, so only the ones present in the configuration are applied.
+	keys := []string{}
+	rawConfig := d.GetRawConfig()
+	for _, k := range winrmhelper.DefaultDomainPasswordPolicyKeys {
+		if !rawConfig.GetAttr(k).IsNull() {
+			keys = append(keys, k)
+		}
+	}
+
+	err := winrmhelper.SetDefaultDomainPasswordPolicy(meta.(*config.ProviderConf), d, domain, keys)
+	if err != nil {
+		return err
+	}
+	d.SetId(domain)
+
+	return resourceADDefaultDomainPasswordPolicyRead(d, meta)
+}
+
+func resourceADDefaultDomainPasswordPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	policy, err := winrmhelper.GetDefaultDomainPasswordPolicyFromHost(meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	_ = d.Set("domain", d.Id())
+	_ = d.Set("complexity_enabled", policy.ComplexityEnabled)
+	_ = d.Set("min_password_length", policy.MinPasswordLength)
+	_ = d.Set("password_history_count", policy.PasswordHistoryCount)
+	_ = d.Set("min_password_age", policy.MinPasswordAge)
+	_ = d.Set("max_password_age", policy.MaxPasswordAge)
+	_ = d.Set("lockout_threshold", policy.LockoutThreshold)
+	_ = d.Set("lockout_duration", policy.LockoutDuration)
+	_ = d.Set("lockout_observation_window", policy.LockoutObservationWindow)
+	_ = d.Set("reversible_encryption_enabled", policy.ReversibleEncryptionEnabled)
+	_ = d.Set("dn", policy.DN)
+
+	return nil
+}
+
+func resourceADDefaultDomainPasswordPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	keys := []string{}
+	for _, k := range winrmhelper.DefaultDomainPasswordPolicyKeys {
+		if d.HasChange(k) {
+			keys = append(keys, k)
+		}
+	}
+
+	err := winrmhelper.SetDefaultDomainPasswordPolicy(meta.(*config.ProviderConf), d, d.Id(), keys)
+	if err != nil {
+		return fmt.Errorf("while updating the default password policy of domain %q: %s", d.Id(), err)
+	}
+
+	return resourceADDefaultDomainPasswordPolicyRead(d, meta)
+}
+
+func resourceADDefaultDomainPasswordPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	// Every domain has a password policy, so the current settings are left in place and the
+	// resource is only removed from the state.
+	log.Printf("[WARN] The default password policy of domain %q is left unchanged, it is only removed from the terraform state", d.Id())
+	return nil
+}
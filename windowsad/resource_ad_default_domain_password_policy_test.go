@@ -0,0 +1,53 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADDefaultDomainPasswordPolicy_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	resourceName := "windowsad_default_domain_password_policy.p"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADDefaultDomainPasswordPolicyConfig(domain, 8),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "min_password_length", "8"),
+					resource.TestCheckResourceAttrSet(resourceName, "dn"),
+				),
+			},
+			{
+				Config: testAccResourceADDefaultDomainPasswordPolicyConfig(domain, 7),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "min_password_length", "7"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADDefaultDomainPasswordPolicyConfig(domain string, minLength int) string {
+	return fmt.Sprintf(`
+resource "windowsad_default_domain_password_policy" "p" {
+  domain              = %q
+  min_password_length = %d
+}
+`, domain, minLength)
+}
@@ -0,0 +1,239 @@
+package windowsad
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/auditcsv"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADGPOAdvancedAuditPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_gpo_advanced_audit_policy` manages the advanced audit policy configuration of the computer configuration of a Group Policy Object (GPO). The resource owns the whole `audit.csv` file of the GPO.",
+		Create:      resourceADGPOAdvancedAuditPolicyCreate,
+		Read:        resourceADGPOAdvancedAuditPolicyRead,
+		Update:      resourceADGPOAdvancedAuditPolicyUpdate,
+		Delete:      resourceADGPOAdvancedAuditPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"gpo_container": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The GUID of the container the advanced audit policy belongs to.",
+			},
+			"subcategory": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Description: "The audit setting of a subcategory. Subcategories that aren't listed are not configured by the policy.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(auditcsv.SubcategoryNames(), false),
+							Description:  "The name of the subcategory, e.g. `Credential Validation`, `Logon` or `Process Creation`.",
+						},
+						"success": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Audit successful events.",
+						},
+						"failure": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Audit failed events.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getAdvancedAuditPolicyFromResource returns the contents of the audit.csv file described by the
+// resource.
+func getAdvancedAuditPolicyFromResource(d *schema.ResourceData) ([]byte, error) {
+	settings := []auditcsv.Setting{}
+	for _, s := range d.Get("subcategory").(*schema.Set).List() {
+		sub := s.(map[string]interface{})
+		settings = append(settings, auditcsv.Setting{
+			Subcategory: sub["name"].(string),
+			Success:     sub["success"].(bool),
+			Failure:     sub["failure"].(bool),
+		})
+	}
+	return auditcsv.Encode(settings)
+}
+
+func getAdvancedAuditPolicyGUID(resourceID string) (string, error) {
+	toks := strings.Split(resourceID, "_")
+	if len(toks) != 2 {
+		return "", fmt.Errorf("resource ID %q does not match <guid>_advancedauditpolicy", resourceID)
+	}
+	return toks[0], nil
+}
+
+func resourceADGPOAdvancedAuditPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	if guid == "" {
+		return fmt.Errorf("Cannot handle empty GPO GUID")
+	}
+	parsedGUID, err := uuid.ParseUUID(guid)
+	if err != nil {
+		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
+	}
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
+	contents, err := getAdvancedAuditPolicyFromResource(d)
+	if err != nil {
+		return fmt.Errorf("error while generating advanced audit policy from resource data: %s", err)
+	}
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.UploadAuditCSV(meta.(*config.ProviderConf), winrmCPClient, gpo, contents)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.AddMachineExtensionNames(meta.(*config.ProviderConf), gpo.DN, winrmhelper.AdvancedAuditExtensionNames)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s_advancedauditpolicy", guid))
+
+	return resourceADGPOAdvancedAuditPolicyRead(d, meta)
+}
+
+func resourceADGPOAdvancedAuditPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	guid, err := getAdvancedAuditPolicyGUID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] GPO with guid %q not found", guid)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	_ = d.Set("gpo_container", guid)
+
+	contents, found, err := winrmhelper.GetAuditCSVContents(meta.(*config.ProviderConf), gpo)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Printf("[DEBUG] audit.csv file not found, marking resource as gone")
+		d.SetId("")
+		return nil
+	}
+
+	settings, err := auditcsv.Parse(contents)
+	if err != nil {
+		return fmt.Errorf("error while parsing advanced audit policy of GPO with guid %q: %s", guid, err)
+	}
+
+	subcategories := []interface{}{}
+	for _, s := range settings {
+		subcategories = append(subcategories, map[string]interface{}{
+			"name":    s.Subcategory,
+			"success": s.Success,
+			"failure": s.Failure,
+		})
+	}
+	return d.Set("subcategory", subcategories)
+}
+
+func resourceADGPOAdvancedAuditPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	contents, err := getAdvancedAuditPolicyFromResource(d)
+	if err != nil {
+		return fmt.Errorf("error while generating advanced audit policy from resource data: %s", err)
+	}
+
+	hostContents, _, err := winrmhelper.GetAuditCSVContents(meta.(*config.ProviderConf), gpo)
+	if err != nil {
+		return fmt.Errorf("error while retrieving advanced audit policy contents for GPO with guid %q: %s", guid, err)
+	}
+
+	if !bytes.Equal(contents, hostContents) {
+		err = winrmhelper.UploadAuditCSV(meta.(*config.ProviderConf), winrmCPClient, gpo, contents)
+		if err != nil {
+			return fmt.Errorf("error while uploading advanced audit policy file for GPO with guid %q: %s", guid, err)
+		}
+	}
+	return resourceADGPOAdvancedAuditPolicyRead(d, meta)
+}
+
+func resourceADGPOAdvancedAuditPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid, err := getAdvancedAuditPolicyGUID(d.Id())
+	if err != nil {
+		return err
+	}
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemoveAuditCSV(meta.(*config.ProviderConf), winrmCPClient, gpo)
+	if err != nil {
+		return fmt.Errorf("error while removing advanced audit policy file for GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemoveMachineExtensionNames(meta.(*config.ProviderConf), gpo.DN, winrmhelper.AdvancedAuditExtensionNames)
+	if err != nil {
+		return fmt.Errorf("error while unregistering advanced audit policy extension for GPO with guid %q: %s", guid, err)
+	}
+	return nil
+}
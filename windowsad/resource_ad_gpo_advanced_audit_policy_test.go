@@ -0,0 +1,124 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADGPOAdvancedAuditPolicy_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gpoaudit")
+	resourceName := "windowsad_gpo_advanced_audit_policy.audit"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOAdvancedAuditPolicyExists(resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOAdvancedAuditPolicyConfig(gpoName, domain, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOAdvancedAuditPolicyExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "subcategory.#", "3"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "subcategory.*", map[string]string{
+						"name":    "Logon",
+						"success": "true",
+						"failure": "false",
+					}),
+				),
+			},
+			{
+				Config: testAccResourceADGPOAdvancedAuditPolicyConfig(gpoName, domain, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOAdvancedAuditPolicyExists(resourceName, true),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "subcategory.*", map[string]string{
+						"name":    "Logon",
+						"success": "true",
+						"failure": "true",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOAdvancedAuditPolicyExists(resourceName string, desired bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		toks := strings.Split(rs.Primary.ID, "_")
+		if len(toks) != 2 {
+			return fmt.Errorf("resource ID %q does not match <guid>_advancedauditpolicy", rs.Primary.ID)
+		}
+		guid := toks[0]
+
+		gpo, err := winrmhelper.GetGPOFromHost(testAccProvider.Meta().(*config.ProviderConf), "", guid)
+		if err != nil {
+			// if the GPO got destroyed first then the rest of the entities depending on it
+			// are also destroyed.
+			if !desired && strings.Contains(err.Error(), "NotFound") {
+				return nil
+			}
+			return err
+		}
+		_, found, err := winrmhelper.GetAuditCSVContents(testAccProvider.Meta().(*config.ProviderConf), gpo)
+		if err != nil {
+			return err
+		}
+		if found != desired {
+			return fmt.Errorf("audit.csv file of GPO %q exists: %t, expected: %t", guid, found, desired)
+		}
+		return nil
+	}
+}
+
+func testAccResourceADGPOAdvancedAuditPolicyConfig(gpoName, domain string, logonFailure bool) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_advanced_audit_policy" "audit" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  subcategory {
+    name    = "Credential Validation"
+    success = true
+    failure = true
+  }
+
+  subcategory {
+    name    = "Logon"
+    success = true
+    failure = %[3]t
+  }
+
+  subcategory {
+    name    = "Process Creation"
+    success = true
+  }
+}
+`, gpoName, domain, logonFailure)
+}
@@ -0,0 +1,123 @@
+package windowsad
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Drive maps only exist in the user configuration.
+var gpoPreferenceDrives = &gpoPreferences{
+	kind: winrmhelper.PreferenceDrives,
+	idSuffixes: map[string]string{
+		winrmhelper.UserConfiguration: "preferencedrives",
+	},
+	encode: getPreferenceDrivesFromResource,
+	read:   setPreferenceDrivesResourceData,
+}
+
+func resourceADGPOPreferenceDrives() *schema.Resource {
+	return gpoPreferenceDrives.resource(
+		"`windowsad_gpo_preference_drives` manages the drive maps preferences of the user configuration of a Group Policy Object (GPO), mapping network shares to drive letters with item-level targeting. The resource owns the whole `Drives.xml` file of the GPO.",
+		map[string]*schema.Schema{
+			"drive": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A drive map preference item. Items are applied in order.",
+				Elem: gppItemSchema(map[string]*schema.Schema{
+					"letter": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[A-Z]$`), "must be an upper case drive letter"),
+						Description:  "The drive letter, e.g. `S`.",
+					},
+					"path": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The UNC path of the share, e.g. `\\\\fileserver\\share`. Not used by the `delete` action.",
+					},
+					"label": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The label of the drive.",
+					},
+					"persistent": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Reconnect the drive at logon.",
+					},
+				}),
+			},
+		},
+	)
+}
+
+// getPreferenceDrivesFromResource returns the contents of the Drives.xml file described by the
+// resource, keeping the elements of the existing file the resource doesn't manage
+func getPreferenceDrivesFromResource(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error) {
+	current, err := gpp.ParseDrives(existing)
+	if err != nil {
+		return nil, err
+	}
+	drives := gpp.NewDrives()
+	drives.Unsupported = current.Unsupported
+	for idx, v := range d.Get("drive").([]interface{}) {
+		drive := v.(map[string]interface{})
+		letter := drive["letter"].(string)
+		item, action, filters, err := expandGPPItem(drive, gpp.DriveClsid, letter+":", gppItemUserContext(d, configuration, "drive", idx), changed)
+		if err != nil {
+			return nil, err
+		}
+		drives.Drives = append(drives.Drives, gpp.Drive{
+			Item: item,
+			Properties: gpp.DriveProperties{
+				Action:     action,
+				ThisDrive:  "NOCHANGE",
+				AllDrives:  "NOCHANGE",
+				Path:       drive["path"].(string),
+				Label:      drive["label"].(string),
+				Persistent: gpp.Bool(drive["persistent"].(bool)),
+				UseLetter:  true,
+				Letter:     letter,
+			},
+			Filters: filters,
+		})
+	}
+	// items that didn't change keep their uid, so clients don't apply them again
+	err = drives.KeepUnchanged(current)
+	if err != nil {
+		return nil, err
+	}
+	return drives.Encode()
+}
+
+// setPreferenceDrivesResourceData sets the drive blocks of the resource from a Drives.xml file
+func setPreferenceDrivesResourceData(d *schema.ResourceData, contents []byte) error {
+	drives, err := gpp.ParseDrives(contents)
+	if err != nil {
+		return err
+	}
+	logUnsupportedPreferences(winrmhelper.PreferenceDrives, drives.Unsupported)
+
+	driveList := []interface{}{}
+	for _, v := range drives.Drives {
+		drive, err := flattenGPPItem(v.Item, v.Properties.Action, v.Filters)
+		if err != nil {
+			return err
+		}
+		drive["letter"] = v.Properties.Letter
+		drive["path"] = v.Properties.Path
+		drive["label"] = v.Properties.Label
+		drive["persistent"] = bool(v.Properties.Persistent)
+		driveList = append(driveList, drive)
+	}
+	return d.Set("drive", driveList)
+}
@@ -0,0 +1,70 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADGPOPreferenceDrives_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gppdrives")
+	resourceName := "windowsad_gpo_preference_drives.pref"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOPreferencesExists(gpoPreferenceDrives, resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOPreferenceDrivesConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOPreferencesExists(gpoPreferenceDrives, resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "drive.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "drive.1.action", "delete"),
+					resource.TestCheckResourceAttr(resourceName, "drive.0.run_in_user_context", "true"),
+					resource.TestCheckResourceAttr(resourceName, "drive.1.run_in_user_context", "false"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOPreferenceDrivesConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_preference_drives" "pref" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  drive {
+    letter     = "S"
+    path       = "\\\\fileserver\\share"
+    label      = "Share"
+    persistent = true
+    action     = "replace"
+  }
+
+  drive {
+    letter              = "T"
+    action              = "delete"
+    run_in_user_context = false
+  }
+}
+`, gpoName, domain)
+}
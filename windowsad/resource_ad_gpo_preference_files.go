@@ -0,0 +1,128 @@
+package windowsad
+
+import (
+	"strings"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var gpoPreferenceFiles = &gpoPreferences{
+	kind: winrmhelper.PreferenceFiles,
+	idSuffixes: map[string]string{
+		winrmhelper.ComputerConfiguration: "preferencefiles",
+		winrmhelper.UserConfiguration:     "userpreferencefiles",
+	},
+	encode: getPreferenceFilesFromResource,
+	read:   setPreferenceFilesResourceData,
+}
+
+func resourceADGPOPreferenceFiles() *schema.Resource {
+	return gpoPreferenceFiles.resource(
+		"`windowsad_gpo_preference_files` manages the files preferences of the computer or user configuration of a Group Policy Object (GPO), copying files to the computers the GPO applies to with item-level targeting. The resource owns the whole `Files.xml` file of the configuration.",
+		map[string]*schema.Schema{
+			"file": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A file preference item. Items are applied in order.",
+				Elem: gppItemSchema(map[string]*schema.Schema{
+					"source": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The path the file is copied from, usually a UNC path readable by the computer accounts. Not used by the `delete` action.",
+					},
+					"target": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The path the file is copied to, e.g. `C:\\ProgramData\\Contoso\\settings.json`.",
+					},
+					"read_only": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Set the read-only attribute of the file.",
+					},
+					"hidden": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Set the hidden attribute of the file.",
+					},
+					"archive": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Set the archive attribute of the file.",
+					},
+				}),
+			},
+		},
+	)
+}
+
+// getPreferenceFilesFromResource returns the contents of the Files.xml file described by the
+// resource, keeping the elements of the existing file the resource doesn't manage
+func getPreferenceFilesFromResource(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error) {
+	current, err := gpp.ParseFiles(existing)
+	if err != nil {
+		return nil, err
+	}
+	files := gpp.NewFiles()
+	files.Unsupported = current.Unsupported
+	for idx, v := range d.Get("file").([]interface{}) {
+		file := v.(map[string]interface{})
+		target := file["target"].(string)
+		// items are named after the file they copy
+		name := target[strings.LastIndex(target, `\`)+1:]
+		item, action, filters, err := expandGPPItem(file, gpp.FileClsid, name, gppItemUserContext(d, configuration, "file", idx), changed)
+		if err != nil {
+			return nil, err
+		}
+		files.Files = append(files.Files, gpp.File{
+			Item: item,
+			Properties: gpp.FileProperties{
+				Action:     action,
+				FromPath:   file["source"].(string),
+				TargetPath: target,
+				ReadOnly:   gpp.Bool(file["read_only"].(bool)),
+				Archive:    gpp.Bool(file["archive"].(bool)),
+				Hidden:     gpp.Bool(file["hidden"].(bool)),
+			},
+			Filters: filters,
+		})
+	}
+	// items that didn't change keep their uid, so clients don't apply them again
+	err = files.KeepUnchanged(current)
+	if err != nil {
+		return nil, err
+	}
+	return files.Encode()
+}
+
+// setPreferenceFilesResourceData sets the file blocks of the resource from a Files.xml file
+func setPreferenceFilesResourceData(d *schema.ResourceData, contents []byte) error {
+	files, err := gpp.ParseFiles(contents)
+	if err != nil {
+		return err
+	}
+	logUnsupportedPreferences(winrmhelper.PreferenceFiles, files.Unsupported)
+
+	fileList := []interface{}{}
+	for _, v := range files.Files {
+		file, err := flattenGPPItem(v.Item, v.Properties.Action, v.Filters)
+		if err != nil {
+			return err
+		}
+		file["source"] = v.Properties.FromPath
+		file["target"] = v.Properties.TargetPath
+		file["read_only"] = bool(v.Properties.ReadOnly)
+		file["hidden"] = bool(v.Properties.Hidden)
+		file["archive"] = bool(v.Properties.Archive)
+		fileList = append(fileList, file)
+	}
+	return d.Set("file", fileList)
+}
@@ -0,0 +1,60 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADGPOPreferenceFiles_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gppfiles")
+	resourceName := "windowsad_gpo_preference_files.pref"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOPreferencesExists(gpoPreferenceFiles, resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOPreferenceFilesConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOPreferencesExists(gpoPreferenceFiles, resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "file.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "file.0.read_only", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOPreferenceFilesConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_preference_files" "pref" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  file {
+    source    = "\\\\fileserver\\share\\settings.json"
+    target    = "C:\\ProgramData\\Contoso\\settings.json"
+    read_only = true
+  }
+}
+`, gpoName, domain)
+}
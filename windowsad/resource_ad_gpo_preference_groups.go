@@ -0,0 +1,183 @@
+package windowsad
+
+import (
+	"strings"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var gpoPreferenceGroups = &gpoPreferences{
+	kind: winrmhelper.PreferenceGroups,
+	idSuffixes: map[string]string{
+		winrmhelper.ComputerConfiguration: "preferencegroups",
+		winrmhelper.UserConfiguration:     "userpreferencegroups",
+	},
+	encode: getPreferenceGroupsFromResource,
+	read:   setPreferenceGroupsResourceData,
+}
+
+func resourceADGPOPreferenceGroups() *schema.Resource {
+	return gpoPreferenceGroups.resource(
+		"`windowsad_gpo_preference_groups` manages the local groups preferences of the computer or user configuration of a Group Policy Object (GPO), adding and removing members of local groups with item-level targeting. The resource owns the whole `Groups.xml` file of the configuration; local users are not supported and are kept as they are.",
+		map[string]*schema.Schema{
+			"group": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A local group preference item. Items are applied in order.",
+				Elem: gppItemSchema(map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The name of the local group, e.g. `Administrators (built-in)`.",
+					},
+					"sid": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The SID of the local group, used for the built-in groups whose name depends on the language of the computer, e.g. `S-1-5-32-544` for the Administrators group.",
+					},
+					"new_name": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "Renames the group.",
+					},
+					"description": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The description of the group.",
+					},
+					"delete_all_users": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Remove the user members of the group that aren't listed.",
+					},
+					"delete_all_groups": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Remove the group members of the group that aren't listed.",
+					},
+					"member": {
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "An account added to or removed from the group.",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"name": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The name of the account, e.g. `CONTOSO\\Workstation Admins`.",
+								},
+								"sid": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Default:     "",
+									Description: "The SID of the account.",
+								},
+								"action": {
+									Type:         schema.TypeString,
+									Optional:     true,
+									Default:      "add",
+									ValidateFunc: validation.StringInSlice([]string{"add", "remove"}, false),
+									Description:  "Whether the account is added to or removed from the group, `add` or `remove`.",
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+	)
+}
+
+// getPreferenceGroupsFromResource returns the contents of the Groups.xml file described by the
+// resource, keeping the elements of the existing file the resource doesn't manage
+func getPreferenceGroupsFromResource(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error) {
+	current, err := gpp.ParseGroups(existing)
+	if err != nil {
+		return nil, err
+	}
+	groups := gpp.NewGroups()
+	groups.Unsupported = current.Unsupported
+	for idx, g := range d.Get("group").([]interface{}) {
+		group := g.(map[string]interface{})
+		item, action, filters, err := expandGPPItem(group, gpp.GroupClsid, group["name"].(string), gppItemUserContext(d, configuration, "group", idx), changed)
+		if err != nil {
+			return nil, err
+		}
+
+		members := []gpp.GroupMember{}
+		for _, m := range group["member"].([]interface{}) {
+			member := m.(map[string]interface{})
+			members = append(members, gpp.GroupMember{
+				Name:   member["name"].(string),
+				Action: strings.ToUpper(member["action"].(string)),
+				Sid:    member["sid"].(string),
+			})
+		}
+		properties := gpp.GroupProperties{
+			Action:          action,
+			NewName:         group["new_name"].(string),
+			Description:     group["description"].(string),
+			DeleteAllUsers:  gpp.Bool(group["delete_all_users"].(bool)),
+			DeleteAllGroups: gpp.Bool(group["delete_all_groups"].(bool)),
+			GroupSid:        group["sid"].(string),
+			GroupName:       group["name"].(string),
+		}
+		if len(members) > 0 {
+			properties.Members = &gpp.GroupMembers{Members: members}
+		}
+
+		groups.Groups = append(groups.Groups, gpp.Group{Item: item, Properties: properties, Filters: filters})
+	}
+	// items that didn't change keep their uid, so clients don't apply them again
+	err = groups.KeepUnchanged(current)
+	if err != nil {
+		return nil, err
+	}
+	return groups.Encode()
+}
+
+// setPreferenceGroupsResourceData sets the group blocks of the resource from a Groups.xml file
+func setPreferenceGroupsResourceData(d *schema.ResourceData, contents []byte) error {
+	groups, err := gpp.ParseGroups(contents)
+	if err != nil {
+		return err
+	}
+	logUnsupportedPreferences(winrmhelper.PreferenceGroups, groups.Unsupported)
+
+	groupList := []interface{}{}
+	for _, g := range groups.Groups {
+		group, err := flattenGPPItem(g.Item, g.Properties.Action, g.Filters)
+		if err != nil {
+			return err
+		}
+		members := []interface{}{}
+		if g.Properties.Members != nil {
+			for _, m := range g.Properties.Members.Members {
+				members = append(members, map[string]interface{}{
+					"name":   m.Name,
+					"sid":    m.Sid,
+					"action": strings.ToLower(m.Action),
+				})
+			}
+		}
+		group["name"] = g.Properties.GroupName
+		group["sid"] = g.Properties.GroupSid
+		group["new_name"] = g.Properties.NewName
+		group["description"] = g.Properties.Description
+		group["delete_all_users"] = bool(g.Properties.DeleteAllUsers)
+		group["delete_all_groups"] = bool(g.Properties.DeleteAllGroups)
+		group["member"] = members
+		groupList = append(groupList, group)
+	}
+	return d.Set("group", groupList)
+}
@@ -0,0 +1,83 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADGPOPreferenceGroups_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gppgroups")
+	resourceName := "windowsad_gpo_preference_groups.pref"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOPreferencesExists(gpoPreferenceGroups, resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOPreferenceGroupsConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOPreferencesExists(gpoPreferenceGroups, resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "group.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "group.0.member.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOPreferenceGroupsConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_preference_groups" "pref" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  group {
+    name              = "Remote Desktop Users (built-in)"
+    sid               = "S-1-5-32-555"
+    delete_all_users  = true
+    delete_all_groups = true
+
+    member {
+      name   = "NT AUTHORITY\\Authenticated Users"
+      sid    = "S-1-5-11"
+      action = "remove"
+    }
+
+    member {
+      name = "NT AUTHORITY\\INTERACTIVE"
+      sid  = "S-1-5-4"
+    }
+
+    filter {
+      type = "FilterOs"
+      attributes = {
+        class   = "NT"
+        version = "WIN10"
+        type    = "NE"
+        edition = "NE"
+        sp      = "NE"
+      }
+    }
+  }
+}
+`, gpoName, domain)
+}
@@ -0,0 +1,145 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var gpoPreferenceRegistry = &gpoPreferences{
+	kind: winrmhelper.PreferenceRegistry,
+	idSuffixes: map[string]string{
+		winrmhelper.ComputerConfiguration: "preferenceregistry",
+		winrmhelper.UserConfiguration:     "userpreferenceregistry",
+	},
+	encode: getPreferenceRegistryFromResource,
+	read:   setPreferenceRegistryResourceData,
+}
+
+func resourceADGPOPreferenceRegistry() *schema.Resource {
+	return gpoPreferenceRegistry.resource(
+		"`windowsad_gpo_preference_registry` manages the registry preferences of the computer or user configuration of a Group Policy Object (GPO). Unlike the administrative templates of `windowsad_gpo_registry_policy`, registry preferences can write to any key and support item-level targeting. The resource owns the whole `Registry.xml` file of the configuration; collections are not supported and are kept as they are.",
+		map[string]*schema.Schema{
+			"value": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A registry preference item. Items are applied in order.",
+				Elem: gppItemSchema(map[string]*schema.Schema{
+					"hive": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringInSlice(gpp.RegistryHives, false),
+						Description:  fmt.Sprintf("The hive of the key. Valid values are `%s`.", strings.Join(gpp.RegistryHives, "`, `")),
+					},
+					"key": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The registry key, relative to the hive, e.g. `SOFTWARE\\Contoso\\Agent`.",
+					},
+					"value_name": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The name of the value. If empty, the default value of the key is set.",
+					},
+					"type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "REG_SZ",
+						ValidateFunc: validation.StringInSlice(gpp.RegistryTypes, false),
+						Description:  fmt.Sprintf("The type of the value. Valid values are `%s`.", strings.Join(gpp.RegistryTypes, "`, `")),
+					},
+					"value": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The data of the value. Numbers are in decimal and binary data in lowercase hexadecimal.",
+					},
+				}),
+			},
+		},
+	)
+}
+
+// getPreferenceRegistryFromResource returns the contents of the Registry.xml file described by
+// the resource, keeping the elements of the existing file the resource doesn't manage
+func getPreferenceRegistryFromResource(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error) {
+	current, err := gpp.ParseRegistrySettings(existing)
+	if err != nil {
+		return nil, err
+	}
+	settings := gpp.NewRegistrySettings()
+	settings.Unsupported = current.Unsupported
+	for idx, v := range d.Get("value").([]interface{}) {
+		value := v.(map[string]interface{})
+		valueName := value["value_name"].(string)
+		name := valueName
+		if name == "" {
+			name = "(Default)"
+		}
+		item, action, filters, err := expandGPPItem(value, gpp.RegistryClsid, name, gppItemUserContext(d, configuration, "value", idx), changed)
+		if err != nil {
+			return nil, err
+		}
+		valueType := value["type"].(string)
+		data, err := gpp.EncodeRegistryValue(valueType, value["value"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("value %q of key %q: %s", valueName, value["key"].(string), err)
+		}
+		settings.Registry = append(settings.Registry, gpp.Registry{
+			Item: item,
+			Properties: gpp.RegistryProperties{
+				Action:         action,
+				DisplayDecimal: valueType == "REG_DWORD" || valueType == "REG_QWORD",
+				Default:        valueName == "",
+				Hive:           value["hive"].(string),
+				Key:            value["key"].(string),
+				Name:           valueName,
+				Type:           valueType,
+				Value:          data,
+			},
+			Filters: filters,
+		})
+	}
+	// items that didn't change keep their uid, so clients don't apply them again
+	err = settings.KeepUnchanged(current)
+	if err != nil {
+		return nil, err
+	}
+	return settings.Encode()
+}
+
+// setPreferenceRegistryResourceData sets the value blocks of the resource from a Registry.xml
+// file
+func setPreferenceRegistryResourceData(d *schema.ResourceData, contents []byte) error {
+	settings, err := gpp.ParseRegistrySettings(contents)
+	if err != nil {
+		return err
+	}
+	logUnsupportedPreferences(winrmhelper.PreferenceRegistry, settings.Unsupported)
+
+	valueList := []interface{}{}
+	for _, v := range settings.Registry {
+		value, err := flattenGPPItem(v.Item, v.Properties.Action, v.Filters)
+		if err != nil {
+			return err
+		}
+		data, err := gpp.DecodeRegistryValue(v.Properties.Type, v.Properties.Value)
+		if err != nil {
+			return fmt.Errorf("value %q of key %q: %s", v.Properties.Name, v.Properties.Key, err)
+		}
+		value["hive"] = v.Properties.Hive
+		value["key"] = v.Properties.Key
+		value["value_name"] = v.Properties.Name
+		value["type"] = v.Properties.Type
+		value["value"] = data
+		valueList = append(valueList, value)
+	}
+	return d.Set("value", valueList)
+}
@@ -0,0 +1,69 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADGPOPreferenceRegistry_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gppreg")
+	resourceName := "windowsad_gpo_preference_registry.pref"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOPreferencesExists(gpoPreferenceRegistry, resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOPreferenceRegistryConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOPreferencesExists(gpoPreferenceRegistry, resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "value.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "value.0.value", "42"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOPreferenceRegistryConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_preference_registry" "pref" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  value {
+    hive       = "HKEY_LOCAL_MACHINE"
+    key        = "SOFTWARE\\Contoso\\Agent"
+    value_name = "Port"
+    type       = "REG_DWORD"
+    value      = "42"
+  }
+
+  value {
+    hive   = "HKEY_LOCAL_MACHINE"
+    key    = "SOFTWARE\\Contoso\\Agent"
+    value  = "default"
+    action = "replace"
+  }
+}
+`, gpoName, domain)
+}
@@ -0,0 +1,311 @@
+package windowsad
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var gpoPreferenceScheduledTasks = &gpoPreferences{
+	kind: winrmhelper.PreferenceScheduledTasks,
+	idSuffixes: map[string]string{
+		winrmhelper.ComputerConfiguration: "preferencescheduledtasks",
+		winrmhelper.UserConfiguration:     "userpreferencescheduledtasks",
+	},
+	encode: getPreferenceScheduledTasksFromResource,
+	read:   setPreferenceScheduledTasksResourceData,
+}
+
+// scheduledTaskTriggerTypes lists the supported trigger types
+var scheduledTaskTriggerTypes = []string{"boot", "logon", "daily", "once"}
+
+func resourceADGPOPreferenceScheduledTasks() *schema.Resource {
+	return gpoPreferenceScheduledTasks.resource(
+		"`windowsad_gpo_preference_scheduled_tasks` manages the scheduled tasks preferences of the computer or user configuration of a Group Policy Object (GPO), with item-level targeting. The resource owns the whole `ScheduledTasks.xml` file of the configuration; only the scheduled tasks of Windows Vista and later are supported, the other tasks being kept as they are, and tasks run without a stored password.",
+		map[string]*schema.Schema{
+			"task": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "A scheduled task preference item. Items are applied in order.",
+				Elem: gppItemSchema(map[string]*schema.Schema{
+					"name": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The name of the task.",
+					},
+					"description": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "",
+						Description: "The description of the task.",
+					},
+					"run_as": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     `NT AUTHORITY\System`,
+						Description: "The account the task runs as, e.g. `NT AUTHORITY\\System` or `%LogonDomain%\\%LogonUser%` for the logged-on user.",
+					},
+					"logon_type": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "S4U",
+						ValidateFunc: validation.StringInSlice([]string{"S4U", "InteractiveToken"}, false),
+						Description:  "How the account logs on: `S4U` runs the task whether the user is logged on or not, `InteractiveToken` only when the user is logged on.",
+					},
+					"run_level": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "HighestAvailable",
+						ValidateFunc: validation.StringInSlice([]string{"LeastPrivilege", "HighestAvailable"}, false),
+						Description:  "The privileges of the task, `LeastPrivilege` or `HighestAvailable`.",
+					},
+					"enabled": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     true,
+						Description: "Whether the task is enabled.",
+					},
+					"execution_time_limit": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Default:     "P3D",
+						Description: "The time after which the task is stopped, as an ISO 8601 duration, e.g. `PT1H`.",
+					},
+					"trigger": {
+						Type:        schema.TypeSet,
+						Optional:    true,
+						Description: "When the task starts.",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"type": {
+									Type:         schema.TypeString,
+									Required:     true,
+									ValidateFunc: validation.StringInSlice(scheduledTaskTriggerTypes, false),
+									Description:  "The type of trigger: `boot`, `logon`, `daily` or `once`.",
+								},
+								"start_boundary": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Default:     "",
+									Description: "The local time the trigger is activated, e.g. `2024-01-01T03:00:00`. Required for `daily` and `once` triggers.",
+								},
+								"days_interval": {
+									Type:         schema.TypeInt,
+									Optional:     true,
+									Default:      1,
+									ValidateFunc: validation.IntAtLeast(1),
+									Description:  "The number of days between runs of a `daily` trigger.",
+								},
+								"delay": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Default:     "",
+									Description: "The delay of a `boot` or `logon` trigger, as an ISO 8601 duration, e.g. `PT5M`.",
+								},
+							},
+						},
+					},
+					"exec": {
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Description: "A program run by the task. Programs run in order.",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"command": {
+									Type:        schema.TypeString,
+									Required:    true,
+									Description: "The program to run, e.g. `powershell.exe`.",
+								},
+								"arguments": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Default:     "",
+									Description: "The arguments of the program.",
+								},
+								"working_directory": {
+									Type:        schema.TypeString,
+									Optional:    true,
+									Default:     "",
+									Description: "The directory the program runs in.",
+								},
+							},
+						},
+					},
+				}),
+			},
+		},
+	)
+}
+
+// getScheduledTaskTriggers returns the triggers of a task block
+func getScheduledTaskTriggers(triggers *schema.Set) (gpp.TaskTriggers, error) {
+	result := gpp.TaskTriggers{}
+	for _, t := range triggers.List() {
+		trigger := t.(map[string]interface{})
+		startBoundary := trigger["start_boundary"].(string)
+		switch trigger["type"].(string) {
+		case "boot":
+			result.Boot = append(result.Boot, gpp.BootTrigger{Enabled: true, Delay: trigger["delay"].(string)})
+		case "logon":
+			result.Logon = append(result.Logon, gpp.LogonTrigger{Enabled: true, Delay: trigger["delay"].(string)})
+		case "daily":
+			if startBoundary == "" {
+				return result, fmt.Errorf("daily triggers require a start_boundary")
+			}
+			result.Calendar = append(result.Calendar, gpp.CalendarTrigger{
+				StartBoundary: startBoundary,
+				Enabled:       true,
+				ScheduleByDay: &gpp.ScheduleByDay{DaysInterval: trigger["days_interval"].(int)},
+			})
+		case "once":
+			if startBoundary == "" {
+				return result, fmt.Errorf("once triggers require a start_boundary")
+			}
+			result.Time = append(result.Time, gpp.TimeTrigger{StartBoundary: startBoundary, Enabled: true})
+		}
+	}
+	return result, nil
+}
+
+// getPreferenceScheduledTasksFromResource returns the contents of the ScheduledTasks.xml file
+// described by the resource, keeping the elements of the existing file the resource doesn't manage
+func getPreferenceScheduledTasksFromResource(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error) {
+	current, err := gpp.ParseScheduledTasks(existing)
+	if err != nil {
+		return nil, err
+	}
+	tasks := gpp.NewScheduledTasks()
+	tasks.Unsupported = current.Unsupported
+	for idx, v := range d.Get("task").([]interface{}) {
+		task := v.(map[string]interface{})
+		name := task["name"].(string)
+		item, action, filters, err := expandGPPItem(task, gpp.TaskV2Clsid, name, gppItemUserContext(d, configuration, "task", idx), changed)
+		if err != nil {
+			return nil, err
+		}
+
+		runAs := task["run_as"].(string)
+		logonType := task["logon_type"].(string)
+		definition := gpp.NewTask(gpp.TaskPrincipal{
+			UserID:    runAs,
+			LogonType: logonType,
+			RunLevel:  task["run_level"].(string),
+		}, task["enabled"].(bool), task["execution_time_limit"].(string))
+		definition.RegistrationInfo.Description = task["description"].(string)
+		definition.Triggers, err = getScheduledTaskTriggers(task["trigger"].(*schema.Set))
+		if err != nil {
+			return nil, fmt.Errorf("task %q: %s", name, err)
+		}
+		for _, e := range task["exec"].([]interface{}) {
+			exec := e.(map[string]interface{})
+			definition.Actions.Exec = append(definition.Actions.Exec, gpp.ExecAction{
+				Command:          exec["command"].(string),
+				Arguments:        exec["arguments"].(string),
+				WorkingDirectory: exec["working_directory"].(string),
+			})
+		}
+
+		tasks.Tasks = append(tasks.Tasks, gpp.TaskV2{
+			Item: item,
+			Properties: gpp.TaskProperties{
+				Action:    action,
+				Name:      name,
+				RunAs:     runAs,
+				LogonType: logonType,
+				Task:      definition,
+			},
+			Filters: filters,
+		})
+	}
+	// items that didn't change keep their uid, so clients don't apply them again
+	err = tasks.KeepUnchanged(current)
+	if err != nil {
+		return nil, err
+	}
+	return tasks.Encode()
+}
+
+// flattenScheduledTaskTriggers returns the trigger blocks of a task. Calendar triggers that don't
+// run daily are not supported and left out.
+func flattenScheduledTaskTriggers(name string, triggers gpp.TaskTriggers) []interface{} {
+	result := []interface{}{}
+	newTrigger := func(triggerType string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":           triggerType,
+			"start_boundary": "",
+			"days_interval":  1,
+			"delay":          "",
+		}
+	}
+	for _, t := range triggers.Boot {
+		trigger := newTrigger("boot")
+		trigger["delay"] = t.Delay
+		result = append(result, trigger)
+	}
+	for _, t := range triggers.Logon {
+		trigger := newTrigger("logon")
+		trigger["delay"] = t.Delay
+		result = append(result, trigger)
+	}
+	for _, t := range triggers.Calendar {
+		if t.ScheduleByDay == nil {
+			log.Printf("[WARN] ignoring unsupported calendar trigger of task %q", name)
+			continue
+		}
+		trigger := newTrigger("daily")
+		trigger["start_boundary"] = t.StartBoundary
+		trigger["days_interval"] = t.ScheduleByDay.DaysInterval
+		result = append(result, trigger)
+	}
+	for _, t := range triggers.Time {
+		trigger := newTrigger("once")
+		trigger["start_boundary"] = t.StartBoundary
+		result = append(result, trigger)
+	}
+	return result
+}
+
+// setPreferenceScheduledTasksResourceData sets the task blocks of the resource from a
+// ScheduledTasks.xml file
+func setPreferenceScheduledTasksResourceData(d *schema.ResourceData, contents []byte) error {
+	tasks, err := gpp.ParseScheduledTasks(contents)
+	if err != nil {
+		return err
+	}
+	logUnsupportedPreferences(winrmhelper.PreferenceScheduledTasks, tasks.Unsupported)
+
+	taskList := []interface{}{}
+	for _, v := range tasks.Tasks {
+		task, err := flattenGPPItem(v.Item, v.Properties.Action, v.Filters)
+		if err != nil {
+			return err
+		}
+		definition := v.Properties.Task
+		execs := []interface{}{}
+		for _, e := range definition.Actions.Exec {
+			execs = append(execs, map[string]interface{}{
+				"command":           e.Command,
+				"arguments":         e.Arguments,
+				"working_directory": e.WorkingDirectory,
+			})
+		}
+		task["name"] = v.Properties.Name
+		task["description"] = definition.RegistrationInfo.Description
+		task["run_as"] = definition.Principals.Principal.UserID
+		task["logon_type"] = definition.Principals.Principal.LogonType
+		task["run_level"] = definition.Principals.Principal.RunLevel
+		task["enabled"] = definition.Settings.IsEnabled()
+		task["execution_time_limit"] = definition.Settings.ExecutionTimeLimit
+		task["trigger"] = flattenScheduledTaskTriggers(v.Properties.Name, definition.Triggers)
+		task["exec"] = execs
+		taskList = append(taskList, task)
+	}
+	return d.Set("task", taskList)
+}
@@ -0,0 +1,74 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADGPOPreferenceScheduledTasks_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gpptasks")
+	resourceName := "windowsad_gpo_preference_scheduled_tasks.pref"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOPreferencesExists(gpoPreferenceScheduledTasks, resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOPreferenceScheduledTasksConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOPreferencesExists(gpoPreferenceScheduledTasks, resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "task.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "task.0.trigger.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOPreferenceScheduledTasksConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_preference_scheduled_tasks" "pref" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  task {
+    name   = "Cleanup"
+    action = "replace"
+
+    trigger {
+      type  = "boot"
+      delay = "PT5M"
+    }
+
+    trigger {
+      type           = "daily"
+      start_boundary = "2024-01-01T03:00:00"
+    }
+
+    exec {
+      command   = "cleanmgr.exe"
+      arguments = "/sagerun:1"
+    }
+  }
+}
+`, gpoName, domain)
+}
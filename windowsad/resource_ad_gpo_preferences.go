@@ -0,0 +1,409 @@
+package windowsad
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/gpp"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// gpoPreferences describes a resource owning the XML file of a kind of Group Policy Preferences
+// of a GPO configuration. The items of the file are converted from and to the resource data by
+// encode and read. encode is given the configuration of the preferences and the current contents
+// of the file, or nil if there is none, to keep the elements the resource doesn't manage.
+type gpoPreferences struct {
+	kind string
+	// idSuffixes maps the configurations the preferences can be set for to the suffix of the
+	// resource ID
+	idSuffixes map[string]string
+	encode     func(d *schema.ResourceData, configuration string, existing []byte, changed time.Time) ([]byte, error)
+	read       func(d *schema.ResourceData, contents []byte) error
+}
+
+// resource returns the resource managing the preferences, items being the schema of its item
+// blocks
+func (p *gpoPreferences) resource(description string, items map[string]*schema.Schema) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"gpo_container": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The GUID of the container the preferences belong to.",
+		},
+	}
+	if len(p.idSuffixes) > 1 {
+		s["configuration"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Default:      winrmhelper.ComputerConfiguration,
+			ValidateFunc: validation.StringInSlice(winrmhelper.GPOConfigurations, false),
+			Description:  "The configuration the preferences apply to, `computer` or `user`.",
+		}
+	}
+	for k, v := range items {
+		s[k] = v
+	}
+
+	return &schema.Resource{
+		Description: description,
+		Create:      p.create,
+		Read:        p.readResource,
+		Update:      p.update,
+		Delete:      p.delete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: s,
+	}
+}
+
+// configuration returns the configuration of the preferences of a resource
+func (p *gpoPreferences) configuration(d *schema.ResourceData) string {
+	if len(p.idSuffixes) > 1 {
+		return d.Get("configuration").(string)
+	}
+	for configuration := range p.idSuffixes {
+		return configuration
+	}
+	return ""
+}
+
+// parseID returns the GUID of the GPO and the configuration of a resource ID
+func (p *gpoPreferences) parseID(resourceID string) (string, string, error) {
+	toks := strings.Split(resourceID, "_")
+	if len(toks) == 2 {
+		for configuration, suffix := range p.idSuffixes {
+			if toks[1] == suffix {
+				return toks[0], configuration, nil
+			}
+		}
+	}
+	suffixes := []string{}
+	for _, suffix := range p.idSuffixes {
+		suffixes = append(suffixes, "<guid>_"+suffix)
+	}
+	sort.Strings(suffixes)
+	return "", "", fmt.Errorf("resource ID %q does not match %s", resourceID, strings.Join(suffixes, " or "))
+}
+
+func (p *gpoPreferences) create(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	if guid == "" {
+		return fmt.Errorf("Cannot handle empty GPO GUID")
+	}
+	parsedGUID, err := uuid.ParseUUID(guid)
+	if err != nil {
+		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
+	}
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
+
+	configuration := p.configuration(d)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.existingContents(meta.(*config.ProviderConf), gpo, configuration)
+	if err != nil {
+		return err
+	}
+	contents, err := p.encode(d, configuration, existing, time.Now())
+	if err != nil {
+		return fmt.Errorf("error while generating %s preferences from resource data: %s", p.kind, err)
+	}
+
+	err = winrmhelper.UploadPreference(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration, p.kind, contents)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.AddExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetPreferenceExtensionNames(p.kind))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s_%s", guid, p.idSuffixes[configuration]))
+
+	return p.readResource(d, meta)
+}
+
+// existingContents returns the contents of the preferences file of a GPO configuration, or nil if
+// the file doesn't exist
+func (p *gpoPreferences) existingContents(conf *config.ProviderConf, gpo *winrmhelper.GPO, configuration string) ([]byte, error) {
+	contents, found, err := winrmhelper.GetPreferenceContents(conf, gpo, configuration, p.kind)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving the %s preferences of GPO with guid %q: %s", p.kind, gpo.ID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return contents, nil
+}
+
+func (p *gpoPreferences) readResource(d *schema.ResourceData, meta interface{}) error {
+	guid, configuration, err := p.parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] GPO with guid %q not found", guid)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	_ = d.Set("gpo_container", guid)
+	if len(p.idSuffixes) > 1 {
+		_ = d.Set("configuration", configuration)
+	}
+
+	contents, found, err := winrmhelper.GetPreferenceContents(meta.(*config.ProviderConf), gpo, configuration, p.kind)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Printf("[DEBUG] %s.xml file not found, marking resource as gone", p.kind)
+		d.SetId("")
+		return nil
+	}
+
+	err = p.read(d, contents)
+	if err != nil {
+		return fmt.Errorf("error while reading %s preferences of GPO with guid %q: %s", p.kind, guid, err)
+	}
+	return nil
+}
+
+func (p *gpoPreferences) update(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	configuration := p.configuration(d)
+	existing, err := p.existingContents(meta.(*config.ProviderConf), gpo, configuration)
+	if err != nil {
+		return err
+	}
+	contents, err := p.encode(d, configuration, existing, time.Now())
+	if err != nil {
+		return fmt.Errorf("error while generating %s preferences from resource data: %s", p.kind, err)
+	}
+
+	// Items carry the time they were changed, so the file is written on every update instead of
+	// being compared with the one on the host.
+	err = winrmhelper.UploadPreference(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration, p.kind, contents)
+	if err != nil {
+		return fmt.Errorf("error while uploading %s preferences file for GPO with guid %q: %s", p.kind, guid, err)
+	}
+	return p.readResource(d, meta)
+}
+
+func (p *gpoPreferences) delete(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid, configuration, err := p.parseID(d.Id())
+	if err != nil {
+		return err
+	}
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemovePreference(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration, p.kind)
+	if err != nil {
+		return fmt.Errorf("error while removing %s preferences file for GPO with guid %q: %s", p.kind, guid, err)
+	}
+
+	err = winrmhelper.RemoveExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetPreferenceExtensionNames(p.kind))
+	if err != nil {
+		return fmt.Errorf("error while unregistering %s preferences extension for GPO with guid %q: %s", p.kind, guid, err)
+	}
+	return nil
+}
+
+// gppItemSchema returns the schema of the item blocks of a preferences resource: the given fields
+// and the ones common to all items, the action and the item-level targeting.
+func gppItemSchema(fields map[string]*schema.Schema) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"action": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "update",
+			ValidateFunc: validation.StringInSlice(gpp.ActionNames(), false),
+			Description:  fmt.Sprintf("What the item does on the computers the GPO applies to. Valid values are `%s`.", strings.Join(gpp.ActionNames(), "`, `")),
+		},
+		"remove_when_not_applied": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Remove the item when the GPO no longer applies. Only used with the `replace` action.",
+		},
+		"run_in_user_context": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Computed:    true,
+			Description: "Apply the item in the security context of the logged-on user. Only used in the user configuration. Like in the Group Policy editor, defaults to `true` in the user configuration and `false` in the computer configuration.",
+		},
+		"filter": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "An item-level targeting filter. The item is only applied where its filters match, the filters being evaluated in order.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.StringMatch(regexp.MustCompile(`^Filter[A-Za-z]+$`), "must be the element name of a filter, e.g. FilterGroup"),
+						Description:  "The type of filter, e.g. `FilterGroup`, `FilterComputer`, `FilterOs` or `FilterWmi`.",
+					},
+					"operator": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						Default:      "AND",
+						ValidateFunc: validation.StringInSlice([]string{"AND", "OR"}, false),
+						Description:  "How the filter is combined with the previous ones, `AND` or `OR`.",
+					},
+					"not": {
+						Type:        schema.TypeBool,
+						Optional:    true,
+						Default:     false,
+						Description: "Negate the filter.",
+					},
+					"attributes": {
+						Type:        schema.TypeMap,
+						Optional:    true,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+						Description: "The attributes of the filter, e.g. `name` and `sid` for a `FilterGroup`.",
+					},
+				},
+			},
+		},
+	}
+	for k, v := range fields {
+		s[k] = v
+	}
+	return &schema.Resource{Schema: s}
+}
+
+// gppItemUserContext returns whether the item at the given index of the blocks runs in the security
+// context of the logged-on user. If run_in_user_context isn't configured the Group Policy editor
+// default is used, which runs the items of the user configuration in the user context.
+func gppItemUserContext(d *schema.ResourceData, configuration, block string, index int) bool {
+	value := d.GetRawConfig().GetAttr(block).AsValueSlice()[index].GetAttr("run_in_user_context")
+	if value.IsNull() {
+		return configuration == winrmhelper.UserConfiguration
+	}
+	return value.True()
+}
+
+// expandGPPItem returns the common attributes, the action code and the filters of an item block
+func expandGPPItem(item map[string]interface{}, clsid, name string, userContext bool, changed time.Time) (gpp.Item, string, *gpp.Filters, error) {
+	action, err := gpp.ActionCode(item["action"].(string))
+	if err != nil {
+		return gpp.Item{}, "", nil, err
+	}
+	i, err := gpp.NewItem(clsid, name, action, changed)
+	if err != nil {
+		return gpp.Item{}, "", nil, err
+	}
+	i.RemovePolicy = gpp.Bool(item["remove_when_not_applied"].(bool))
+	i.UserContext = gpp.Bool(userContext)
+
+	filters := []gpp.Filter{}
+	for _, f := range item["filter"].([]interface{}) {
+		filter := f.(map[string]interface{})
+		attributes := map[string]string{}
+		for k, v := range filter["attributes"].(map[string]interface{}) {
+			attributes[k] = v.(string)
+		}
+		filters = append(filters, gpp.Filter{
+			Type:       filter["type"].(string),
+			Or:         filter["operator"].(string) == "OR",
+			Not:        filter["not"].(bool),
+			Attributes: attributes,
+		})
+	}
+	return i, action, gpp.NewFilters(filters), nil
+}
+
+// flattenGPPItem returns an item block with the common attributes of an item, to which the fields
+// specific to the kind of item are added
+func flattenGPPItem(i gpp.Item, action string, filters *gpp.Filters) (map[string]interface{}, error) {
+	actionName, err := gpp.ActionName(action)
+	if err != nil {
+		return nil, fmt.Errorf("item %q: %s", i.Name, err)
+	}
+	filterList := []interface{}{}
+	for _, f := range filters.List() {
+		attributes := map[string]interface{}{}
+		for k, v := range f.Attributes {
+			attributes[k] = v
+		}
+		operator := "AND"
+		if f.Or {
+			operator = "OR"
+		}
+		filterList = append(filterList, map[string]interface{}{
+			"type":       f.Type,
+			"operator":   operator,
+			"not":        f.Not,
+			"attributes": attributes,
+		})
+	}
+	return map[string]interface{}{
+		"action":                  actionName,
+		"remove_when_not_applied": bool(i.RemovePolicy),
+		"run_in_user_context":     bool(i.UserContext),
+		"filter":                  filterList,
+	}, nil
+}
+
+// logUnsupportedPreferences logs the elements of a preferences file the resource doesn't manage,
+// they are written back unchanged when the file is written
+func logUnsupportedPreferences(kind string, elements []gpp.Unsupported) {
+	for _, e := range elements {
+		log.Printf("[WARN] ignoring unsupported element %q of the %s preferences, it is kept as it is", e.XMLName.Local, kind)
+	}
+}
@@ -0,0 +1,44 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func testAccResourceADGPOPreferencesExists(p *gpoPreferences, resourceName string, desired bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		guid, configuration, err := p.parseID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		gpo, err := winrmhelper.GetGPOFromHost(testAccProvider.Meta().(*config.ProviderConf), "", guid)
+		if err != nil {
+			// if the GPO got destroyed first then the rest of the entities depending on it
+			// are also destroyed.
+			if !desired && strings.Contains(err.Error(), "NotFound") {
+				return nil
+			}
+			return err
+		}
+		_, found, err := winrmhelper.GetPreferenceContents(testAccProvider.Meta().(*config.ProviderConf), gpo, configuration, p.kind)
+		if err != nil {
+			return err
+		}
+		if found != desired {
+			return fmt.Errorf("%s.xml file of GPO %q exists: %t, expected: %t", p.kind, guid, found, desired)
+		}
+		return nil
+	}
+}
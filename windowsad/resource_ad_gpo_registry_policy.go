@@ -0,0 +1,332 @@
+package windowsad
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/preg"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADGPORegistryPolicy() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_gpo_registry_policy` manages the administrative template (registry) settings of the computer or user configuration of a Group Policy Object (GPO). The resource owns the whole `Registry.pol` file of the configuration.",
+		Create:      resourceADGPORegistryPolicyCreate,
+		Read:        resourceADGPORegistryPolicyRead,
+		Update:      resourceADGPORegistryPolicyUpdate,
+		Delete:      resourceADGPORegistryPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"gpo_container": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The GUID of the container the registry policy belongs to.",
+			},
+			"configuration": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      winrmhelper.ComputerConfiguration,
+				ValidateFunc: validation.StringInSlice(winrmhelper.GPOConfigurations, false),
+				Description:  "The configuration the settings apply to, `computer` or `user`. Computer settings are written to the `HKEY_LOCAL_MACHINE` hive of the computers the GPO applies to, user settings to the `HKEY_CURRENT_USER` hive of the users.",
+			},
+			"setting": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"setting", "delete"},
+				Description:  "A registry value set by the policy.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The registry key, relative to the hive of the configuration, e.g. `Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU`.",
+						},
+						"value_name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the registry value.",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(preg.TypeNames(), false),
+							Description:  fmt.Sprintf("The type of the registry value. Valid values are `%s`.", strings.Join(preg.TypeNames(), "`, `")),
+						},
+						"value": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "The data of the value. Numbers are in decimal and binary data in lowercase hexadecimal. Not used for `REG_MULTI_SZ` values.",
+						},
+						"values": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The strings of a `REG_MULTI_SZ` value.",
+						},
+					},
+				},
+			},
+			"delete": {
+				Type:         schema.TypeSet,
+				Optional:     true,
+				AtLeastOneOf: []string{"setting", "delete"},
+				Description:  "A registry value deleted by the policy on the computers it applies to.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The registry key, relative to the hive of the configuration.",
+						},
+						"value_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "",
+							Description: "The name of the value to delete. If empty, all the values of the key are deleted.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getRegistryPolicyFromResource returns the contents of the Registry.pol file described by the
+// resource, with its entries sorted the way the Group Policy editor writes them.
+func getRegistryPolicyFromResource(d *schema.ResourceData) ([]byte, error) {
+	entries := []preg.Entry{}
+	for _, s := range d.Get("setting").(*schema.Set).List() {
+		setting := s.(map[string]interface{})
+		values := []string{}
+		for _, v := range setting["values"].([]interface{}) {
+			values = append(values, v.(string))
+		}
+		entry, err := preg.NewEntry(setting["key"].(string), setting["value_name"].(string), setting["type"].(string), setting["value"].(string), values)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	for _, v := range d.Get("delete").(*schema.Set).List() {
+		del := v.(map[string]interface{})
+		entries = append(entries, preg.NewDeleteValueEntry(del["key"].(string), del["value_name"].(string)))
+	}
+	preg.Sort(entries)
+	return preg.Encode(entries), nil
+}
+
+// registryPolicyIDSuffixes maps the configurations to the suffix of the resource ID
+var registryPolicyIDSuffixes = map[string]string{
+	winrmhelper.ComputerConfiguration: "registrypolicy",
+	winrmhelper.UserConfiguration:     "userregistrypolicy",
+}
+
+// getRegistryPolicyGUID returns the GUID of the GPO and the configuration of a resource ID
+func getRegistryPolicyGUID(resourceID string) (string, string, error) {
+	toks := strings.Split(resourceID, "_")
+	if len(toks) == 2 {
+		for configuration, suffix := range registryPolicyIDSuffixes {
+			if toks[1] == suffix {
+				return toks[0], configuration, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("resource ID %q does not match <guid>_registrypolicy or <guid>_userregistrypolicy", resourceID)
+}
+
+func resourceADGPORegistryPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	if guid == "" {
+		return fmt.Errorf("Cannot handle empty GPO GUID")
+	}
+	parsedGUID, err := uuid.ParseUUID(guid)
+	if err != nil {
+		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
+	}
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
+	contents, err := getRegistryPolicyFromResource(d)
+	if err != nil {
+		return fmt.Errorf("error while generating registry policy from resource data: %s", err)
+	}
+
+	configuration := d.Get("configuration").(string)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.UploadRegistryPol(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration, contents)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.AddExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetRegistryPolicyExtensionNames(configuration))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s_%s", guid, registryPolicyIDSuffixes[configuration]))
+
+	return resourceADGPORegistryPolicyRead(d, meta)
+}
+
+func resourceADGPORegistryPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	guid, configuration, err := getRegistryPolicyGUID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] GPO with guid %q not found", guid)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	_ = d.Set("gpo_container", guid)
+	_ = d.Set("configuration", configuration)
+
+	contents, found, err := winrmhelper.GetRegistryPolContents(meta.(*config.ProviderConf), gpo, configuration)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Printf("[DEBUG] Registry.pol file not found, marking resource as gone")
+		d.SetId("")
+		return nil
+	}
+
+	entries, err := preg.Parse(contents)
+	if err != nil {
+		return fmt.Errorf("error while parsing registry policy of GPO with guid %q: %s", guid, err)
+	}
+
+	settings := []interface{}{}
+	deletes := []interface{}{}
+	for _, entry := range entries {
+		if entry.IsDirective() {
+			valueName, ok := entry.DeletedValue()
+			if !ok {
+				log.Printf("[WARN] ignoring unsupported directive %q of key %q", entry.ValueName, entry.Key)
+				continue
+			}
+			deletes = append(deletes, map[string]interface{}{
+				"key":        entry.Key,
+				"value_name": valueName,
+			})
+			continue
+		}
+
+		value, values, err := entry.Value()
+		if err != nil {
+			return fmt.Errorf("error while reading value %s\\%s: %s", entry.Key, entry.ValueName, err)
+		}
+		valuesList := []interface{}{}
+		for _, v := range values {
+			valuesList = append(valuesList, v)
+		}
+		settings = append(settings, map[string]interface{}{
+			"key":        entry.Key,
+			"value_name": entry.ValueName,
+			"type":       preg.TypeName(entry.Type),
+			"value":      value,
+			"values":     valuesList,
+		})
+	}
+
+	err = d.Set("setting", settings)
+	if err != nil {
+		return err
+	}
+	return d.Set("delete", deletes)
+}
+
+func resourceADGPORegistryPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	contents, err := getRegistryPolicyFromResource(d)
+	if err != nil {
+		return fmt.Errorf("error while generating registry policy from resource data: %s", err)
+	}
+
+	configuration := d.Get("configuration").(string)
+	hostContents, _, err := winrmhelper.GetRegistryPolContents(meta.(*config.ProviderConf), gpo, configuration)
+	if err != nil {
+		return fmt.Errorf("error while retrieving registry policy contents for GPO with guid %q: %s", guid, err)
+	}
+
+	if !bytes.Equal(contents, hostContents) {
+		err = winrmhelper.UploadRegistryPol(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration, contents)
+		if err != nil {
+			return fmt.Errorf("error while uploading registry policy file for GPO with guid %q: %s", guid, err)
+		}
+	}
+	return resourceADGPORegistryPolicyRead(d, meta)
+}
+
+func resourceADGPORegistryPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid, configuration, err := getRegistryPolicyGUID(d.Id())
+	if err != nil {
+		return err
+	}
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemoveRegistryPol(meta.(*config.ProviderConf), winrmCPClient, gpo, configuration)
+	if err != nil {
+		return fmt.Errorf("error while removing registry policy file for GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemoveExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetRegistryPolicyExtensionNames(configuration))
+	if err != nil {
+		return fmt.Errorf("error while unregistering registry policy extension for GPO with guid %q: %s", guid, err)
+	}
+	return nil
+}
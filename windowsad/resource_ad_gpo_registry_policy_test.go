@@ -0,0 +1,185 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADGPORegistryPolicy_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gporeg")
+	resourceName := "windowsad_gpo_registry_policy.reg"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPORegistryPolicyExists(resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPORegistryPolicyConfig(gpoName, domain, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPORegistryPolicyExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "delete.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADGPORegistryPolicyConfig(gpoName, domain, "0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPORegistryPolicyExists(resourceName, true),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "setting.*", map[string]string{
+						"value_name": "NoAutoUpdate",
+						"value":      "0",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceADGPORegistryPolicy_user(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gporeguser")
+	resourceName := "windowsad_gpo_registry_policy.user"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPORegistryPolicyExists(resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPORegistryPolicyUserConfig(gpoName, domain),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPORegistryPolicyExists(resourceName, true),
+					testAccResourceADGPORegistryPolicyExists("windowsad_gpo_registry_policy.computer", true),
+					resource.TestCheckResourceAttr(resourceName, "configuration", "user"),
+					resource.TestCheckResourceAttr(resourceName, "setting.#", "1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPORegistryPolicyExists(resourceName string, desired bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		guid, configuration, err := getRegistryPolicyGUID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		gpo, err := winrmhelper.GetGPOFromHost(testAccProvider.Meta().(*config.ProviderConf), "", guid)
+		if err != nil {
+			// if the GPO got destroyed first then the rest of the entities depending on it
+			// are also destroyed.
+			if !desired && strings.Contains(err.Error(), "NotFound") {
+				return nil
+			}
+			return err
+		}
+		_, found, err := winrmhelper.GetRegistryPolContents(testAccProvider.Meta().(*config.ProviderConf), gpo, configuration)
+		if err != nil {
+			return err
+		}
+		if found != desired {
+			return fmt.Errorf("Registry.pol file of GPO %q exists: %t, expected: %t", guid, found, desired)
+		}
+		return nil
+	}
+}
+
+func testAccResourceADGPORegistryPolicyConfig(gpoName, domain, noAutoUpdate string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_registry_policy" "reg" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+    value_name = "NoAutoUpdate"
+    type       = "REG_DWORD"
+    value      = %[3]q
+  }
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate"
+    value_name = "WUServer"
+    type       = "REG_SZ"
+    value      = "https://wsus.example.com:8531"
+  }
+
+  delete {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+    value_name = "AUOptions"
+  }
+}
+`, gpoName, domain, noAutoUpdate)
+}
+
+func testAccResourceADGPORegistryPolicyUserConfig(gpoName, domain string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_registry_policy" "computer" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\WindowsUpdate\\AU"
+    value_name = "NoAutoUpdate"
+    type       = "REG_DWORD"
+    value      = "0"
+  }
+}
+
+resource "windowsad_gpo_registry_policy" "user" {
+  gpo_container = windowsad_gpo.gpo.id
+  configuration = "user"
+
+  setting {
+    key        = "Software\\Policies\\Microsoft\\Windows\\Control Panel\\Desktop"
+    value_name = "ScreenSaveTimeOut"
+    type       = "REG_SZ"
+    value      = "600"
+  }
+}
+`, gpoName, domain)
+}
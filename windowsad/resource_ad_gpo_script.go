@@ -0,0 +1,279 @@
+package windowsad
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADGPOScript() *schema.Resource {
+	return &schema.Resource{
+		Description:   "`windowsad_gpo_script` manages a startup, shutdown, logon or logoff script of a Group Policy Object (GPO). The script file is stored in the GPO's `Scripts` folder and listed in its `scripts.ini` file, or `psscripts.ini` for PowerShell scripts.",
+		Create:        resourceADGPOScriptCreate,
+		Read:          resourceADGPOScriptRead,
+		Update:        resourceADGPOScriptUpdate,
+		Delete:        resourceADGPOScriptDelete,
+		CustomizeDiff: resourceADGPOScriptCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"gpo_container": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The GUID of the container the script belongs to.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(winrmhelper.GPOScriptTypes, false),
+				Description:  "The type of the script. `startup` and `shutdown` scripts run on the computers the GPO applies to, `logon` and `logoff` scripts for its users.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^\\/:*?"<>|]+$`), "must be a file name without a path"),
+				Description:  "The file name of the script, e.g. `setup.cmd`. Scripts with the `.ps1` extension are run as PowerShell scripts.",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The content of the script file.",
+			},
+			"parameters": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The parameters passed to the script.",
+			},
+			"order": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The position of the script in the list of scripts of its type, starting from 0. Scripts run in that order. If not set, the script is added at the end of the list.",
+			},
+			"content_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA256 checksum of the script file, used to detect changes made outside of Terraform.",
+			},
+		},
+	}
+}
+
+func getScriptSHA256(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// resourceADGPOScriptCustomizeDiff plans a new checksum when the checksum of the configured content
+// doesn't match the one of the file on the host, so that changes to the file are reverted.
+func resourceADGPOScriptCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("content") {
+		return d.SetNewComputed("content_sha256")
+	}
+	sum := getScriptSHA256([]byte(d.Get("content").(string)))
+	if sum != d.Get("content_sha256").(string) {
+		return d.SetNew("content_sha256", sum)
+	}
+	return nil
+}
+
+// getGPOScriptID returns the GUID of the GPO, the type and the name of the script of a resource ID
+func getGPOScriptID(resourceID string) (string, string, string, error) {
+	toks := strings.SplitN(resourceID, "_", 3)
+	if len(toks) != 3 || toks[0] == "" || toks[2] == "" {
+		return "", "", "", fmt.Errorf("resource ID %q does not match <guid>_<type>_<name>", resourceID)
+	}
+	for _, scriptType := range winrmhelper.GPOScriptTypes {
+		if toks[1] == scriptType {
+			return toks[0], toks[1], toks[2], nil
+		}
+	}
+	return "", "", "", fmt.Errorf("invalid script type %q in resource ID %q", toks[1], resourceID)
+}
+
+// getGPOScriptPosition returns the position of the script set in the configuration, or -1 to keep
+// the current position of the script
+func getGPOScriptPosition(d *schema.ResourceData) int {
+	if d.GetRawConfig().GetAttr("order").IsNull() {
+		return -1
+	}
+	return d.Get("order").(int)
+}
+
+func resourceADGPOScriptCreate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	if guid == "" {
+		return fmt.Errorf("Cannot handle empty GPO GUID")
+	}
+	parsedGUID, err := uuid.ParseUUID(guid)
+	if err != nil {
+		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
+	}
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
+
+	scriptType := d.Get("type").(string)
+	name := d.Get("name").(string)
+	configuration := winrmhelper.GetScriptConfiguration(scriptType)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return err
+	}
+
+	err = winrmhelper.UploadScript(meta.(*config.ProviderConf), winrmCPClient, gpo, scriptType, name, []byte(d.Get("content").(string)), d.Get("parameters").(string), getGPOScriptPosition(d))
+	if err != nil {
+		return fmt.Errorf("error while uploading script %q for GPO with guid %q: %s", name, guid, err)
+	}
+
+	err = winrmhelper.AddExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetScriptsExtensionNames(configuration))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s_%s_%s", guid, scriptType, name))
+
+	return resourceADGPOScriptRead(d, meta)
+}
+
+func resourceADGPOScriptRead(d *schema.ResourceData, meta interface{}) error {
+	guid, scriptType, name, err := getGPOScriptID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			log.Printf("[DEBUG] GPO with guid %q not found", guid)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	contents, found, err := winrmhelper.GetScriptContents(meta.(*config.ProviderConf), gpo, scriptType, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Printf("[DEBUG] script %q not found, marking resource as gone", name)
+		d.SetId("")
+		return nil
+	}
+
+	script, position, err := winrmhelper.GetScript(meta.(*config.ProviderConf), gpo, scriptType, name)
+	if err != nil {
+		return fmt.Errorf("error while reading scripts of GPO with guid %q: %s", guid, err)
+	}
+	if position < 0 {
+		log.Printf("[DEBUG] script %q is not listed in the scripts of the GPO, marking resource as gone", name)
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("gpo_container", guid)
+	_ = d.Set("type", scriptType)
+	_ = d.Set("name", name)
+	_ = d.Set("parameters", script.Parameters)
+	_ = d.Set("order", position)
+	_ = d.Set("content_sha256", getScriptSHA256(contents))
+	// The content is only read when importing, later changes to the file are detected through the
+	// checksum.
+	if d.Get("content").(string) == "" {
+		_ = d.Set("content", string(contents))
+	}
+	return nil
+}
+
+func resourceADGPOScriptUpdate(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid := d.Get("gpo_container").(string)
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	var contents []byte
+	if d.HasChanges("content", "content_sha256") {
+		contents = []byte(d.Get("content").(string))
+	}
+
+	name := d.Get("name").(string)
+	err = winrmhelper.UploadScript(meta.(*config.ProviderConf), winrmCPClient, gpo, d.Get("type").(string), name, contents, d.Get("parameters").(string), getGPOScriptPosition(d))
+	if err != nil {
+		return fmt.Errorf("error while uploading script %q for GPO with guid %q: %s", name, guid, err)
+	}
+	return resourceADGPOScriptRead(d, meta)
+}
+
+func resourceADGPOScriptDelete(d *schema.ResourceData, meta interface{}) error {
+	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
+	if err != nil {
+		return err
+	}
+	defer meta.(*config.ProviderConf).ReleaseWinRMCPClient(winrmCPClient)
+
+	guid, scriptType, name, err := getGPOScriptID(d.Id())
+	if err != nil {
+		return err
+	}
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
+
+	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
+	if err != nil {
+		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.RemoveScript(meta.(*config.ProviderConf), winrmCPClient, gpo, scriptType, name)
+	if err != nil {
+		return fmt.Errorf("error while removing script %q for GPO with guid %q: %s", name, guid, err)
+	}
+
+	// The extension stays registered while other scripts of the configuration remain.
+	configuration := winrmhelper.GetScriptConfiguration(scriptType)
+	hasScripts, err := winrmhelper.HasScripts(meta.(*config.ProviderConf), gpo, configuration)
+	if err != nil {
+		return err
+	}
+	if !hasScripts {
+		err = winrmhelper.RemoveExtensionNames(meta.(*config.ProviderConf), gpo.DN, configuration, winrmhelper.GetScriptsExtensionNames(configuration))
+		if err != nil {
+			return fmt.Errorf("error while unregistering scripts extension for GPO with guid %q: %s", guid, err)
+		}
+	}
+	return nil
+}
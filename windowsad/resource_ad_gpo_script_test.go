@@ -0,0 +1,113 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADGPOScript_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gposcript")
+	resourceName := "windowsad_gpo_script.startup"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADGPOScriptExists(resourceName, false),
+			testAccResourceADGPOScriptExists("windowsad_gpo_script.logon", false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOScriptConfig(gpoName, domain, "-Verbose"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOScriptExists(resourceName, true),
+					testAccResourceADGPOScriptExists("windowsad_gpo_script.logon", true),
+					resource.TestCheckResourceAttr(resourceName, "order", "0"),
+					resource.TestCheckResourceAttrSet(resourceName, "content_sha256"),
+				),
+			},
+			{
+				Config: testAccResourceADGPOScriptConfig(gpoName, domain, "-Verbose -WhatIf"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOScriptExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "parameters", "-Verbose -WhatIf"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOScriptExists(resourceName string, desired bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		guid, scriptType, name, err := getGPOScriptID(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		gpo, err := winrmhelper.GetGPOFromHost(testAccProvider.Meta().(*config.ProviderConf), "", guid)
+		if err != nil {
+			// if the GPO got destroyed first then the rest of the entities depending on it
+			// are also destroyed.
+			if !desired && strings.Contains(err.Error(), "NotFound") {
+				return nil
+			}
+			return err
+		}
+		_, position, err := winrmhelper.GetScript(testAccProvider.Meta().(*config.ProviderConf), gpo, scriptType, name)
+		if err != nil {
+			return err
+		}
+		if (position >= 0) != desired {
+			return fmt.Errorf("script %q of GPO %q is listed: %t, expected: %t", name, guid, position >= 0, desired)
+		}
+		return nil
+	}
+}
+
+func testAccResourceADGPOScriptConfig(gpoName, domain, parameters string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_script" "startup" {
+  gpo_container = windowsad_gpo.gpo.id
+  type          = "startup"
+  name          = "setup.ps1"
+  content       = "Write-Verbose \"Configuring the computer\"\r\n"
+  parameters    = %[3]q
+}
+
+resource "windowsad_gpo_script" "logon" {
+  gpo_container = windowsad_gpo.gpo.id
+  type          = "logon"
+  name          = "drives.cmd"
+  content       = "net use S: \\\\fileserver\\share\r\n"
+}
+`, gpoName, domain, parameters)
+}
@@ -1,10 +1,10 @@
 package windowsad
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
@@ -18,7 +18,7 @@ import (
 
 func resourceADGPOSecurity() *schema.Resource {
 	return &schema.Resource{
-		Description: "`ad_gpo_security` manages the security settings portion of a Group Policy Object (GPO).",
+		Description: "`ad_gpo_security` manages the security settings portion of a Group Policy Object (GPO). Only the sections and keys of the configured blocks are managed, the other settings of the GPO's `GptTmpl.inf` file are preserved.",
 		Create:      resourceADGPOSecurityCreate,
 		Read:        resourceADGPOSecurityRead,
 		Update:      resourceADGPOSecurityUpdate,
@@ -41,11 +41,15 @@ func resourceADGPOSecurityCreate(d *schema.ResourceData, meta interface{}) error
 	if guid == "" {
 		return fmt.Errorf("Cannot handle empty GPO GUID")
 	}
-	_, err = uuid.ParseUUID(guid)
+	parsedGUID, err := uuid.ParseUUID(guid)
 	if err != nil {
 		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
 	}
-	iniFile, err := winrmhelper.GetSecIniFromResource(d, adschema.GpoSecuritySchema())
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
+	iniFile, err := winrmhelper.GetSecIniFromResource(meta.(*config.ProviderConf), d, adschema.GpoSecuritySchema())
 	if err != nil {
 		return fmt.Errorf("error while generating ini file from resource data: %s", err)
 	}
@@ -55,14 +59,22 @@ func resourceADGPOSecurityCreate(d *schema.ResourceData, meta interface{}) error
 		return err
 	}
 
-	err = winrmhelper.UploadSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo, iniFile)
+	hostSecIniBytes, _, err := winrmhelper.GetSecIniRawContents(meta.(*config.ProviderConf), gpo)
+	if err != nil {
+		return fmt.Errorf("error while retrieving security settings contents for GPO with guid %q: %s", guid, err)
+	}
+
+	contents, err := gposec.MergeIniContents(hostSecIniBytes, iniFile, getManagedSecurityBlocks(d))
+	if err != nil {
+		return fmt.Errorf("error while merging security settings for GPO with guid %q: %s", guid, err)
+	}
+
+	err = winrmhelper.UploadSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo, contents)
 	if err != nil {
 		return err
 	}
 
-	// GUIDs for security settings are defined here:
-	// https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-gpsb/55bb803e-b35f-4ce8-b558-4c1e92ad77a4
-	err = winrmhelper.SetMachineExtensionNames(meta.(*config.ProviderConf), gpo.DN, "[{827D319E-6EAC-11D2-A4EA-00C04F79F83A}{803E14A0-B4FB-11D0-A0D0-00A0C90F574B}]")
+	err = winrmhelper.AddMachineExtensionNames(meta.(*config.ProviderConf), gpo.DN, winrmhelper.SecuritySettingsExtensionNames)
 	if err != nil {
 		return err
 	}
@@ -101,10 +113,59 @@ func resourceADGPOSecurityRead(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	err = gposec.HandleSectionRead(adschema.GPOSecuritySchemaKeys, hostSecIni, d)
+	if hostSecIni.PrivilegeRights != nil {
+		err = winrmhelper.ResolvePrivilegeRightsNames(meta.(*config.ProviderConf), hostSecIni.PrivilegeRights, getConfiguredPrivilegeRightsPrincipals(d))
+		if err != nil {
+			return err
+		}
+	}
+
+	// only the managed blocks are read back, the other sections of the file belong to other tools.
+	// An imported resource has no managed blocks yet and adopts all of them.
+	schemaKeys := getManagedSecurityBlocks(d)
+	if len(schemaKeys) == 0 {
+		schemaKeys = adschema.GPOSecuritySchemaKeys
+	}
+	err = gposec.HandleSectionRead(schemaKeys, hostSecIni, d)
 	return err
 }
 
+// getManagedSecurityBlocks returns the blocks of the resource that are configured, or were before
+// this change. The INF sections and keys of the other blocks are left untouched on the host.
+func getManagedSecurityBlocks(d *schema.ResourceData) []string {
+	blocks := []string{}
+	for _, key := range adschema.GPOSecuritySchemaKeys {
+		oldValue, newValue := d.GetChange(key)
+		if isSecurityBlockSet(oldValue) || isSecurityBlockSet(newValue) {
+			blocks = append(blocks, key)
+		}
+	}
+	sort.Strings(blocks)
+	return blocks
+}
+
+func isSecurityBlockSet(value interface{}) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		return len(v) > 0
+	case *schema.Set:
+		return v.Len() > 0
+	}
+	return false
+}
+
+// getConfiguredPrivilegeRightsPrincipals returns the principals of all the user rights assignments of
+// the resource, used to keep their spelling when they are read back as SIDs
+func getConfiguredPrivilegeRightsPrincipals(d *schema.ResourceData) []string {
+	principals := []string{}
+	for _, item := range d.Get("privilege_rights").(*schema.Set).List() {
+		for _, p := range item.(map[string]interface{})["principals"].(*schema.Set).List() {
+			principals = append(principals, p.(string))
+		}
+	}
+	return principals
+}
+
 func resourceADGPOSecurityUpdate(d *schema.ResourceData, meta interface{}) error {
 	winrmCPClient, err := meta.(*config.ProviderConf).AcquireWinRMCPClient()
 	if err != nil {
@@ -116,37 +177,40 @@ func resourceADGPOSecurityUpdate(d *schema.ResourceData, meta interface{}) error
 	if guid == "" {
 		return fmt.Errorf("Cannot handle empty GPO GUID")
 	}
-	_, err = uuid.ParseUUID(guid)
+	parsedGUID, err := uuid.ParseUUID(guid)
 	if err != nil {
 		return fmt.Errorf("Cannot parse GUID %q: %s", guid, err)
 	}
+	// the parsed GUID has a valid length, so formatting it can't fail
+	lockGUID, _ := uuid.FormatUUID(parsedGUID)
+	meta.(*config.ProviderConf).LockGPO(lockGUID)
+	defer meta.(*config.ProviderConf).UnlockGPO(lockGUID)
 
 	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
 	if err != nil {
 		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
 	}
 
-	iniFile, err := winrmhelper.GetSecIniFromResource(d, adschema.GpoSecuritySchema())
+	iniFile, err := winrmhelper.GetSecIniFromResource(meta.(*config.ProviderConf), d, adschema.GpoSecuritySchema())
 	if err != nil {
 		return fmt.Errorf("error while generating ini file from resource data: %s", err)
 	}
 
-	iniBuf := bytes.NewBuffer([]byte{})
-	_, err = iniFile.WriteTo(iniBuf)
+	hostSecIniBytes, _, err := winrmhelper.GetSecIniRawContents(meta.(*config.ProviderConf), gpo)
 	if err != nil {
-		return fmt.Errorf("error while writing INI file in buffer")
+		return fmt.Errorf("error while retrieving security settings contents for GPO with guid %q: %s", guid, err)
 	}
-	iniSum := sha256.Sum256(iniBuf.Bytes())
 
-	hostSecIniBytes, err := winrmhelper.GetSecIniContents(meta.(*config.ProviderConf), gpo)
+	contents, err := gposec.MergeIniContents(hostSecIniBytes, iniFile, getManagedSecurityBlocks(d))
 	if err != nil {
-		return fmt.Errorf("error while retrieving security settings contents for GPO with guid %q: %s", guid, err)
+		return fmt.Errorf("error while merging security settings for GPO with guid %q: %s", guid, err)
 	}
 
+	iniSum := sha256.Sum256(contents)
 	hostSum := sha256.Sum256(hostSecIniBytes)
 
 	if iniSum != hostSum {
-		err = winrmhelper.UploadSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo, iniFile)
+		err = winrmhelper.UploadSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo, contents)
 		if err != nil {
 			return fmt.Errorf("error while uploading security settings file for GPO with guid %q: %s", guid, err)
 		}
@@ -167,15 +231,49 @@ func resourceADGPOSecurityDelete(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("resource ID %q does not match <guid>_securitysettings", resourceID)
 	}
 	guid := toks[0]
+	meta.(*config.ProviderConf).LockGPO(guid)
+	defer meta.(*config.ProviderConf).UnlockGPO(guid)
 
 	gpo, err := winrmhelper.GetGPOFromHost(meta.(*config.ProviderConf), "", guid)
 	if err != nil {
 		return fmt.Errorf("error while retrieving GPO with guid %q: %s", guid, err)
 	}
 
+	hostSecIniBytes, found, err := winrmhelper.GetSecIniRawContents(meta.(*config.ProviderConf), gpo)
+	if err != nil {
+		return fmt.Errorf("error while retrieving security settings contents for GPO with guid %q: %s", guid, err)
+	}
+	if found {
+		emptyIni, err := winrmhelper.NewSecIni()
+		if err != nil {
+			return err
+		}
+		contents, err := gposec.MergeIniContents(hostSecIniBytes, emptyIni, getManagedSecurityBlocks(d))
+		if err != nil {
+			return fmt.Errorf("error while merging security settings for GPO with guid %q: %s", guid, err)
+		}
+		hasSettings, err := gposec.HasSettings(contents)
+		if err != nil {
+			return err
+		}
+		// settings that aren't managed by the resource are kept, with their extension registered
+		if hasSettings {
+			err = winrmhelper.UploadSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo, contents)
+			if err != nil {
+				return fmt.Errorf("error while uploading security settings file for GPO with guid %q: %s", guid, err)
+			}
+			return nil
+		}
+	}
+
 	err = winrmhelper.RemoveSecIni(meta.(*config.ProviderConf), winrmCPClient, gpo)
 	if err != nil {
 		return fmt.Errorf("error while removing security settings INF file for GPO with guid %q: %s", guid, err)
 	}
+
+	err = winrmhelper.RemoveMachineExtensionNames(meta.(*config.ProviderConf), gpo.DN, winrmhelper.SecuritySettingsExtensionNames)
+	if err != nil {
+		return fmt.Errorf("error while unregistering security settings extension for GPO with guid %q: %s", guid, err)
+	}
 	return nil
 }
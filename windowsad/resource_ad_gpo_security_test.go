@@ -148,3 +148,132 @@ resource "windowsad_gpo_security" "gpo_sec" {
 }
 `, gpoName, domain)
 }
+
+func TestAccResourceADGPOSecurity_privilegeRights(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gposec")
+	resourceName := "windowsad_gpo_security.gpo_sec"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOSecurityExists(resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOSecurityConfigPrivilegeRights(gpoName, domain, `"BUILTIN\\Guests"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOSecurityExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "privilege_rights.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege_rights.*", map[string]string{
+						"privilege":    "SeDenyNetworkLogonRight",
+						"principals.#": "1",
+					}),
+				),
+			},
+			{
+				Config: testAccResourceADGPOSecurityConfigPrivilegeRights(gpoName, domain, `"BUILTIN\\Guests", "S-1-5-7"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOSecurityExists(resourceName, true),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege_rights.*", map[string]string{
+						"privilege":    "SeDenyNetworkLogonRight",
+						"principals.#": "2",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOSecurityConfigPrivilegeRights(gpoName, domain, denyNetworkLogon string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_security" "gpo_sec" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  privilege_rights {
+    privilege  = "SeDenyNetworkLogonRight"
+    principals = [%[3]s]
+  }
+
+  privilege_rights {
+    privilege  = "SeTcbPrivilege"
+    principals = []
+  }
+}
+`, gpoName, domain, denyNetworkLogon)
+}
+
+func TestAccResourceADGPOSecurity_securityOptions(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	gpoName := testAccRandomName("tfacc-gposec")
+	resourceName := "windowsad_gpo_security.gpo_sec"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             resource.ComposeTestCheckFunc(testAccResourceADGPOSecurityExists(resourceName, false)),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGPOSecurityConfigSecurityOptions(gpoName, domain, "3"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOSecurityExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "security_options.0.lm_compatibility_level", "3"),
+					resource.TestCheckResourceAttr(resourceName, "security_options.0.new_administrator_name", "localadm"),
+					resource.TestCheckResourceAttr(resourceName, "registry_values.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADGPOSecurityConfigSecurityOptions(gpoName, domain, "5"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGPOSecurityExists(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "security_options.0.lm_compatibility_level", "5"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADGPOSecurityConfigSecurityOptions(gpoName, domain, lmCompatibilityLevel string) string {
+	return fmt.Sprintf(`
+resource "windowsad_gpo" "gpo" {
+  name   = %[1]q
+  domain = %[2]q
+}
+
+resource "windowsad_gpo_security" "gpo_sec" {
+  gpo_container = windowsad_gpo.gpo.id
+
+  security_options {
+    enable_guest_account   = "0"
+    new_administrator_name = "localadm"
+    lm_compatibility_level = %[3]q
+    no_lm_hash             = "1"
+  }
+
+  registry_values {
+    key_name   = "MACHINE\\Software\\Example\\Value"
+    value_type = "4"
+    value      = "1"
+  }
+}
+`, gpoName, domain, lmCompatibilityLevel)
+}
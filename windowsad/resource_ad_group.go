@@ -8,6 +8,7 @@ import (
 
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
@@ -37,7 +38,7 @@ func resourceADGroup() *schema.Resource {
 				Optional:     true,
 				Default:      "global",
 				ValidateFunc: validation.StringInSlice([]string{"global", "domainlocal", "universal"}, false),
-				Description:  "The group's scope. Can be one of `global`, `domainlocal`, or `universal` (case sensitive).",
+				Description:  "The group's scope. Can be one of `global`, `domainlocal`, or `universal` (case sensitive). Changing between `global` and `domainlocal` converts the group to `universal` first, as AD does not allow a direct conversion.",
 			},
 			"category": {
 				Type:         schema.TypeString,
@@ -57,6 +58,41 @@ func resourceADGroup() *schema.Resource {
 				Optional:    true,
 				Description: "Description of the Group.",
 			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The display name of the Group.",
+			},
+			"managed_by": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "The DN of the user or group that manages the Group.",
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"info": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Notes about the Group. This parameter sets the info attribute of the group object.",
+			},
+			"mail": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The e-mail address of the Group. This parameter sets the mail attribute of the group object.",
+			},
+			"protected_from_accidental_deletion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If set to true, the Group will be protected from accidental deletion. The protection is lifted when the group is destroyed by terraform.",
+			},
+			"restore_from_recycle_bin": restoreFromRecycleBinSchema("group"),
+			"custom_attributes": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "JSON encoded map that represents key/value pairs for custom attributes. Please note that `terraform import` will not import these attributes.",
+				ValidateFunc:     validation.StringIsJSON,
+				DiffSuppressFunc: suppressJsonDiff,
+			},
 			"sid": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -72,17 +108,35 @@ func resourceADGroup() *schema.Resource {
 }
 
 func resourceADGroupCreate(d *schema.ResourceData, meta interface{}) error {
-	u := winrmhelper.GetGroupFromResource(d)
+	u, err := winrmhelper.GetGroupFromResource(d)
+	if err != nil {
+		return fmt.Errorf("while building a Group struct from resource data: %s", err)
+	}
+	restored, err := restoreFromRecycleBin(d, meta, "group", d.Get("sam_account_name").(string), u.Container, u.Name)
+	if err != nil {
+		return err
+	}
+	if restored {
+		return resourceADGroupUpdate(d, meta)
+	}
+
 	guid, err := u.AddGroup(meta.(*config.ProviderConf))
+	if guid != "" {
+		d.SetId(guid)
+	}
 	if err != nil {
 		return err
 	}
-	d.SetId(guid)
 	return resourceADGroupRead(d, meta)
 }
 
 func resourceADGroupRead(d *schema.ResourceData, meta interface{}) error {
-	g, err := winrmhelper.GetGroupFromHost(meta.(*config.ProviderConf), d.Id())
+	caKeys, err := extractCustAttrKeys(d)
+	if err != nil {
+		return err
+	}
+
+	g, err := winrmhelper.GetGroupFromHost(meta.(*config.ProviderConf), d.Id(), caKeys)
 	if err != nil {
 		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
 			d.SetId("")
@@ -100,15 +154,31 @@ func resourceADGroupRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set("category", g.Category)
 	_ = d.Set("container", g.Container)
 	_ = d.Set("description", g.Description)
+	_ = d.Set("display_name", g.DisplayName)
+	_ = d.Set("managed_by", g.ManagedBy)
+	_ = d.Set("info", g.Info)
+	_ = d.Set("mail", g.Mail)
+	_ = d.Set("protected_from_accidental_deletion", g.Protected)
 	_ = d.Set("dn", g.DistinguishedName)
 	_ = d.Set("sid", g.SID.Value)
 
+	if g.CustomAttributes != nil {
+		ca, err := structure.FlattenJsonToString(g.CustomAttributes)
+		if err != nil {
+			return err
+		}
+		_ = d.Set("custom_attributes", ca)
+	}
+
 	return nil
 }
 
 func resourceADGroupUpdate(d *schema.ResourceData, meta interface{}) error {
-	g := winrmhelper.GetGroupFromResource(d)
-	err := g.ModifyGroup(d, meta.(*config.ProviderConf))
+	g, err := winrmhelper.GetGroupFromResource(d)
+	if err != nil {
+		return err
+	}
+	err = g.ModifyGroup(d, meta.(*config.ProviderConf))
 	if err != nil {
 		return err
 	}
@@ -116,7 +186,7 @@ func resourceADGroupUpdate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceADGroupDelete(d *schema.ResourceData, meta interface{}) error {
-	g, err := winrmhelper.GetGroupFromHost(meta.(*config.ProviderConf), d.Id())
+	g, err := winrmhelper.GetGroupFromHost(meta.(*config.ProviderConf), d.Id(), nil)
 	if err != nil {
 		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
 			return nil
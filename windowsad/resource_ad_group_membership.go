@@ -9,6 +9,7 @@ import (
 	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceADGroupMembership() *schema.Resource {
@@ -35,6 +36,19 @@ func resourceADGroupMembership() *schema.Resource {
 				Elem:        &schema.Schema{Type: schema.TypeString},
 				MinItems:    0,
 			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Time to live, in seconds, of the memberships managed by this resource. Members are removed from the group by AD when the TTL expires and are added back on the next apply. Requires the Privileged Access Management optional feature to be enabled in the forest.",
+			},
+			"ttl_remaining": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "A map of member GUIDs to the remaining time to live, in seconds, of their membership. Only populated when `ttl` is set.",
+			},
 		},
 	}
 }
@@ -53,6 +67,18 @@ func resourceADGroupMembershipRead(d *schema.ResourceData, meta interface{}) err
 	}
 	_ = d.Set("group_members", memberList)
 	_ = d.Set("group_id", toks[0])
+
+	if d.Get("ttl").(int) > 0 {
+		err = gm.PopulateMemberTTLs(meta.(*config.ProviderConf))
+		if err != nil {
+			return err
+		}
+		ttlRemaining := make(map[string]interface{}, len(gm.GroupMembers))
+		for _, m := range gm.GroupMembers {
+			ttlRemaining[m.GUID] = m.TTL
+		}
+		_ = d.Set("ttl_remaining", ttlRemaining)
+	}
 	return nil
 }
 
@@ -64,6 +90,9 @@ func resourceADGroupMembershipCreate(d *schema.ResourceData, meta interface{}) e
 
 	err = gm.Create(meta.(*config.ProviderConf))
 	if err != nil {
+		if gm.TTL > 0 {
+			return fmt.Errorf("while adding time-bound members, make sure the Privileged Access Management feature is enabled: %s", err)
+		}
 		return err
 	}
 
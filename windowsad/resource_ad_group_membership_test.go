@@ -106,6 +106,50 @@ func TestAccResourceADGroupMembership_Update(t *testing.T) {
 		},
 	})
 }
+
+func TestAccResourceADGroupMembership_TTL(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_group_container",
+		"TF_VAR_ad_group_membership_ttl_enabled",
+	}
+
+	groupContainer := os.Getenv("TF_VAR_ad_group_container")
+
+	groupName := testAccRandomName("tfacc-grp")
+	groupSam := testAccRandomSAM()
+	group2Name := testAccRandomName("tfacc-grp2")
+	group2Sam := testAccRandomSAM()
+	resourceName := "windowsad_group_membership.gm"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADGroupMembershipExists(resourceName, false, 0),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGroupMembershipConfigTTL(
+					groupName, groupSam, groupContainer,
+					group2Name, group2Sam, groupContainer,
+					3600,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGroupMembershipExists(resourceName, true, 1),
+					resource.TestCheckResourceAttr(resourceName, "ttl", "3600"),
+				),
+			},
+			{
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ttl_remaining.%", "1"),
+				),
+			},
+		},
+	})
+}
+
 func testAccResourceADGroupMembershipExists(resourceName string, expected bool, desiredMemberCount int) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -202,3 +246,29 @@ resource "windowsad_group_membership" "gm" {
 }
 `, groupName, groupSam, groupContainer, group2Name, group2Sam, group2Container, group3Name, group3Sam, group3Container, userName, userSam, userPassword, userPrincipal, userContainer)
 }
+
+func testAccResourceADGroupMembershipConfigTTL(
+	groupName, groupSam, groupContainer,
+	group2Name, group2Sam, group2Container string,
+	ttl int,
+) string {
+	return fmt.Sprintf(`
+resource "windowsad_group" "g" {
+  name             = %[1]q
+  sam_account_name = %[2]q
+  container        = %[3]q
+}
+
+resource "windowsad_group" "g2" {
+  name             = %[4]q
+  sam_account_name = %[5]q
+  container        = %[6]q
+}
+
+resource "windowsad_group_membership" "gm" {
+  group_id      = windowsad_group.g.id
+  group_members = [windowsad_group.g2.id]
+  ttl           = %[7]d
+}
+`, groupName, groupSam, groupContainer, group2Name, group2Sam, group2Container, ttl)
+}
@@ -116,10 +116,75 @@ func TestAccResourceADGroup_scopes(t *testing.T) {
 					testAccResourceADGroupExists(resourceName, sam, true),
 				),
 			},
+			{
+				// global -> domainlocal is not a legal conversion in AD and has to go through universal
+				Config: testAccResourceADGroupConfigRandom(groupName, sam, container, "domainlocal", "security", "Test group"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGroupExists(resourceName, sam, true),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceADGroup_attributes(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_group_container",
+	}
+
+	container := os.Getenv("TF_VAR_ad_group_container")
+	groupName := testAccRandomName("tfacc-group")
+	sam := testAccRandomSAM()
+	resourceName := "windowsad_group.g"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADGroupExists(resourceName, sam, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADGroupConfigAttributes(groupName, sam, container, "Some notes", "group@example.com", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGroupExists(resourceName, sam, true),
+					resource.TestCheckResourceAttr(resourceName, "info", "Some notes"),
+					resource.TestCheckResourceAttr(resourceName, "mail", "group@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "display_name", groupName),
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "true"),
+				),
+			},
+			{
+				Config: testAccResourceADGroupConfigAttributes(groupName, sam, container, "", "", false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADGroupExists(resourceName, sam, true),
+					resource.TestCheckResourceAttr(resourceName, "info", ""),
+					resource.TestCheckResourceAttr(resourceName, "mail", ""),
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "false"),
+				),
+			},
 		},
 	})
 }
 
+func testAccResourceADGroupConfigAttributes(name, sam, container, info, mail string, protected bool) string {
+	return fmt.Sprintf(`
+resource "windowsad_group" "g" {
+  name                               = %[1]q
+  sam_account_name                   = %[2]q
+  container                          = %[3]q
+  display_name                       = %[1]q
+  info                               = %[4]q
+  mail                               = %[5]q
+  protected_from_accidental_deletion = %[6]t
+  custom_attributes = jsonencode({
+    "extensionAttribute1" : "tfacc"
+  })
+}
+`, name, sam, container, info, mail, protected)
+}
+
 func testAccResourceADGroupConfigRandom(name, sam, container, scope, category, description string) string {
 	return fmt.Sprintf(`
 resource "windowsad_group" "g" {
@@ -146,7 +211,7 @@ func testAccResourceADGroupExists(name, groupSAM string, expected bool) resource
 		}
 		defer conf.ReleaseWinRMClient(client)
 
-		u, err := winrmhelper.GetGroupFromHost(conf, rs.Primary.ID)
+		u, err := winrmhelper.GetGroupFromHost(conf, rs.Primary.ID, nil)
 		if err != nil {
 			if strings.Contains(err.Error(), "ADIdentityNotFoundException") && !expected {
 				return nil
@@ -0,0 +1,127 @@
+package windowsad
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADKdsRootKey() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_kds_root_key` makes sure a Key Distribution Services root key exists in the forest. The key is required by group managed service accounts.",
+		Create:      resourceADKdsRootKeyCreate,
+		Read:        resourceADKdsRootKeyRead,
+		Delete:      resourceADKdsRootKeyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceADKdsRootKeyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"effective_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: suppressImportedEffectiveTimeDiff,
+				Description:      "The date, in RFC3339 format, from which the key can be used. Set it at least 10 hours in the past to use the key right away in lab environments. Defaults to the creation time, in which case domain controllers wait 10 hours before using the key. Imported keys are only replaced if it differs from `key_effective_time`.",
+			},
+			"create_new": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "If set to true, a new key is always added. By default an existing key is reused if the forest already has one.",
+			},
+			"key_effective_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The effective time of the key, as reported by `Get-KdsRootKey`.",
+			},
+			"creation_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation time of the key, as reported by `Get-KdsRootKey`.",
+			},
+		},
+	}
+}
+
+// suppressImportedEffectiveTimeDiff suppresses the diff of effective_time on imported keys. The
+// effective time a key was requested with can't be read back, so it's empty after an import, and
+// the key is only replaced if the configured time differs from the one of the key.
+func suppressImportedEffectiveTimeDiff(k, old, new string, d *schema.ResourceData) bool {
+	if old != "" || d.Id() == "" {
+		return false
+	}
+	keyTime, err := time.Parse(time.RFC3339, d.Get("key_effective_time").(string))
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return keyTime.Equal(newTime)
+}
+
+func resourceADKdsRootKeyImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// an imported key is an existing key, which is what create_new = false describes
+	_ = d.Set("create_new", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceADKdsRootKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+
+	if !d.Get("create_new").(bool) {
+		keys, err := winrmhelper.GetKdsRootKeys(conf)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			log.Printf("[DEBUG] Reusing existing KDS root key %q", keys[0].KeyID)
+			d.SetId(keys[0].KeyID)
+			return resourceADKdsRootKeyRead(d, meta)
+		}
+	}
+
+	keyID, err := winrmhelper.NewKdsRootKey(conf, d.Get("effective_time").(string))
+	if err != nil {
+		return err
+	}
+	d.SetId(keyID)
+
+	return resourceADKdsRootKeyRead(d, meta)
+}
+
+func resourceADKdsRootKeyRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	key, err := winrmhelper.GetKdsRootKeyFromHost(meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		return fmt.Errorf("while reading KDS root key %q: %s", d.Id(), err)
+	}
+	if key == nil {
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("key_effective_time", key.EffectiveTime)
+	_ = d.Set("creation_time", key.CreationTime)
+
+	return nil
+}
+
+func resourceADKdsRootKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	// KDS root keys can't be removed with the KDS cmdlets, and removing one would break the
+	// managed service accounts that depend on it, so the key is only dropped from the state.
+	log.Printf("[WARN] KDS root key %q is kept in the forest, it is only removed from the terraform state", d.Id())
+	return nil
+}
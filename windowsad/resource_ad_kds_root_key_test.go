@@ -0,0 +1,42 @@
+package windowsad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADKdsRootKey_basic(t *testing.T) {
+
+	resourceName := "windowsad_kds_root_key.k"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, []string{}) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADKdsRootKeyConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "key_effective_time"),
+					resource.TestCheckResourceAttrSet(resourceName, "creation_time"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the effective time the key was requested with can't be read back, the diff
+				// against key_effective_time is suppressed instead
+				ImportStateVerifyIgnore: []string{"effective_time"},
+			},
+		},
+	})
+}
+
+func testAccResourceADKdsRootKeyConfig() string {
+	return `
+resource "windowsad_kds_root_key" "k" {
+  effective_time = "2020-01-01T00:00:00Z"
+}
+`
+}
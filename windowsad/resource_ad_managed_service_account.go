@@ -0,0 +1,247 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADManagedServiceAccount() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_managed_service_account` manages group managed service accounts (gMSA) and standalone managed service accounts (MSA).",
+		Create:      resourceADManagedServiceAccountCreate,
+		Read:        resourceADManagedServiceAccountRead,
+		Update:      resourceADManagedServiceAccountUpdate,
+		Delete:      resourceADManagedServiceAccountDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 15),
+				Description:  "The name of the service account. The SAM account name is derived from it and is limited to 15 characters.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "group",
+				ValidateFunc: validation.StringInSlice([]string{"group", "standalone"}, false),
+				Description:  "The type of managed service account, `group` (gMSA) or `standalone` (MSA).",
+			},
+			"container": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The DN of the container the account is created in. Defaults to the Managed Service Accounts container.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Specifies a description of the object. This parameter sets the value of the Description property for the account object.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The display name of the account.",
+			},
+			"dns_host_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The DNS host name of the service. Required for group managed service accounts.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If set to false, the account will be disabled.",
+			},
+			"managed_password_interval_in_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of days before the managed password is changed. It can only be set when the account is created. Only applies to group managed service accounts.",
+			},
+			"principals_allowed_to_retrieve_managed_password": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The distinguished names of the principals, usually computer accounts or groups of computers, allowed to retrieve the managed password. Only applies to group managed service accounts.",
+			},
+			"service_principal_names": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The service principal names (SPNs) of the account. SPNs that are already registered on another object in the forest are rejected. If omitted, the SPNs of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"kerberos_encryption_type": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(winrmhelper.KerberosEncryptionTypeNames(), false),
+				},
+				Description: "The Kerberos encryption types supported by the account. Valid values are `DES`, `RC4`, `AES128` and `AES256`.",
+			},
+			"allowed_to_delegate_to": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The SPNs of the services this account can present delegated credentials to (constrained delegation). This parameter sets the msDS-AllowedToDelegateTo attribute. If omitted, the constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"trusted_to_auth_for_delegation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "If set to true, the account can use protocol transition for constrained delegation, i.e. delegate without the client authenticating with Kerberos. This parameter sets the TrustedToAuthForDelegation property of an account object. If omitted, the setting is not managed by terraform.",
+			},
+			"principals_allowed_to_delegate_to_account": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The distinguished names of the principals that are allowed to delegate to this account (resource-based constrained delegation). This parameter sets the PrincipalsAllowedToDelegateToAccount property, which is stored in the msDS-AllowedToActOnBehalfOfOtherIdentity security descriptor. If omitted, the resource-based constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"sam_account_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SAM account name of the account, including the trailing `$`.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the account.",
+			},
+			"sid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SID of the account.",
+			},
+		},
+	}
+}
+
+func resourceADManagedServiceAccountCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	msa := winrmhelper.GetManagedServiceAccountFromResource(d)
+
+	if len(msa.ServicePrincipalNames) > 0 {
+		err := winrmhelper.CheckServicePrincipalNameDuplicates(conf, "", msa.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+
+	guid, err := msa.NewManagedServiceAccount(conf)
+	if err != nil {
+		return err
+	}
+	d.SetId(guid)
+
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(conf, "Set-ADServiceAccount", guid, delegation.ConfiguredKeys())
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for managed service account %q: %s", guid, err)
+	}
+
+	return resourceADManagedServiceAccountRead(d, meta)
+}
+
+func resourceADManagedServiceAccountRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	msa, err := winrmhelper.GetManagedServiceAccountFromHost(meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	accountType := "group"
+	if msa.Standalone {
+		accountType = "standalone"
+	}
+
+	_ = d.Set("name", msa.Name)
+	_ = d.Set("type", accountType)
+	_ = d.Set("container", msa.Container)
+	_ = d.Set("description", msa.Description)
+	_ = d.Set("display_name", msa.DisplayName)
+	_ = d.Set("dns_host_name", msa.DNSHostName)
+	_ = d.Set("enabled", msa.Enabled)
+	_ = d.Set("principals_allowed_to_retrieve_managed_password", msa.PrincipalsAllowedToRetrieveManagedPassword)
+	_ = d.Set("service_principal_names", msa.ServicePrincipalNames)
+	_ = d.Set("kerberos_encryption_type", msa.KerberosEncryptionTypes)
+	_ = d.Set("allowed_to_delegate_to", msa.AllowedToDelegateTo)
+	_ = d.Set("trusted_to_auth_for_delegation", msa.TrustedToAuthForDelegation)
+	_ = d.Set("principals_allowed_to_delegate_to_account", msa.PrincipalsAllowedToDelegateToAccount)
+	_ = d.Set("sam_account_name", msa.SAMAccountName)
+	_ = d.Set("dn", msa.DN)
+	_ = d.Set("sid", msa.SID.Value)
+	if !msa.Standalone {
+		_ = d.Set("managed_password_interval_in_days", msa.ManagedPasswordIntervalInDays)
+	}
+
+	return nil
+}
+
+func resourceADManagedServiceAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	msa := winrmhelper.GetManagedServiceAccountFromResource(d)
+
+	if d.HasChange("service_principal_names") && len(msa.ServicePrincipalNames) > 0 {
+		err := winrmhelper.CheckServicePrincipalNameDuplicates(conf, d.Id(), msa.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := msa.ModifyManagedServiceAccount(d, conf)
+	if err != nil {
+		return err
+	}
+
+	changedKeys := []string{}
+	for _, key := range winrmhelper.KerberosDelegationKeys {
+		if d.HasChange(key) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(conf, "Set-ADServiceAccount", d.Id(), changedKeys)
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for managed service account %q: %s", d.Id(), err)
+	}
+
+	return resourceADManagedServiceAccountRead(d, meta)
+}
+
+func resourceADManagedServiceAccountDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	msa := winrmhelper.GetManagedServiceAccountFromResource(d)
+	err := msa.DeleteManagedServiceAccount(meta.(*config.ProviderConf))
+	if err != nil {
+		return fmt.Errorf("while deleting managed service account %q: %s", d.Id(), err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADManagedServiceAccount_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_domain_name",
+		"TF_VAR_ad_group_container",
+	}
+
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	container := os.Getenv("TF_VAR_ad_group_container")
+	name := testAccShortRandomName("gmsa")
+	groupSAM := testAccRandomSAM()
+	resourceName := "windowsad_managed_service_account.m"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADManagedServiceAccountExists(resourceName, name, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADManagedServiceAccountConfig(name, domain, groupSAM, container, "AES256"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADManagedServiceAccountExists(resourceName, name, true),
+					resource.TestCheckResourceAttr(resourceName, "type", "group"),
+					resource.TestCheckResourceAttr(resourceName, "principals_allowed_to_retrieve_managed_password.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "kerberos_encryption_type.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADManagedServiceAccountConfig(name, domain, groupSAM, container, "AES128"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADManagedServiceAccountExists(resourceName, name, true),
+					resource.TestCheckResourceAttr(resourceName, "kerberos_encryption_type.#", "2"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADManagedServiceAccountConfig(name, domain, groupSAM, container, extraEncType string) string {
+	return fmt.Sprintf(`
+resource "windowsad_group" "hosts" {
+  name             = %[3]q
+  sam_account_name = %[3]q
+  container        = %[4]q
+}
+
+resource "windowsad_managed_service_account" "m" {
+  name                                            = %[1]q
+  dns_host_name                                   = "%[1]s.%[2]s"
+  managed_password_interval_in_days               = 30
+  principals_allowed_to_retrieve_managed_password = [windowsad_group.hosts.dn]
+  service_principal_names                         = ["HTTP/%[1]s.%[2]s"]
+  kerberos_encryption_type                        = distinct(["AES256", %[5]q])
+}
+`, name, domain, groupSAM, container, extraEncType)
+}
+
+func testAccResourceADManagedServiceAccountExists(resourceName, name string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		msa, err := winrmhelper.GetManagedServiceAccountFromHost(testAccProvider.Meta().(*config.ProviderConf), rs.Primary.ID)
+		if err != nil {
+			if strings.Contains(err.Error(), "ADIdentityNotFoundException") && !expected {
+				return nil
+			}
+			return err
+		}
+
+		if !expected {
+			return fmt.Errorf("managed service account %q still exists", msa.DN)
+		}
+		if msa.Name != name {
+			return fmt.Errorf("managed service account name %q does not match expected name %q", msa.Name, name)
+		}
+		return nil
+	}
+}
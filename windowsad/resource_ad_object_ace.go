@@ -0,0 +1,248 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/sddl"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADObjectACE() *schema.Resource {
+	aceSchema := objectACESchema(true)
+	aceSchema["target_dn"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Required:         true,
+		ForceNew:         true,
+		DiffSuppressFunc: suppressCaseDiff,
+		Description:      "The distinguished name of the object the ACE is set on.",
+	}
+	aceSchema["principal_sid"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The SID of the principal.",
+	}
+	aceSchema["object_type_guid"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The GUID `object_type` resolves to.",
+	}
+	aceSchema["inherited_object_type_guid"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The GUID `inherited_object_type` resolves to.",
+	}
+	aceSchema["sddl"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The ACE in SDDL form.",
+	}
+
+	return &schema.Resource{
+		Description: "`windowsad_object_ace` manages a single access control entry on an Active Directory object, e.g. to delegate the administration of an OU. Other ACEs of the object are left untouched.",
+		Create:      resourceADObjectACECreate,
+		Read:        resourceADObjectACERead,
+		Delete:      resourceADObjectACEDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: aceSchema,
+	}
+}
+
+// objectACESchema returns the attributes describing an ACE, shared by windowsad_object_ace
+// and the ace blocks of windowsad_object_acl
+func objectACESchema(forceNew bool) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"principal": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ForceNew:         forceNew,
+			DiffSuppressFunc: suppressCaseDiff,
+			Description:      "The principal the ACE applies to. It can be a SID, a distinguished name or an account name like `CONTOSO\\helpdesk`.",
+		},
+		"access_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     forceNew,
+			Default:      "Allow",
+			ValidateFunc: validation.StringInSlice([]string{"Allow", "Deny"}, false),
+			Description:  "Whether the ACE allows or denies the rights, `Allow` or `Deny`.",
+		},
+		"rights": {
+			Type:     schema.TypeSet,
+			Required: true,
+			ForceNew: forceNew,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(sddl.RightNames(), false),
+			},
+			Description: "The rights granted or denied, named after the ActiveDirectoryRights enumeration, e.g. `ReadProperty`, `WriteProperty`, `CreateChild`, `ExtendedRight` or `GenericAll`.",
+		},
+		"object_type": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         forceNew,
+			DiffSuppressFunc: suppressSchemaGUIDDiff,
+			Description:      "The schema class, attribute, property set or extended right the ACE is limited to, by name (e.g. `computer`, `member` or `Reset Password`) or GUID.",
+		},
+		"inherited_object_type": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ForceNew:         forceNew,
+			DiffSuppressFunc: suppressSchemaGUIDDiff,
+			Description:      "The schema class of the child objects that inherit the ACE, by name (e.g. `user`) or GUID.",
+		},
+		"inheritance": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     forceNew,
+			Default:      sddl.InheritanceNone,
+			ValidateFunc: validation.StringInSlice(sddl.InheritanceNames(), false),
+			Description:  "How the ACE is inherited by child objects: `None`, `All` (the object and all descendents), `Descendents`, `SelfAndChildren` or `Children`.",
+		},
+	}
+}
+
+// suppressSchemaGUIDDiff suppresses the diff between a well known schema name and its GUID
+func suppressSchemaGUIDDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldGUID, oldOK := sddl.LookupSchemaGUID(old)
+	newGUID, newOK := sddl.LookupSchemaGUID(new)
+	if !oldOK || !newOK {
+		return strings.EqualFold(old, new)
+	}
+	return oldGUID == newGUID
+}
+
+// getObjectACEFromMap returns an ObjectACE from the attributes of windowsad_object_ace or an
+// ace block of windowsad_object_acl
+func getObjectACEFromMap(m map[string]interface{}) winrmhelper.ObjectACE {
+	rights := []string{}
+	for _, r := range m["rights"].(*schema.Set).List() {
+		rights = append(rights, r.(string))
+	}
+	return winrmhelper.ObjectACE{
+		Principal:           m["principal"].(string),
+		AccessType:          m["access_type"].(string),
+		Rights:              rights,
+		ObjectType:          m["object_type"].(string),
+		InheritedObjectType: m["inherited_object_type"].(string),
+		Inheritance:         m["inheritance"].(string),
+	}
+}
+
+func getObjectACEFromResource(d *schema.ResourceData) winrmhelper.ObjectACE {
+	m := map[string]interface{}{}
+	for _, k := range []string{"principal", "access_type", "rights", "object_type", "inherited_object_type", "inheritance"} {
+		m[k] = d.Get(k)
+	}
+	return getObjectACEFromMap(m)
+}
+
+// parseObjectACEID splits the ID of windowsad_object_ace, <target DN>|<ACE in SDDL form>
+func parseObjectACEID(id string) (string, *sddl.ACE, error) {
+	idx := strings.LastIndex(id, "|")
+	if idx < 0 {
+		return "", nil, fmt.Errorf("invalid ID %q, expected <target DN>|<ACE in SDDL form>", id)
+	}
+	ace, err := sddl.ParseACE(id[idx+1:])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid ID %q: %s", id, err)
+	}
+	// ACEs read from the object have their trustee aliases replaced by SIDs, so an alias
+	// would never match
+	if !sddl.IsSID(ace.Trustee) {
+		return "", nil, fmt.Errorf("invalid ID %q: the trustee %q must be a SID, SID aliases are not supported", id, ace.Trustee)
+	}
+	return id[:idx], ace, nil
+}
+
+func resourceADObjectACECreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	targetDN := d.Get("target_dn").(string)
+
+	aces, err := winrmhelper.ResolveObjectACEs(conf, []winrmhelper.ObjectACE{getObjectACEFromResource(d)})
+	if err != nil {
+		return err
+	}
+
+	conf.LockObject(targetDN)
+	defer conf.UnlockObject(targetDN)
+	err = winrmhelper.ModifyObjectACEs(conf, targetDN, aces, nil)
+	if err != nil {
+		return fmt.Errorf("while adding ACE %s to %q: %s", aces[0].String(), targetDN, err)
+	}
+	d.SetId(fmt.Sprintf("%s|%s", targetDN, aces[0].String()))
+
+	return resourceADObjectACERead(d, meta)
+}
+
+func resourceADObjectACERead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	targetDN, ace, err := parseObjectACEID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	sd, err := winrmhelper.GetObjectSecurityDescriptor(meta.(*config.ProviderConf), targetDN)
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if !sd.ContainsExplicitACE(*ace) {
+		d.SetId("")
+		return nil
+	}
+
+	// the configured attributes are only populated from the ACE when importing
+	if _, ok := d.GetOk("principal"); !ok {
+		objectACE, err := winrmhelper.NewObjectACEFromSDDL(*ace)
+		if err != nil {
+			return err
+		}
+		_ = d.Set("principal", objectACE.Principal)
+		_ = d.Set("access_type", objectACE.AccessType)
+		_ = d.Set("rights", objectACE.Rights)
+		_ = d.Set("object_type", objectACE.ObjectType)
+		_ = d.Set("inherited_object_type", objectACE.InheritedObjectType)
+		_ = d.Set("inheritance", objectACE.Inheritance)
+	}
+	_ = d.Set("target_dn", targetDN)
+	_ = d.Set("principal_sid", ace.Trustee)
+	_ = d.Set("object_type_guid", ace.ObjectType)
+	_ = d.Set("inherited_object_type_guid", ace.InheritedObjectType)
+	_ = d.Set("sddl", ace.String())
+
+	return nil
+}
+
+func resourceADObjectACEDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	targetDN, ace, err := parseObjectACEID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	conf := meta.(*config.ProviderConf)
+	conf.LockObject(targetDN)
+	defer conf.UnlockObject(targetDN)
+	err = winrmhelper.ModifyObjectACEs(conf, targetDN, nil, []sddl.ACE{*ace})
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			return nil
+		}
+		return fmt.Errorf("while removing ACE %s from %q: %s", ace.String(), targetDN, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADObjectACE_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_group_container",
+	}
+
+	path := os.Getenv("TF_VAR_ad_user_container")
+	groupContainer := os.Getenv("TF_VAR_ad_group_container")
+	ouName := testAccRandomName("tfacc-ou")
+	groupSAM := testAccRandomSAM()
+	resourceName := "windowsad_object_ace.a"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADObjectACEConfig(ouName, path, groupSAM, groupContainer),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "object_type_guid", "00299570-246d-11d0-a768-00aa006e0529"),
+					resource.TestCheckResourceAttr(resourceName, "inherited_object_type_guid", "bf967aba-0de6-11d0-a285-00aa003049e2"),
+					resource.TestCheckResourceAttrPair(resourceName, "principal_sid", "windowsad_group.g", "sid"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"principal", "object_type", "inherited_object_type"},
+			},
+		},
+	})
+}
+
+func testAccResourceADObjectACEConfig(ouName, path, groupSAM, groupContainer string) string {
+	return fmt.Sprintf(`
+resource "windowsad_ou" "o" {
+  name      = %[1]q
+  path      = %[2]q
+  protected = false
+}
+
+resource "windowsad_group" "g" {
+  name             = %[3]q
+  sam_account_name = %[3]q
+  container        = %[4]q
+}
+
+resource "windowsad_object_ace" "a" {
+  target_dn             = windowsad_ou.o.dn
+  principal             = windowsad_group.g.dn
+  rights                = ["ExtendedRight"]
+  object_type           = "Reset Password"
+  inherited_object_type = "user"
+  inheritance           = "Descendents"
+}
+`, ouName, path, groupSAM, groupContainer)
+}
@@ -0,0 +1,154 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceADObjectACL() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_object_acl` manages a set of access control entries on an Active Directory object. Only the ACEs listed in the configuration are managed, other ACEs of the object are left untouched.",
+		Create:      resourceADObjectACLCreate,
+		Read:        resourceADObjectACLRead,
+		Update:      resourceADObjectACLUpdate,
+		Delete:      resourceADObjectACLDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"target_dn": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The distinguished name of the object the ACEs are set on.",
+			},
+			"ace": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Resource{Schema: objectACESchema(false)},
+				Description: "An access control entry managed on the object.",
+			},
+		},
+	}
+}
+
+func getObjectACEsFromSet(s *schema.Set) []winrmhelper.ObjectACE {
+	aces := []winrmhelper.ObjectACE{}
+	for _, item := range s.List() {
+		aces = append(aces, getObjectACEFromMap(item.(map[string]interface{})))
+	}
+	return aces
+}
+
+func resourceADObjectACLCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	targetDN := d.Get("target_dn").(string)
+
+	aces, err := winrmhelper.ResolveObjectACEs(conf, getObjectACEsFromSet(d.Get("ace").(*schema.Set)))
+	if err != nil {
+		return err
+	}
+
+	conf.LockObject(targetDN)
+	defer conf.UnlockObject(targetDN)
+	err = winrmhelper.ModifyObjectACEs(conf, targetDN, aces, nil)
+	if err != nil {
+		return fmt.Errorf("while adding ACEs to %q: %s", targetDN, err)
+	}
+	d.SetId(targetDN)
+
+	return resourceADObjectACLRead(d, meta)
+}
+
+func resourceADObjectACLRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	conf := meta.(*config.ProviderConf)
+
+	sd, err := winrmhelper.GetObjectSecurityDescriptor(conf, d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// only the ACEs in the state are read back, the ones that were removed outside of
+	// terraform are dropped so they show up in the plan
+	aceSet := d.Get("ace").(*schema.Set)
+	configured := getObjectACEsFromSet(aceSet)
+	aces, err := winrmhelper.ResolveObjectACEs(conf, configured)
+	if err != nil {
+		return err
+	}
+
+	present := []interface{}{}
+	for idx, item := range aceSet.List() {
+		if sd.ContainsExplicitACE(aces[idx]) {
+			present = append(present, item)
+		}
+	}
+
+	_ = d.Set("target_dn", d.Id())
+	_ = d.Set("ace", present)
+
+	return nil
+}
+
+func resourceADObjectACLUpdate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+
+	if d.HasChange("ace") {
+		oldValue, newValue := d.GetChange("ace")
+		oldSet, newSet := oldValue.(*schema.Set), newValue.(*schema.Set)
+
+		toRemove, err := winrmhelper.ResolveObjectACEs(conf, getObjectACEsFromSet(oldSet.Difference(newSet)))
+		if err != nil {
+			return err
+		}
+		toAdd, err := winrmhelper.ResolveObjectACEs(conf, getObjectACEsFromSet(newSet))
+		if err != nil {
+			return err
+		}
+
+		conf.LockObject(d.Id())
+		defer conf.UnlockObject(d.Id())
+		err = winrmhelper.ModifyObjectACEs(conf, d.Id(), toAdd, toRemove)
+		if err != nil {
+			return fmt.Errorf("while updating the ACEs of %q: %s", d.Id(), err)
+		}
+	}
+
+	return resourceADObjectACLRead(d, meta)
+}
+
+func resourceADObjectACLDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	conf := meta.(*config.ProviderConf)
+
+	aces, err := winrmhelper.ResolveObjectACEs(conf, getObjectACEsFromSet(d.Get("ace").(*schema.Set)))
+	if err != nil {
+		return err
+	}
+
+	conf.LockObject(d.Id())
+	defer conf.UnlockObject(d.Id())
+	err = winrmhelper.ModifyObjectACEs(conf, d.Id(), nil, aces)
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			return nil
+		}
+		return fmt.Errorf("while removing ACEs from %q: %s", d.Id(), err)
+	}
+	return nil
+}
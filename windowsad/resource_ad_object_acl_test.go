@@ -0,0 +1,87 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADObjectACL_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_group_container",
+	}
+
+	path := os.Getenv("TF_VAR_ad_user_container")
+	groupContainer := os.Getenv("TF_VAR_ad_group_container")
+	ouName := testAccRandomName("tfacc-ou")
+	groupSAM := testAccRandomSAM()
+	resourceName := "windowsad_object_acl.a"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADObjectACLConfig(ouName, path, groupSAM, groupContainer, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ace.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADObjectACLConfig(ouName, path, groupSAM, groupContainer, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ace.#", "2"),
+				),
+			},
+			{
+				Config: testAccResourceADObjectACLConfig(ouName, path, groupSAM, groupContainer, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "ace.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceADObjectACLConfig(ouName, path, groupSAM, groupContainer string, manageMembers bool) string {
+	membersACE := ""
+	if manageMembers {
+		membersACE = `
+  ace {
+    principal             = windowsad_group.g.dn
+    rights                = ["ReadProperty", "WriteProperty"]
+    object_type           = "member"
+    inherited_object_type = "group"
+    inheritance           = "Descendents"
+  }
+`
+	}
+	return fmt.Sprintf(`
+resource "windowsad_ou" "o" {
+  name      = %[1]q
+  path      = %[2]q
+  protected = false
+}
+
+resource "windowsad_group" "g" {
+  name             = %[3]q
+  sam_account_name = %[3]q
+  container        = %[4]q
+}
+
+resource "windowsad_object_acl" "a" {
+  target_dn = windowsad_ou.o.dn
+
+  ace {
+    principal   = windowsad_group.g.dn
+    rights      = ["CreateChild", "DeleteChild"]
+    object_type = "computer"
+    inheritance = "All"
+  }
+%[5]s}
+`, ouName, path, groupSAM, groupContainer, membersACE)
+}
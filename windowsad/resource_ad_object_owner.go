@@ -0,0 +1,132 @@
+package windowsad
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceADObjectOwner() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_object_owner` enforces the owner of an Active Directory object, e.g. to make Domain Admins the owner of computer accounts instead of the technician who joined them.",
+		Create:      resourceADObjectOwnerCreate,
+		Read:        resourceADObjectOwnerRead,
+		Update:      resourceADObjectOwnerUpdate,
+		Delete:      resourceADObjectOwnerDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"target_dn": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The distinguished name of the object. Users, groups, computers, OUs and GPO containers are all supported.",
+			},
+			"owner": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The owner of the object. It can be a SID, a distinguished name or an account name like `CONTOSO\\Domain Admins`.",
+			},
+			"owner_sid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SID of the owner.",
+			},
+		},
+	}
+}
+
+func resolveObjectOwner(conf *config.ProviderConf, owner string) (string, error) {
+	sids, err := winrmhelper.ResolvePrincipalSIDs(conf, []string{owner})
+	if err != nil {
+		return "", err
+	}
+	return sids[owner], nil
+}
+
+func resourceADObjectOwnerCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	targetDN := d.Get("target_dn").(string)
+
+	ownerSID, err := resolveObjectOwner(conf, d.Get("owner").(string))
+	if err != nil {
+		return err
+	}
+	conf.LockObject(targetDN)
+	defer conf.UnlockObject(targetDN)
+	err = winrmhelper.SetObjectOwner(conf, targetDN, ownerSID)
+	if err != nil {
+		return fmt.Errorf("while setting the owner of %q: %s", targetDN, err)
+	}
+	d.SetId(targetDN)
+
+	return resourceADObjectOwnerRead(d, meta)
+}
+
+func resourceADObjectOwnerRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	conf := meta.(*config.ProviderConf)
+
+	ownerSID, err := winrmhelper.GetObjectOwner(conf, d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// the owner is kept as configured as long as it resolves to the actual owner, otherwise
+	// the SID of the actual owner is stored so the drift shows up in the plan
+	owner := d.Get("owner").(string)
+	expectedSID := ""
+	if owner != "" {
+		expectedSID, err = resolveObjectOwner(conf, owner)
+		if err != nil {
+			log.Printf("[WARN] could not resolve the configured owner %q of %q: %s", owner, d.Id(), err)
+		}
+	}
+	if !strings.EqualFold(expectedSID, ownerSID) {
+		_ = d.Set("owner", ownerSID)
+	}
+	_ = d.Set("target_dn", d.Id())
+	_ = d.Set("owner_sid", ownerSID)
+
+	return nil
+}
+
+func resourceADObjectOwnerUpdate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+
+	if d.HasChange("owner") {
+		ownerSID, err := resolveObjectOwner(conf, d.Get("owner").(string))
+		if err != nil {
+			return err
+		}
+		conf.LockObject(d.Id())
+		defer conf.UnlockObject(d.Id())
+		err = winrmhelper.SetObjectOwner(conf, d.Id(), ownerSID)
+		if err != nil {
+			return fmt.Errorf("while setting the owner of %q: %s", d.Id(), err)
+		}
+	}
+
+	return resourceADObjectOwnerRead(d, meta)
+}
+
+func resourceADObjectOwnerDelete(d *schema.ResourceData, meta interface{}) error {
+	// An object always has an owner, so the current owner is kept and the resource is only
+	// removed from the state.
+	log.Printf("[WARN] The owner of %q is left unchanged, it is only removed from the terraform state", d.Id())
+	return nil
+}
@@ -0,0 +1,68 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADObjectOwner_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_group_container",
+	}
+
+	path := os.Getenv("TF_VAR_ad_user_container")
+	groupContainer := os.Getenv("TF_VAR_ad_group_container")
+	ouName := testAccRandomName("tfacc-ou")
+	groupSAM := testAccRandomSAM()
+	resourceName := "windowsad_object_owner.o"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADObjectOwnerConfig(ouName, path, groupSAM, groupContainer, "windowsad_group.g.dn"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "owner_sid", "windowsad_group.g", "sid"),
+				),
+			},
+			{
+				Config: testAccResourceADObjectOwnerConfig(ouName, path, groupSAM, groupContainer, `"S-1-5-32-544"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "owner_sid", "S-1-5-32-544"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADObjectOwnerConfig(ouName, path, groupSAM, groupContainer, owner string) string {
+	return fmt.Sprintf(`
+resource "windowsad_ou" "o" {
+  name      = %[1]q
+  path      = %[2]q
+  protected = false
+}
+
+resource "windowsad_group" "g" {
+  name             = %[3]q
+  sam_account_name = %[3]q
+  container        = %[4]q
+}
+
+resource "windowsad_object_owner" "o" {
+  target_dn = windowsad_ou.o.dn
+  owner     = %[5]s
+}
+`, ouName, path, groupSAM, groupContainer, owner)
+}
@@ -0,0 +1,141 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceADOfflineDomainJoin() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_offline_domain_join` pre-stages a computer account and provisions an offline domain join (djoin) blob that can be used to join a machine to the domain without network access to a DC.",
+		Read:        resourceADOfflineDomainJoinRead,
+		Create:      resourceADOfflineDomainJoinCreate,
+		Delete:      resourceADOfflineDomainJoinDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The name for the computer account.",
+			},
+			"container": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The DN of the container used to hold the computer account. Defaults to the domain's default computers container.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Specifies a description of the computer object.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The DNS name of the domain the machine will join. Defaults to the domain of the WinRM host.",
+			},
+			"odj_blob": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The base64 encoded offline domain join blob, as produced by `djoin /provision`. It can be passed to `djoin /requestodj` or used in an unattend file.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the computer object.",
+			},
+			"guid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The GUID of the computer object.",
+			},
+			"sid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SID of the computer object.",
+			},
+		},
+	}
+}
+
+func resourceADOfflineDomainJoinRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	computer, err := winrmhelper.NewComputerFromHost(meta.(*config.ProviderConf), d.Id(), nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "ObjectNotFound") {
+			// The computer account was removed, the blob is no longer usable
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error while reading computer with GUID %q: %s", d.Id(), err)
+	}
+	_ = d.Set("name", computer.Name)
+	_ = d.Set("container", computer.Path)
+	_ = d.Set("description", computer.Description)
+	_ = d.Set("dn", computer.DN)
+	_ = d.Set("guid", computer.GUID)
+	_ = d.Set("sid", computer.SID.Value)
+
+	return nil
+}
+
+func resourceADOfflineDomainJoinCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	computer := &winrmhelper.Computer{
+		Name:        winrmhelper.SanitiseTFInput(d, "name"),
+		Path:        winrmhelper.SanitiseTFInput(d, "container"),
+		Description: winrmhelper.SanitiseTFInput(d, "description"),
+		Enabled:     true,
+	}
+	guid, err := computer.Create(conf)
+	if err != nil {
+		return fmt.Errorf("error while pre-staging computer object: %s", err)
+	}
+	d.SetId(guid)
+
+	odj := &winrmhelper.OfflineDomainJoin{
+		Domain:      winrmhelper.SanitiseTFInput(d, "domain"),
+		MachineName: computer.Name,
+		MachineOU:   computer.Path,
+		DCName:      conf.Settings.DomainController,
+	}
+	blob, err := odj.Provision(conf)
+	if err != nil {
+		return fmt.Errorf("error while provisioning offline domain join blob for computer %q: %s", computer.Name, err)
+	}
+	_ = d.Set("odj_blob", blob)
+
+	return resourceADOfflineDomainJoinRead(d, meta)
+}
+
+func resourceADOfflineDomainJoinDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	computer := &winrmhelper.Computer{
+		GUID: d.Id(),
+	}
+	err := computer.Delete(meta.(*config.ProviderConf))
+	if err != nil {
+		if strings.Contains(err.Error(), "ObjectNotFound") {
+			return nil
+		}
+		return fmt.Errorf("error while deleting a computer object with id %q: %s", d.Id(), err)
+	}
+
+	return nil
+}
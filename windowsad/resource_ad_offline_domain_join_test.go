@@ -0,0 +1,46 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResourceADOfflineDomainJoin_basic(t *testing.T) {
+
+	envVars := []string{"TF_VAR_ad_computer_container"}
+
+	container := os.Getenv("TF_VAR_ad_computer_container")
+	computerName := testAccShortRandomName("odj")
+	resourceName := "windowsad_offline_domain_join.odj"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADComputerExists(resourceName, computerName, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADOfflineDomainJoinConfig(computerName, container),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADComputerExists(resourceName, computerName, true),
+					resource.TestCheckResourceAttrSet(resourceName, "odj_blob"),
+					resource.TestCheckResourceAttrSet(resourceName, "sid"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceADOfflineDomainJoinConfig(name, container string) string {
+	return fmt.Sprintf(`
+resource "windowsad_offline_domain_join" "odj" {
+  name        = %[1]q
+  container   = %[2]q
+  description = "tfacc offline domain join"
+}
+`, name, container)
+}
@@ -0,0 +1,247 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceADPasswordSettingsObject() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_password_settings_object` manages fine-grained password policies (password settings objects) and the users and groups they apply to.",
+		Create:      resourceADPasswordSettingsObjectCreate,
+		Read:        resourceADPasswordSettingsObjectRead,
+		Update:      resourceADPasswordSettingsObjectUpdate,
+		Delete:      resourceADPasswordSettingsObjectDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the password settings object.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The display name of the password settings object.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the password settings object.",
+			},
+			"precedence": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The precedence of the policy. When several policies apply to a user, the one with the lowest precedence wins.",
+			},
+			"complexity_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether passwords must meet complexity requirements.",
+			},
+			"min_password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      7,
+				ValidateFunc: validation.IntBetween(0, 255),
+				Description:  "The minimum password length.",
+			},
+			"password_history_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      24,
+				ValidateFunc: validation.IntBetween(0, 1024),
+				Description:  "The number of previous passwords that can't be reused.",
+			},
+			"min_password_age": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "1.00:00:00",
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The minimum password age, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"max_password_age": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "42.00:00:00",
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The maximum password age, as a time span in the `[d.]hh:mm:ss` format. Use `00:00:00` for passwords that never expire.",
+			},
+			"lockout_threshold": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 65535),
+				Description:  "The number of failed logon attempts that cause the account to be locked out. `0` disables lockout.",
+			},
+			"lockout_duration": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "00:30:00",
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "How long an account stays locked out, as a time span in the `[d.]hh:mm:ss` format.",
+			},
+			"lockout_observation_window": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "00:30:00",
+				ValidateFunc:     validateTimeSpan,
+				DiffSuppressFunc: suppressTimeSpanDiff,
+				Description:      "The time after which the failed logon attempts counter is reset, as a time span in the `[d.]hh:mm:ss` format. It must not be longer than `lockout_duration`.",
+			},
+			"reversible_encryption_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether passwords are stored using reversible encryption.",
+			},
+			"protected_from_accidental_deletion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If set to true, the object is protected from accidental deletion. The provider clears the flag before deleting the object.",
+			},
+			"applies_to": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The distinguished names of the users and global security groups the policy applies to. This list is authoritative, subjects that are not listed are removed.",
+			},
+			"dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the password settings object.",
+			},
+		},
+	}
+}
+
+func validateTimeSpan(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+	if _, err := winrmhelper.ParseTimeSpan(v); err != nil {
+		return nil, []error{fmt.Errorf("%q: %s", k, err)}
+	}
+	return nil, nil
+}
+
+func suppressTimeSpanDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldValue, err := winrmhelper.ParseTimeSpan(old)
+	if err != nil {
+		return false
+	}
+	newValue, err := winrmhelper.ParseTimeSpan(new)
+	if err != nil {
+		return false
+	}
+	return oldValue == newValue
+}
+
+func getAppliesToFromResource(d *schema.ResourceData) []string {
+	subjects := []string{}
+	for _, s := range d.Get("applies_to").(*schema.Set).List() {
+		subjects = append(subjects, winrmhelper.SanitiseString(s.(string)))
+	}
+	return subjects
+}
+
+func resourceADPasswordSettingsObjectCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	guid, err := winrmhelper.NewPasswordSettingsObject(conf, d)
+	if err != nil {
+		return err
+	}
+	d.SetId(guid)
+
+	err = winrmhelper.SetPasswordSettingsObjectSubjects(conf, guid, []string{}, getAppliesToFromResource(d))
+	if err != nil {
+		return fmt.Errorf("while setting the subjects of password settings object %q: %s", guid, err)
+	}
+
+	return resourceADPasswordSettingsObjectRead(d, meta)
+}
+
+func resourceADPasswordSettingsObjectRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	pso, err := winrmhelper.GetPasswordSettingsObjectFromHost(meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	_ = d.Set("name", pso.Name)
+	_ = d.Set("display_name", pso.DisplayName)
+	_ = d.Set("description", pso.Description)
+	_ = d.Set("precedence", pso.Precedence)
+	_ = d.Set("complexity_enabled", pso.ComplexityEnabled)
+	_ = d.Set("min_password_length", pso.MinPasswordLength)
+	_ = d.Set("password_history_count", pso.PasswordHistoryCount)
+	_ = d.Set("min_password_age", pso.MinPasswordAge)
+	_ = d.Set("max_password_age", pso.MaxPasswordAge)
+	_ = d.Set("lockout_threshold", pso.LockoutThreshold)
+	_ = d.Set("lockout_duration", pso.LockoutDuration)
+	_ = d.Set("lockout_observation_window", pso.LockoutObservationWindow)
+	_ = d.Set("reversible_encryption_enabled", pso.ReversibleEncryptionEnabled)
+	_ = d.Set("protected_from_accidental_deletion", pso.ProtectedFromDeletion)
+	_ = d.Set("applies_to", pso.AppliesTo)
+	_ = d.Set("dn", pso.DN)
+
+	return nil
+}
+
+func resourceADPasswordSettingsObjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	err := winrmhelper.UpdatePasswordSettingsObject(conf, d)
+	if err != nil {
+		return fmt.Errorf("while updating password settings object %q: %s", d.Id(), err)
+	}
+
+	if d.HasChange("applies_to") {
+		// the subjects are read back from the host to catch any change made outside of terraform
+		pso, err := winrmhelper.GetPasswordSettingsObjectFromHost(conf, d.Id())
+		if err != nil {
+			return err
+		}
+		err = winrmhelper.SetPasswordSettingsObjectSubjects(conf, d.Id(), pso.AppliesTo, getAppliesToFromResource(d))
+		if err != nil {
+			return fmt.Errorf("while setting the subjects of password settings object %q: %s", d.Id(), err)
+		}
+	}
+
+	return resourceADPasswordSettingsObjectRead(d, meta)
+}
+
+func resourceADPasswordSettingsObjectDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	err := winrmhelper.DeletePasswordSettingsObject(meta.(*config.ProviderConf), d.Id())
+	if err != nil {
+		if strings.Contains(err.Error(), "ADIdentityNotFoundException") {
+			return nil
+		}
+		return fmt.Errorf("while deleting password settings object %q: %s", d.Id(), err)
+	}
+	return nil
+}
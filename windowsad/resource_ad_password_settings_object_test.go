@@ -0,0 +1,105 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADPasswordSettingsObject_basic(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_group_container",
+	}
+
+	container := os.Getenv("TF_VAR_ad_group_container")
+	name := testAccShortRandomName("pso")
+	groupSAM := testAccRandomSAM()
+	resourceName := "windowsad_password_settings_object.p"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADPasswordSettingsObjectExists(resourceName, name, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADPasswordSettingsObjectConfig(name, groupSAM, container, 12, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADPasswordSettingsObjectExists(resourceName, name, true),
+					resource.TestCheckResourceAttr(resourceName, "min_password_length", "12"),
+					resource.TestCheckResourceAttr(resourceName, "applies_to.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceADPasswordSettingsObjectConfig(name, groupSAM, container, 14, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADPasswordSettingsObjectExists(resourceName, name, true),
+					resource.TestCheckResourceAttr(resourceName, "min_password_length", "14"),
+					resource.TestCheckResourceAttr(resourceName, "applies_to.#", "0"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceADPasswordSettingsObjectConfig(name, groupSAM, container string, minLength int, applyToGroup bool) string {
+	appliesTo := "[]"
+	if applyToGroup {
+		appliesTo = "[windowsad_group.g.dn]"
+	}
+	return fmt.Sprintf(`
+resource "windowsad_group" "g" {
+  name             = %[2]q
+  sam_account_name = %[2]q
+  container        = %[3]q
+}
+
+resource "windowsad_password_settings_object" "p" {
+  name                = %[1]q
+  precedence          = 10
+  min_password_length = %[4]d
+  max_password_age    = "90.00:00:00"
+  lockout_threshold   = 5
+  applies_to          = %[5]s
+}
+`, name, groupSAM, container, minLength, appliesTo)
+}
+
+func testAccResourceADPasswordSettingsObjectExists(resourceName, name string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", resourceName)
+		}
+
+		pso, err := winrmhelper.GetPasswordSettingsObjectFromHost(testAccProvider.Meta().(*config.ProviderConf), rs.Primary.ID)
+		if err != nil {
+			if strings.Contains(err.Error(), "ADIdentityNotFoundException") && !expected {
+				return nil
+			}
+			return err
+		}
+
+		if !expected {
+			return fmt.Errorf("password settings object %q still exists", pso.DN)
+		}
+		if pso.Name != name {
+			return fmt.Errorf("password settings object name %q does not match expected name %q", pso.Name, name)
+		}
+		return nil
+	}
+}
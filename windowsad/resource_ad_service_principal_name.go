@@ -0,0 +1,130 @@
+package windowsad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceADServicePrincipalName() *schema.Resource {
+	return &schema.Resource{
+		Description: "`windowsad_service_principal_name` registers a single service principal name (SPN) on a user or computer account. The SPN is rejected if it's already registered on another object in the forest.",
+		Create:      resourceADServicePrincipalNameCreate,
+		Read:        resourceADServicePrincipalNameRead,
+		Delete:      resourceADServicePrincipalNameDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"account": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The GUID or the distinguished name of the user or computer account that holds the SPN.",
+			},
+			"spn": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "The service principal name, for example `HTTP/web.example.com`.",
+			},
+			"account_guid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The GUID of the account that holds the SPN.",
+			},
+			"account_dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The distinguished name of the account that holds the SPN.",
+			},
+		},
+	}
+}
+
+func resourceADServicePrincipalNameCreate(d *schema.ResourceData, meta interface{}) error {
+	conf := meta.(*config.ProviderConf)
+	account := winrmhelper.SanitiseTFInput(d, "account")
+	owner, err := winrmhelper.GetServicePrincipalNameOwnerFromHost(conf, account)
+	if err != nil {
+		return fmt.Errorf("while retrieving account %q: %s", account, err)
+	}
+
+	spn := &winrmhelper.ServicePrincipalName{
+		AccountGUID: owner.GUID,
+		SPN:         winrmhelper.SanitiseTFInput(d, "spn"),
+	}
+	if !owner.HasServicePrincipalName(spn.SPN) {
+		err = spn.Add(conf)
+		if err != nil {
+			return fmt.Errorf("while registering SPN %q on account %q: %s", spn.SPN, account, err)
+		}
+	}
+	d.SetId(fmt.Sprintf("%s/%s", owner.GUID, spn.SPN))
+
+	return resourceADServicePrincipalNameRead(d, meta)
+}
+
+func resourceADServicePrincipalNameRead(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	// SPNs contain slashes, the GUID never does
+	toks := strings.SplitN(d.Id(), "/", 2)
+	if len(toks) != 2 {
+		return fmt.Errorf("invalid ID %q, expected <account GUID>/<SPN>", d.Id())
+	}
+	accountGUID, spn := toks[0], toks[1]
+
+	owner, err := winrmhelper.GetServicePrincipalNameOwnerFromHost(meta.(*config.ProviderConf), accountGUID)
+	if err != nil {
+		if strings.Contains(err.Error(), "ObjectNotFound") {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if !owner.HasServicePrincipalName(spn) {
+		d.SetId("")
+		return nil
+	}
+
+	// account is kept as configured, it's only populated here when importing
+	if _, ok := d.GetOk("account"); !ok {
+		_ = d.Set("account", owner.GUID)
+	}
+	_ = d.Set("spn", spn)
+	_ = d.Set("account_guid", owner.GUID)
+	_ = d.Set("account_dn", owner.DN)
+
+	return nil
+}
+
+func resourceADServicePrincipalNameDelete(d *schema.ResourceData, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	toks := strings.SplitN(d.Id(), "/", 2)
+	if len(toks) != 2 {
+		return fmt.Errorf("invalid ID %q, expected <account GUID>/<SPN>", d.Id())
+	}
+
+	spn := &winrmhelper.ServicePrincipalName{
+		AccountGUID: toks[0],
+		SPN:         toks[1],
+	}
+	err := spn.Remove(meta.(*config.ProviderConf))
+	if err != nil {
+		if strings.Contains(err.Error(), "ObjectNotFound") {
+			return nil
+		}
+		return fmt.Errorf("while removing SPN %q from account %q: %s", spn.SPN, spn.AccountGUID, err)
+	}
+	return nil
+}
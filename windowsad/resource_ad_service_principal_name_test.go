@@ -0,0 +1,102 @@
+package windowsad
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/config"
+
+	"github.com/JohanVanosmaelAcerta/terraform-provider-windowsad/windowsad/internal/winrmhelper"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccResourceADServicePrincipalName_basic(t *testing.T) {
+
+	envVars := []string{"TF_VAR_ad_computer_container"}
+
+	container := os.Getenv("TF_VAR_ad_computer_container")
+	computerName := testAccShortRandomName("pc")
+	otherComputerName := testAccShortRandomName("pc")
+	spn := fmt.Sprintf("HTTP/%s.tfacc.local", computerName)
+	resourceName := "windowsad_service_principal_name.spn"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADComputerExists("windowsad_computer.c", computerName, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADServicePrincipalNameConfig(computerName, otherComputerName, container, spn, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADServicePrincipalNameExists(resourceName, true),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"account"},
+			},
+			{
+				Config:      testAccResourceADServicePrincipalNameConfig(computerName, otherComputerName, container, spn, true),
+				ExpectError: regexp.MustCompile("duplicate SPNs found"),
+			},
+		},
+	})
+}
+
+func testAccResourceADServicePrincipalNameConfig(name, otherName, container, spn string, duplicate bool) string {
+	cfg := fmt.Sprintf(`
+resource "windowsad_computer" "c" {
+  name      = %[1]q
+  container = %[3]q
+}
+
+resource "windowsad_computer" "other" {
+  name      = %[2]q
+  container = %[3]q
+}
+
+resource "windowsad_service_principal_name" "spn" {
+  account = windowsad_computer.c.guid
+  spn     = %[4]q
+}
+`, name, otherName, container, spn)
+
+	if duplicate {
+		cfg += fmt.Sprintf(`
+resource "windowsad_service_principal_name" "duplicate" {
+  account = windowsad_computer.other.guid
+  spn     = %q
+
+  depends_on = [windowsad_service_principal_name.spn]
+}
+`, spn)
+	}
+	return cfg
+}
+
+func testAccResourceADServicePrincipalNameExists(name string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s key not found in state", name)
+		}
+
+		owner, err := winrmhelper.GetServicePrincipalNameOwnerFromHost(testAccProvider.Meta().(*config.ProviderConf), rs.Primary.Attributes["account_guid"])
+		if err != nil {
+			return err
+		}
+
+		spn := rs.Primary.Attributes["spn"]
+		if owner.HasServicePrincipalName(spn) != expected {
+			return fmt.Errorf("SPN %q registered on %q: expected %t", spn, owner.DN, expected)
+		}
+		return nil
+	}
+}
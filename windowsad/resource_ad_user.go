@@ -225,6 +225,40 @@ func resourceADUser() *schema.Resource {
 				Default:     false,
 				Description: "If set to true, the user account is trusted for Kerberos delegation. A service that runs under an account that is trusted for Kerberos delegation can assume the identity of a client requesting the service. This parameter sets the TrustedForDelegation property of an account object.",
 			},
+			"protected_from_accidental_deletion": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If set to true, the user will be protected from accidental deletion. The protection is lifted when the user is renamed, moved or destroyed by terraform.",
+			},
+			"restore_from_recycle_bin": restoreFromRecycleBinSchema("user"),
+			"service_principal_names": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The service principal names (SPNs) of the user account. SPNs that are already registered on another object in the forest are rejected. If omitted, the SPNs of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"allowed_to_delegate_to": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The SPNs of the services this user account can present delegated credentials to (constrained delegation). This parameter sets the msDS-AllowedToDelegateTo attribute. If omitted, the constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
+			"trusted_to_auth_for_delegation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "If set to true, the user account can use protocol transition for constrained delegation, i.e. delegate without the client authenticating with Kerberos. This parameter sets the TrustedToAuthForDelegation property of an account object. If omitted, the setting is not managed by terraform.",
+			},
+			"principals_allowed_to_delegate_to_account": {
+				Type:             schema.TypeSet,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				DiffSuppressFunc: suppressUnconfiguredDiff,
+				Description:      "The distinguished names of the principals that are allowed to delegate to this user account (resource-based constrained delegation). This parameter sets the PrincipalsAllowedToDelegateToAccount property, which is stored in the msDS-AllowedToActOnBehalfOfOtherIdentity security descriptor. If omitted, the resource-based constrained delegation settings of the account are not managed by terraform. Set it to an empty set to remove them.",
+			},
 			"custom_attributes": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -269,11 +303,36 @@ func resourceADUserCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("while building a User struct from resource data: %s", err)
 	}
 
+	if len(u.ServicePrincipalNames) > 0 {
+		err = winrmhelper.CheckServicePrincipalNameDuplicates(meta.(*config.ProviderConf), "", u.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+
+	name := u.Name
+	if name == "" {
+		name = u.Username
+	}
+	restored, err := restoreFromRecycleBin(d, meta, "user", d.Get("sam_account_name").(string), u.Container, name)
+	if err != nil {
+		return err
+	}
+	if restored {
+		return resourceADUserUpdate(d, meta)
+	}
+
 	guid, err := u.NewUser(meta.(*config.ProviderConf))
 	if err != nil {
 		return err
 	}
 	d.SetId(guid)
+
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(meta.(*config.ProviderConf), "Set-ADUser", guid, delegation.ConfiguredKeys())
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for user %q: %s", guid, err)
+	}
 	// We need to set this so we can then retrieve the list of attributes to look for while "reading"
 	if u.CustomAttributes != nil {
 		caMap := make(map[string]interface{})
@@ -349,6 +408,11 @@ func resourceADUserRead(d *schema.ResourceData, meta interface{}) error {
 	_ = d.Set("title", u.Title)
 	_ = d.Set("smart_card_logon_required", u.SmartcardLogonRequired)
 	_ = d.Set("trusted_for_delegation", u.TrustedForDelegation)
+	_ = d.Set("protected_from_accidental_deletion", u.Protected)
+	_ = d.Set("service_principal_names", u.ServicePrincipalNames)
+	_ = d.Set("allowed_to_delegate_to", u.AllowedToDelegateTo)
+	_ = d.Set("trusted_to_auth_for_delegation", u.TrustedToAuthForDelegation)
+	_ = d.Set("principals_allowed_to_delegate_to_account", u.PrincipalsAllowedToDelegateToAccount)
 
 	if u.CustomAttributes != nil {
 		ca, err := structure.FlattenJsonToString(u.CustomAttributes)
@@ -367,10 +431,29 @@ func resourceADUserUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	if d.HasChange("service_principal_names") && len(u.ServicePrincipalNames) > 0 {
+		err = winrmhelper.CheckServicePrincipalNameDuplicates(meta.(*config.ProviderConf), d.Id(), u.ServicePrincipalNames)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = u.ModifyUser(d, meta.(*config.ProviderConf))
 	if err != nil {
 		return err
 	}
+
+	changedKeys := []string{}
+	for _, key := range winrmhelper.KerberosDelegationKeys {
+		if d.HasChange(key) {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	delegation := winrmhelper.NewKerberosDelegationFromResource(d)
+	err = delegation.Apply(meta.(*config.ProviderConf), "Set-ADUser", d.Id(), changedKeys)
+	if err != nil {
+		return fmt.Errorf("while configuring kerberos delegation for user %q: %s", d.Id(), err)
+	}
 	return resourceADUserRead(d, meta)
 }
 
@@ -209,6 +209,53 @@ func TestAccResourceADUser_modify(t *testing.T) {
 	})
 }
 
+func TestAccResourceADUser_protected(t *testing.T) {
+
+	envVars := []string{
+		"TF_VAR_ad_user_container",
+		"TF_VAR_ad_domain_name",
+	}
+
+	container := os.Getenv("TF_VAR_ad_user_container")
+	domain := os.Getenv("TF_VAR_ad_domain_name")
+	sam := testAccRandomSAM()
+	displayName := testAccRandomName("tfacc-user")
+	password := testAccRandomPassword()
+	principalName := testAccRandomPrincipalName(domain)
+	ouName := testAccRandomName("tfacc-ou")
+	resourceName := "windowsad_user.a"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t, envVars) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccResourceADUserExists(resourceName, sam, false),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceADUserConfigProtectedRandom(sam, displayName, password, principalName, container, ouName, "var.container", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "true"),
+				),
+			},
+			{
+				// a protected user can only be moved if the protection is lifted first
+				Config: testAccResourceADUserConfigProtectedRandom(sam, displayName, password, principalName, container, ouName, "windowsad_ou.o.dn", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceADUserContainer(resourceName, fmt.Sprintf("OU=%s,%s", ouName, container)),
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "true"),
+				),
+			},
+			{
+				Config: testAccResourceADUserConfigProtectedRandom(sam, displayName, password, principalName, container, ouName, "windowsad_ou.o.dn", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "protected_from_accidental_deletion", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceADUser_UAC(t *testing.T) {
 
 	envVars := []string{
@@ -348,6 +395,27 @@ resource "windowsad_user" "a" {
 `, sam, displayName, password, principalName, parentContainer, ouName)
 }
 
+func testAccResourceADUserConfigProtectedRandom(sam, displayName, password, principalName, parentContainer, ouName, container string, protected bool) string {
+	return fmt.Sprintf(`
+variable "container" { default = %[5]q }
+
+resource "windowsad_ou" "o" {
+  name      = %[6]q
+  path      = var.container
+  protected = false
+}
+
+resource "windowsad_user" "a" {
+  sam_account_name                   = %[1]q
+  display_name                       = %[2]q
+  initial_password                   = %[3]q
+  principal_name                     = %[4]q
+  container                          = %[7]s
+  protected_from_accidental_deletion = %[8]t
+}
+`, sam, displayName, password, principalName, parentContainer, ouName, container, protected)
+}
+
 // testAccResourceADUserConfigUACRandom generates a user config with UAC flags.
 func testAccResourceADUserConfigUACRandom(sam, displayName, password, principalName, container, enabled, passwordNeverExpires string) string {
 	return fmt.Sprintf(`